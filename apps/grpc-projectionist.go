@@ -2,6 +2,7 @@ package apps
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"fmt"
 	"github.com/dgraph-io/badger/v2"
@@ -13,19 +14,28 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/reflection"
 
 	projGrpc "projectionist/apps/grpc"
 	"projectionist/config"
 	"projectionist/consts"
+	"projectionist/controllers"
 	"projectionist/middleware"
 	projPB "projectionist/proto"
 	"projectionist/provider"
 )
 
 // RunGRPC - started grpc server
-func RunGRPC(cfg *config.Config, sqlDB *sql.DB, badgerDB *badger.DB) {
+func RunGRPC(
+	cfg *config.Config,
+	sqlDB *sql.DB,
+	badgerDB *badger.DB,
+	checker controllers.Checker,
+	agents projGrpc.AgentRegistry,
+	syncChan chan string,
+) {
 	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Host, cfg.GrpcPort))
 	if err != nil {
 		grpclog.Fatalf("listen error: %v", err)
@@ -44,9 +54,16 @@ func RunGRPC(cfg *config.Config, sqlDB *sql.DB, badgerDB *badger.DB) {
 			func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 				return nil
 			}),
-		grpc.UnaryInterceptor(middleware.ServerInterceptor(cfg)),
+		grpc.UnaryInterceptor(middleware.ServerInterceptor(cfg, sqlDB)),
 		grpc.ConnectionTimeout(3 * time.Second),
 	}
+	if cfg.Agents.CertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.Agents.CertFile, cfg.Agents.KeyFile)
+		if err != nil {
+			grpclog.Fatalf("grpc tls error: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
 	grpcServer := grpc.NewServer(opts...)
 	defer grpcServer.GracefulStop()
 	projPB.RegisterProjectionistServiceServer(
@@ -55,6 +72,9 @@ func RunGRPC(cfg *config.Config, sqlDB *sql.DB, badgerDB *badger.DB) {
 			cfgProvider,
 			dbProvider,
 			cfg,
+			checker,
+			agents,
+			syncChan,
 		))
 	reflection.Register(grpcServer)
 	grpclog.Infof("start listen grpc server: %s:%d", cfg.Host, cfg.GrpcPort)
@@ -85,9 +105,14 @@ func RunGrpcApi(cfg *config.Config) {
 		marshalOptionJson,
 	)
 
-	opts := []grpc.DialOption{grpc.WithInsecure()}
+	dialOption, err := gatewayDialOption(cfg)
+	if err != nil {
+		grpclog.Errorf("gateway dial option error: %v", err)
+		return
+	}
+	opts := []grpc.DialOption{dialOption}
 
-	err := projPB.RegisterProjectionistServiceHandlerFromEndpoint(
+	err = projPB.RegisterProjectionistServiceHandlerFromEndpoint(
 		ctx,
 		gwmux,
 		grpcServerEndpoint,
@@ -121,3 +146,41 @@ func RunGrpcApi(cfg *config.Config) {
 		return
 	}
 }
+
+// AgentDialOption - transport security of agent connection to grpc server, server certificate is verified
+// by agents CA file or system roots, plaintext is used only if agents insecure is enabled
+func AgentDialOption(cfg *config.Config) (grpc.DialOption, error) {
+	if cfg.Agents.Insecure {
+		return grpc.WithInsecure(), nil
+	}
+
+	return tlsDialOption(cfg.Agents.CAFile)
+}
+
+// gatewayDialOption - gateway connects by TLS if grpc server has certificate, the certificate itself
+// is trusted if there is no CA file
+func gatewayDialOption(cfg *config.Config) (grpc.DialOption, error) {
+	if cfg.Agents.CertFile == "" {
+		return grpc.WithInsecure(), nil
+	}
+
+	if cfg.Agents.CAFile != "" {
+		return tlsDialOption(cfg.Agents.CAFile)
+	}
+
+	return tlsDialOption(cfg.Agents.CertFile)
+}
+
+// tlsDialOption - TLS verified by certificates of CA file or by system roots if it is empty
+func tlsDialOption(caFile string) (grpc.DialOption, error) {
+	if caFile == "" {
+		return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})), nil
+	}
+
+	creds, err := credentials.NewClientTLSFromFile(caFile, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.WithTransportCredentials(creds), nil
+}
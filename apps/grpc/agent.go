@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	"projectionist/consts"
+	"projectionist/models"
+	projProto "projectionist/proto"
+)
+
+// AgentRegistry - accepts remote health check agents and their check results
+type AgentRegistry interface {
+	RegisterAgent(name string) int64
+	AgentServices(agentID int64) ([]*models.Service, error)
+	Report(ctx context.Context, agentID int64, result *models.CheckResult) error
+}
+
+func (p *ProjectionistServer) AgentRegister(
+	ctx context.Context,
+	r *projProto.AgentRegisterRequest,
+) (*projProto.AgentRegisterResponse, error) {
+	var respond = &projProto.AgentRegisterResponse{Meta: &projProto.DefaultResponse{}}
+	if strings.TrimSpace(r.Name) == "" {
+		return respond, errors.New(consts.NameIsEmptyResp)
+	}
+
+	respond.AgentId = p.agents.RegisterAgent(r.Name)
+	respond.Meta.Status = true
+	respond.Meta.Message = "agent registered"
+	return respond, nil
+}
+
+func (p *ProjectionistServer) AgentServices(
+	ctx context.Context,
+	r *projProto.AgentRequest,
+) (*projProto.AgentServicesResponse, error) {
+	var respond = &projProto.AgentServicesResponse{Meta: &projProto.DefaultResponse{}}
+
+	services, err := p.agents.AgentServices(r.AgentId)
+	if err != nil {
+		if err == consts.ErrUnknownAgent {
+			return respond, errors.New(consts.UnknownAgentResp)
+		}
+		grpclog.Errorf("agents.AgentServices(%d) error: %v", r.AgentId, err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	respond.Services = make([]*projProto.AgentService, 0, len(services))
+	for _, service := range services {
+		respond.Services = append(respond.Services, &projProto.AgentService{
+			Id:           int64(service.ID),
+			Name:         service.Name,
+			Link:         service.Link,
+			Token:        service.Token,
+			Frequency:    int64(service.Frequency),
+			Timeout:      int64(service.Timeout),
+			Retries:      int64(service.Retries),
+			RetryBackoff: int64(service.RetryBackoff),
+		})
+	}
+
+	respond.Meta.Status = true
+	return respond, nil
+}
+
+func (p *ProjectionistServer) AgentReport(
+	ctx context.Context,
+	r *projProto.AgentReportRequest,
+) (*projProto.DefaultResponse, error) {
+	var respond = &projProto.DefaultResponse{}
+	if r.Result == nil || r.Result.ServiceId <= 0 {
+		return respond, errors.New(consts.BadInputDataResp)
+	}
+
+	err := p.agents.Report(ctx, r.AgentId, checkResultFromProto(r.Result))
+	if err != nil {
+		if err == consts.ErrUnknownAgent {
+			return respond, errors.New(consts.UnknownAgentResp)
+		}
+		if err == sql.ErrNoRows {
+			return respond, errors.New(consts.NotExistResp)
+		}
+		grpclog.Errorf("agents.Report(%d) error: %v", r.AgentId, err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	respond.Status = true
+	respond.Message = "report accepted"
+	return respond, nil
+}
+
+func checkResultFromProto(pResult *projProto.CheckResult) *models.CheckResult {
+	var result = &models.CheckResult{
+		ServiceID: int(pResult.ServiceId),
+		Status:    models.CheckStatus(pResult.Status),
+		Error:     pResult.Error,
+		Attempts:  int(pResult.Attempts),
+		Duration:  time.Duration(pResult.DurationMs) * time.Millisecond,
+		CheckedAt: time.Unix(pResult.CheckedAt, 0),
+	}
+
+	if pResult.CertExpiresAt > 0 {
+		certExpiresAt := time.Unix(pResult.CertExpiresAt, 0)
+		result.CertExpiresAt = &certExpiresAt
+	}
+
+	return result
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"github.com/dgrijalva/jwt-go"
-	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/grpclog"
-	"log"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
 	"projectionist/consts"
 	"projectionist/models"
 	projProto "projectionist/proto"
+	"projectionist/validate"
+	"strings"
+	"time"
 )
 
 func (p *ProjectionistServer) Login(ctx context.Context, r *projProto.LoginRequest) (*projProto.LoginResponse, error) {
@@ -21,53 +26,271 @@ func (p *ProjectionistServer) Login(ctx context.Context, r *projProto.LoginReque
 		return respond, errors.New(consts.InputDataInvalidResp)
 	}
 
-	var iUser models.Model
-	var user = &models.User{}
+	iDB, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		grpclog.Errorf("database empty in db provider")
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
 
-	iUser, err = p.dbProvider.GetByName(user, r.Username)
+	user, err := validate.Login(iDB, p.cfg, r.Username, r.Password, p.clientIP(ctx), time.Now())
 	if err != nil {
-		if err == sql.ErrNoRows {
-			grpclog.Error("dbProvider.GetByName with username %s error: %v", r.Username, err)
-			return respond, errors.New(consts.NotExistResp)
-		}
-		grpclog.Errorf("dbProvider.GetByName error: %v", err)
-		respond.Meta.Message = consts.SmtWhenWrongResp
-		respond.Meta.Status = false
+		grpclog.Errorf("login with username %s error: %v", r.Username, err)
+		return respond, loginError(err)
+	}
+
+	enabled, err := validate.TwoFactorEnabled(iDB, user.ID)
+	if err != nil {
+		grpclog.Errorf("validate.TwoFactorEnabled error: %v", err)
 		return respond, errors.New(consts.SmtWhenWrongResp)
 	}
 
-	user, ok := iUser.(*models.User)
-	if !ok {
-		grpclog.Errorf("LoginApi() error: iUser is not User model")
-		respond.Meta.Message = consts.SmtWhenWrongResp
-		respond.Meta.Status = false
+	if enabled {
+		respond.TwoFactorToken, err = validate.NewTwoFactorToken(p.cfg, user, time.Now())
+		if err != nil {
+			grpclog.Errorf("validate.NewTwoFactorToken() error: %v", err)
+			return respond, errors.New("Authorization failed")
+		}
+
+		respond.TwoFactorRequired = true
+		respond.Meta.Message = "Two-factor code required"
+		respond.Meta.Status = true
+		return respond, nil
+	}
+
+	tokens, err := validate.NewSession(iDB, p.cfg, user, time.Now())
+	if err != nil {
+		grpclog.Errorf("LoginApi() validate.NewSession() error: %v", err)
+		return respond, errors.New("Authorization failed")
+	}
+
+	grpclog.Infof("Login successful for %v", user.Username)
+
+	respond.Meta.Message = "Login successful"
+	setTokens(respond, user, tokens)
+	respond.Meta.Status = true
+	return respond, nil
+}
+
+// LoginTwoFactor - second login step of user with two-factor authentication
+func (p *ProjectionistServer) LoginTwoFactor(ctx context.Context, r *projProto.LoginTwoFactorRequest) (*projProto.LoginResponse, error) {
+	var respond = &projProto.LoginResponse{Meta: &projProto.DefaultResponse{}}
+	err := r.Validate()
+	if err != nil {
+		grpclog.Errorf("LoginTwoFactorRequest.Validate error: %v", err)
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	iDB, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		grpclog.Errorf("database empty in db provider")
 		return respond, errors.New(consts.SmtWhenWrongResp)
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(r.Password)); err != nil {
-		log.Printf("LoginApi() bcrypt.CompareHashAndPassword() error: %v", err)
-		return respond, errors.New("Not authorized")
+	user, err := validate.LoginTwoFactor(iDB, p.cfg, r.Token, r.Code, p.clientIP(ctx), time.Now())
+	if err != nil {
+		grpclog.Errorf("validate.LoginTwoFactor error: %v", err)
+		return respond, loginError(err)
 	}
 
-	var tokenM = &models.Token{UserId: uint64(user.ID)}
-	var token = jwt.NewWithClaims(jwt.GetSigningMethod(jwt.SigningMethodHS256.Name), tokenM)
-	tokenStr, err := token.SignedString([]byte(p.cfg.TokenSecretKey))
+	tokens, err := validate.NewSession(iDB, p.cfg, user, time.Now())
 	if err != nil {
-		grpclog.Errorf("LoginApi() token.SignedString() error: %v", err)
+		grpclog.Errorf("validate.NewSession() error: %v", err)
 		return respond, errors.New("Authorization failed")
 	}
 
 	grpclog.Infof("Login successful for %v", user.Username)
 
 	respond.Meta.Message = "Login successful"
+	setTokens(respond, user, tokens)
+	respond.Meta.Status = true
+	return respond, nil
+}
+
+// Refresh - new access token and refresh token by refresh token of active session
+func (p *ProjectionistServer) Refresh(ctx context.Context, r *projProto.RefreshRequest) (*projProto.LoginResponse, error) {
+	var respond = &projProto.LoginResponse{Meta: &projProto.DefaultResponse{}}
+	err := r.Validate()
+	if err != nil {
+		grpclog.Errorf("RefreshRequest.Validate error: %v", err)
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	iDB, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		grpclog.Errorf("database empty in db provider")
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	tokens, user, err := validate.RefreshSession(iDB, p.cfg, r.RefreshToken, time.Now())
+	if err != nil {
+		if err == validate.ErrSessionInvalid {
+			return respond, errors.New(consts.SessionInvalidResp)
+		}
+		grpclog.Errorf("validate.RefreshSession() error: %v", err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	respond.Meta.Message = "Token refreshed"
+	setTokens(respond, user, tokens)
+	respond.Meta.Status = true
+	return respond, nil
+}
+
+// Logout - revoke session of request access token with its refresh token
+func (p *ProjectionistServer) Logout(ctx context.Context, r *projProto.LogoutRequest) (*projProto.DefaultResponse, error) {
+	var respond = &projProto.DefaultResponse{}
+	token := models.TokenFromContext(ctx)
+	if token == nil {
+		return respond, errors.New("Not authorized")
+	}
+
+	iDB, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		grpclog.Errorf("database empty in db provider")
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	err := validate.Logout(iDB, token, time.Now())
+	if err != nil {
+		if err == validate.ErrSessionInvalid {
+			return respond, errors.New(consts.SessionInvalidResp)
+		}
+		grpclog.Errorf("validate.Logout() error: %v", err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	respond.Message = "Logout successful"
+	respond.Status = true
+	return respond, nil
+}
+
+// ChangePassword - replace password of request user, other sessions are revoked and new tokens are responded
+func (p *ProjectionistServer) ChangePassword(ctx context.Context, r *projProto.ChangePasswordRequest) (*projProto.LoginResponse, error) {
+	var respond = &projProto.LoginResponse{Meta: &projProto.DefaultResponse{}}
+	token := models.TokenFromContext(ctx)
+	if token == nil || token.APIKeyID != 0 {
+		return respond, status.Error(codes.PermissionDenied, consts.PermissionDeniedResp)
+	}
+
+	err := r.Validate()
+	if err != nil {
+		grpclog.Errorf("ChangePasswordRequest.Validate error: %v", err)
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	iDB, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		grpclog.Errorf("database empty in db provider")
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	var now = time.Now()
+	user, err := validate.ChangePassword(iDB, p.cfg, int(token.UserId), r.CurrentPassword, r.NewPassword, p.clientIP(ctx), now)
+	if err != nil {
+		if policyErr, ok := err.(*validate.PasswordPolicyError); ok {
+			return respond, status.Errorf(codes.InvalidArgument, "%s: %s", consts.PasswordPolicyResp, strings.Join(policyErr.Violations, ", "))
+		}
+		if err == validate.ErrPasswordExternal {
+			return respond, status.Error(codes.FailedPrecondition, consts.PasswordExternalResp)
+		}
+		grpclog.Errorf("validate.ChangePassword() error: %v", err)
+		return respond, loginError(err)
+	}
+
+	tokens, err := validate.NewSession(iDB, p.cfg, user, now)
+	if err != nil {
+		grpclog.Errorf("validate.NewSession() error: %v", err)
+		return respond, errors.New("Authorization failed")
+	}
+
+	respond.Meta.Message = "Password changed"
+	setTokens(respond, user, tokens)
+	respond.Meta.Status = true
+	return respond, nil
+}
+
+func setTokens(respond *projProto.LoginResponse, user *models.User, tokens *validate.Tokens) {
 	respond.User = &projProto.User{
 		Id:       int64(user.ID),
 		Username: user.Username,
 		Password: "",
 		Role:     projProto.UserRole(user.Role),
-		Token:    tokenStr,
+		Token:    tokens.AccessToken,
 		Deleted:  projProto.Deleted(user.Deleted),
 	}
-	respond.Meta.Status = true
-	return respond, nil
+	respond.RefreshToken = tokens.RefreshToken
+	respond.ExpiresAt = tokens.ExpiresAt.Unix()
+	respond.PasswordChangeRequired = user.PasswordChangeRequired
+}
+
+// forwardedForKey - metadata with client address set by grpc-gateway from address of http request
+const forwardedForKey = "x-forwarded-for"
+
+// clientIP - address of client without port, requests of local grpc-gateway have address of http client
+func (p *ProjectionistServer) clientIP(ctx context.Context) string {
+	var host = peerIP(ctx)
+	if !p.isGateway(host) {
+		return host
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return host
+	}
+
+	// gateway appends address of http client after addresses forwarded by client itself
+	var forwarded = md.Get(forwardedForKey)
+	if len(forwarded) == 0 {
+		return host
+	}
+
+	var addresses = strings.Split(forwarded[len(forwarded)-1], ",")
+	if ip := net.ParseIP(strings.TrimSpace(addresses[len(addresses)-1])); ip != nil {
+		return ip.String()
+	}
+
+	return host
+}
+
+// isGateway - peer is grpc-gateway of this server, it connects from loopback or configured host
+func (p *ProjectionistServer) isGateway(host string) bool {
+	var ip = net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	return ip.IsLoopback() || ip.Equal(net.ParseIP(p.cfg.Host))
+}
+
+// peerIP - address of peer without port
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// loginError - error of rejected login attempt
+func loginError(err error) error {
+	if throttled, ok := err.(*validate.ThrottledError); ok {
+		return status.Errorf(codes.ResourceExhausted, "%s, retry after %v", consts.LoginThrottledResp, throttled.RetryAfter.Round(time.Second))
+	}
+
+	switch err {
+	case sql.ErrNoRows:
+		return errors.New(consts.NotExistResp)
+	case validate.ErrNotAuthorized:
+		return errors.New("Not authorized")
+	case validate.ErrTwoFactorCode:
+		return errors.New(consts.TwoFactorCodeResp)
+	default:
+		return errors.New(consts.SmtWhenWrongResp)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"projectionist/config"
+	"projectionist/controllers"
 	projProto "projectionist/proto"
 	"projectionist/provider"
 )
@@ -12,13 +13,25 @@ type ProjectionistServer struct {
 	cfg         *config.Config
 	dbProvider  provider.IDBProvider
 	cfgProvider provider.IDBProvider
+	checker     controllers.Checker
+	agents      AgentRegistry
+	syncChan    chan string
 }
 
-func NewProjectionistServer(cfgProvider, dbProvider provider.IDBProvider, cfg *config.Config) *ProjectionistServer {
+func NewProjectionistServer(
+	cfgProvider, dbProvider provider.IDBProvider,
+	cfg *config.Config,
+	checker controllers.Checker,
+	agents AgentRegistry,
+	syncChan chan string,
+) *ProjectionistServer {
 	return &ProjectionistServer{
 		cfg:         cfg,
 		dbProvider:  dbProvider,
 		cfgProvider: cfgProvider,
+		checker:     checker,
+		agents:      agents,
+		syncChan:    syncChan,
 	}
 }
 
@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	"projectionist/consts"
+	"projectionist/models"
+	projProto "projectionist/proto"
+	"projectionist/utils"
+)
+
+func (p *ProjectionistServer) GetIncidentList(
+	ctx context.Context,
+	r *projProto.IncidentListRequest,
+) (*projProto.IncidentListResponse, error) {
+	var respond = &projProto.IncidentListResponse{Meta: &projProto.DefaultResponse{}}
+	if r.Page < 0 || r.Count <= 0 {
+		return respond, errors.New(consts.PageAndCountRequiredResp)
+	}
+
+	var filter = models.IncidentFilter{State: r.State, ServiceID: int(r.ServiceId)}
+	err := filter.Validate()
+	if err != nil {
+		grpclog.Errorf("incidents filter validate error: %v", err)
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	iDB, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		grpclog.Errorf("database empty in db provider")
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	total, err := models.CountIncidents(iDB, filter)
+	if err != nil {
+		grpclog.Errorf("models.CountIncidents() error: %v", err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	if r.Page > 0 {
+		start, _ := utils.Pagination(int(r.Page), int(r.Count))
+		incidents, err := models.GetIncidents(iDB, filter, start, int(r.Count))
+		if err != nil {
+			grpclog.Errorf("models.GetIncidents() error: %v", err)
+			return respond, errors.New(consts.SmtWhenWrongResp)
+		}
+
+		for _, incident := range incidents {
+			respond.Incidents = append(respond.Incidents, incidentToProto(incident))
+		}
+	}
+
+	respond.Meta.Status = true
+	respond.Total = int64(total)
+	return respond, nil
+}
+
+func (p *ProjectionistServer) GetIncident(
+	ctx context.Context,
+	r *projProto.IncidentRequest,
+) (*projProto.IncidentResponse, error) {
+	var respond = &projProto.IncidentResponse{Meta: &projProto.DefaultResponse{}}
+
+	incident, err := p.getIncident(r.Id)
+	if err != nil {
+		return respond, err
+	}
+
+	respond.Meta.Status = true
+	respond.Incident = incidentToProto(incident)
+	return respond, nil
+}
+
+func (p *ProjectionistServer) AcknowledgeIncident(
+	ctx context.Context,
+	r *projProto.IncidentRequest,
+) (*projProto.IncidentResponse, error) {
+	var respond = &projProto.IncidentResponse{Meta: &projProto.DefaultResponse{}}
+
+	incident, err := p.getIncident(r.Id)
+	if err != nil {
+		return respond, err
+	}
+
+	err = incident.Acknowledge(p.dbProvider.GetDB().(*sql.DB), contextUserID(ctx), time.Now())
+	if err != nil {
+		if err == models.ErrIncidentAcknowledged {
+			return respond, errors.New(consts.AlreadyAcknowledgedResp)
+		}
+		grpclog.Errorf("incident %d Acknowledge() error: %v", incident.ID, err)
+		return respond, errors.New(consts.NotUpdatedResp)
+	}
+
+	respond.Meta.Status = true
+	respond.Meta.Message = "incident acknowledged"
+	respond.Incident = incidentToProto(incident)
+	return respond, nil
+}
+
+func (p *ProjectionistServer) AddIncidentNote(
+	ctx context.Context,
+	r *projProto.IncidentNoteRequest,
+) (*projProto.IncidentResponse, error) {
+	var respond = &projProto.IncidentResponse{Meta: &projProto.DefaultResponse{}}
+	if strings.TrimSpace(r.Text) == "" {
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	incident, err := p.getIncident(r.Id)
+	if err != nil {
+		return respond, err
+	}
+
+	err = incident.AddNote(p.dbProvider.GetDB().(*sql.DB), &models.IncidentNote{
+		UserID: contextUserID(ctx),
+		Text:   r.Text,
+	})
+	if err != nil {
+		grpclog.Errorf("incident %d AddNote() error: %v", incident.ID, err)
+		return respond, errors.New(consts.NotSavedResp)
+	}
+
+	respond.Meta.Status = true
+	respond.Meta.Message = "incident note added"
+	respond.Incident = incidentToProto(incident)
+	return respond, nil
+}
+
+func (p *ProjectionistServer) getIncident(id int64) (*models.Incident, error) {
+	if id <= 0 {
+		return nil, errors.New(consts.InputDataInvalidResp)
+	}
+
+	iIncident, err := p.dbProvider.GetByID(&models.Incident{}, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(consts.NotExistResp)
+		}
+		grpclog.Errorf("dbProvider.GetByID incident error: %v", err)
+		return nil, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	return iIncident.(*models.Incident), nil
+}
+
+// contextUserID - id of authorized user, 0 if request is not authorized
+func contextUserID(ctx context.Context) int {
+	token := models.TokenFromContext(ctx)
+	if token == nil {
+		return 0
+	}
+
+	return int(token.UserId)
+}
+
+func incidentToProto(incident *models.Incident) *projProto.Incident {
+	var pIncident = &projProto.Incident{
+		Id:             int64(incident.ID),
+		ServiceId:      int64(incident.ServiceID),
+		Reason:         incident.Reason,
+		StartedAt:      incident.StartedAt.Unix(),
+		Duration:       int64(incident.Duration),
+		AcknowledgedBy: int64(incident.AcknowledgedBy),
+	}
+
+	if incident.EndedAt != nil {
+		pIncident.EndedAt = incident.EndedAt.Unix()
+	}
+
+	if incident.AcknowledgedAt != nil {
+		pIncident.AcknowledgedAt = incident.AcknowledgedAt.Unix()
+	}
+
+	for _, note := range incident.Notes {
+		pIncident.Notes = append(pIncident.Notes, &projProto.IncidentNote{
+			Id:         int64(note.ID),
+			IncidentId: int64(note.IncidentID),
+			UserId:     int64(note.UserID),
+			Text:       note.Text,
+			CreatedAt:  note.CreatedAt.Unix(),
+		})
+	}
+
+	return pIncident
+}
@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	"projectionist/consts"
+	"projectionist/models"
+	projProto "projectionist/proto"
+)
+
+func (p *ProjectionistServer) NewMaintenance(
+	ctx context.Context,
+	r *projProto.MaintenanceRequest,
+) (*projProto.MaintenanceResponse, error) {
+	var respond = &projProto.MaintenanceResponse{Meta: &projProto.DefaultResponse{}}
+	if r.Maintenance == nil {
+		return respond, errors.New(consts.BadInputDataResp)
+	}
+
+	var window = maintenanceFromProto(r.Maintenance)
+	window.ID = 0
+	window.ServiceID = int(r.ServiceId)
+
+	err := window.Validate()
+	if err != nil {
+		grpclog.Errorf("NewMaintenance validate error: %v", err)
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	iService, err := p.dbProvider.GetByID(&models.Service{}, r.ServiceId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respond, errors.New(consts.NotExistResp)
+		}
+		grpclog.Errorf("dbProvider.GetByID service error: %v", err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	if iService.IsDeleted() {
+		return respond, errors.New(consts.NotExistResp)
+	}
+
+	err = p.dbProvider.Save(window)
+	if err != nil {
+		grpclog.Errorf("NewMaintenance save error: %v", err)
+		return respond, errors.New(consts.NotSavedResp)
+	}
+
+	respond.Meta.Status = true
+	respond.Meta.Message = "New maintenance created"
+	respond.MaintenanceId = int64(window.ID)
+	return respond, nil
+}
+
+func (p *ProjectionistServer) GetMaintenanceList(
+	ctx context.Context,
+	r *projProto.MaintenanceListRequest,
+) (*projProto.MaintenanceListResponse, error) {
+	var respond = &projProto.MaintenanceListResponse{Meta: &projProto.DefaultResponse{}}
+
+	db, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || db == nil {
+		grpclog.Errorf("database empty in db provider")
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	var service = &models.Service{ID: int(r.ServiceId)}
+	windows, err := service.GetMaintenanceWindows(db)
+	if err != nil {
+		grpclog.Errorf("GetMaintenanceWindows error: %v", err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	for i := range windows {
+		respond.Maintenance = append(respond.Maintenance, maintenanceToProto(&windows[i]))
+	}
+
+	respond.Meta.Status = true
+	return respond, nil
+}
+
+func (p *ProjectionistServer) DeleteMaintenance(
+	ctx context.Context,
+	r *projProto.MaintenanceDeleteRequest,
+) (*projProto.DefaultResponse, error) {
+	var respond = &projProto.DefaultResponse{}
+
+	iWindow, err := p.dbProvider.GetByID(&models.MaintenanceWindow{}, r.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respond, errors.New(consts.NotExistResp)
+		}
+		grpclog.Errorf("dbProvider.GetByID maintenance error: %v", err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	if int64(iWindow.(*models.MaintenanceWindow).ServiceID) != r.ServiceId {
+		return respond, errors.New(consts.NotExistResp)
+	}
+
+	err = p.dbProvider.Delete(&models.MaintenanceWindow{}, int(r.Id))
+	if err != nil {
+		grpclog.Errorf("dbProvider.Delete maintenance error: %v", err)
+		return respond, errors.New(consts.NotDeletedResp)
+	}
+
+	respond.Status = true
+	respond.Message = "maintenance deleted"
+	return respond, nil
+}
+
+func maintenanceFromProto(m *projProto.Maintenance) *models.MaintenanceWindow {
+	var window = &models.MaintenanceWindow{
+		ID:        int(m.Id),
+		ServiceID: int(m.ServiceId),
+		Name:      m.Name,
+		Mode:      models.MaintenanceMode(m.Mode),
+		Cron:      m.Cron,
+		Duration:  int(m.Duration),
+	}
+
+	if m.StartsAt > 0 {
+		startsAt := time.Unix(m.StartsAt, 0)
+		window.StartsAt = &startsAt
+	}
+
+	if m.EndsAt > 0 {
+		endsAt := time.Unix(m.EndsAt, 0)
+		window.EndsAt = &endsAt
+	}
+
+	return window
+}
+
+func maintenanceToProto(window *models.MaintenanceWindow) *projProto.Maintenance {
+	var m = &projProto.Maintenance{
+		Id:        int64(window.ID),
+		ServiceId: int64(window.ServiceID),
+		Name:      window.Name,
+		Mode:      projProto.MaintenanceMode(window.Mode),
+		Cron:      window.Cron,
+		Duration:  int64(window.Duration),
+	}
+
+	if window.StartsAt != nil {
+		m.StartsAt = window.StartsAt.Unix()
+	}
+
+	if window.EndsAt != nil {
+		m.EndsAt = window.EndsAt.Unix()
+	}
+
+	return m
+}
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	"projectionist/consts"
+	"projectionist/models"
+	projProto "projectionist/proto"
+)
+
+func (p *ProjectionistServer) CheckService(
+	ctx context.Context,
+	r *projProto.ServiceIdRequest,
+) (*projProto.CheckResponse, error) {
+	var respond = &projProto.CheckResponse{Meta: &projProto.DefaultResponse{}}
+	if r.Id <= 0 {
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	result, err := p.checker.CheckNow(ctx, int(r.Id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respond, errors.New(consts.NotExistResp)
+		}
+		grpclog.Errorf("checker.CheckNow() service %d error: %v", r.Id, err)
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	respond.Meta.Status = true
+	respond.Meta.Message = "service checked"
+	respond.Result = checkResultToProto(result)
+	return respond, nil
+}
+
+func (p *ProjectionistServer) PauseService(
+	ctx context.Context,
+	r *projProto.ServiceIdRequest,
+) (*projProto.DefaultResponse, error) {
+	return p.setServicePaused(r.Id, true, "service paused")
+}
+
+func (p *ProjectionistServer) ResumeService(
+	ctx context.Context,
+	r *projProto.ServiceIdRequest,
+) (*projProto.DefaultResponse, error) {
+	return p.setServicePaused(r.Id, false, "service resumed")
+}
+
+func (p *ProjectionistServer) setServicePaused(id int64, paused bool, message string) (*projProto.DefaultResponse, error) {
+	var respond = &projProto.DefaultResponse{}
+	if id <= 0 {
+		return respond, errors.New(consts.InputDataInvalidResp)
+	}
+
+	iDB, ok := p.dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		grpclog.Errorf("database empty in db provider")
+		return respond, errors.New(consts.SmtWhenWrongResp)
+	}
+
+	var service = &models.Service{}
+	err := service.GetByID(iDB, id)
+	if err == nil && service.IsDeleted() {
+		err = sql.ErrNoRows
+	}
+
+	if err == nil {
+		err = service.SetPaused(iDB, paused)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return respond, errors.New(consts.NotExistResp)
+		}
+		grpclog.Errorf("service %d SetPaused(%v) error: %v", id, paused, err)
+		return respond, errors.New(consts.NotUpdatedResp)
+	}
+
+	p.syncChan <- service.Name
+
+	respond.Status = true
+	respond.Message = message
+	return respond, nil
+}
+
+func checkResultToProto(result *models.CheckResult) *projProto.CheckResult {
+	var pResult = &projProto.CheckResult{
+		ServiceId:  int64(result.ServiceID),
+		Status:     projProto.CheckStatus(result.Status),
+		Error:      result.Error,
+		Attempts:   int32(result.Attempts),
+		DurationMs: int64(result.Duration / time.Millisecond),
+		CheckedAt:  result.CheckedAt.Unix(),
+	}
+
+	if result.CertExpiresAt != nil {
+		pResult.CertExpiresAt = result.CertExpiresAt.Unix()
+	}
+
+	return pResult
+}
@@ -0,0 +1,248 @@
+package healtchecker
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"projectionist/config"
+	"projectionist/consts"
+	"projectionist/models"
+	projProto "projectionist/proto"
+)
+
+// Agent - remote health checker, checks services assigned by main server and reports results to it
+type Agent struct {
+	name    string
+	cfg     *config.Config
+	client  projProto.ProjectionistServiceClient
+	prober  *HealthCheck // used only for service probes
+	crontab *cron.Cron
+	sync.Mutex
+	id      int64               // agent id given by server, 0 if agent is not registered
+	planned map[int]*agentEntry // map[service id]planned service
+	slots   chan struct{}       // bounds count of running probes
+	running sync.WaitGroup
+}
+
+type agentEntry struct {
+	service models.Service
+	entryID cron.EntryID
+}
+
+func NewAgent(cfg *config.Config, name string, client projProto.ProjectionistServiceClient) *Agent {
+	return &Agent{
+		name:    name,
+		cfg:     cfg,
+		client:  client,
+		prober:  NewHealthCkeck(cfg, nil, nil),
+		crontab: cron.New(),
+		planned: make(map[int]*agentEntry),
+		slots:   make(chan struct{}, cfg.HealthCheck.Workers),
+	}
+}
+
+// Run - sync assigned services with server every sync interval until ctx is done
+func (a *Agent) Run(ctx context.Context) error {
+	grpclog.Infof("health-check agent %s: started, server %s", a.name, a.cfg.Agents.Server)
+
+	a.crontab.Start()
+
+	ticker := time.NewTicker(time.Duration(a.cfg.Agents.SyncInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		err := a.sync(ctx)
+		if err != nil && ctx.Err() == nil {
+			grpclog.Errorf("health-check agent %s: sync error: %v", a.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop - stop planned checks and wait for running checks, ctx of Run must be done before
+func (a *Agent) Stop() {
+	<-a.crontab.Stop().Done()
+	a.running.Wait()
+	grpclog.Infof("health-check agent %s: stopped", a.name)
+}
+
+// sync - register agent if needed, request assigned services and replan them
+func (a *Agent) sync(ctx context.Context) error {
+	agentID, err := a.register(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.AgentServices(a.authContext(ctx), &projProto.AgentRequest{AgentId: agentID})
+	if err != nil {
+		a.forget(err)
+		return err
+	}
+
+	var services = make([]models.Service, 0, len(resp.Services))
+	for _, pService := range resp.Services {
+		services = append(services, models.Service{
+			ID:           int(pService.Id),
+			Name:         pService.Name,
+			Link:         pService.Link,
+			Token:        pService.Token,
+			Frequency:    int(pService.Frequency),
+			Timeout:      int(pService.Timeout),
+			Retries:      int(pService.Retries),
+			RetryBackoff: int(pService.RetryBackoff),
+		})
+	}
+
+	a.replan(ctx, services)
+
+	return nil
+}
+
+// register - register agent on server if it is not registered yet
+func (a *Agent) register(ctx context.Context) (int64, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.id != 0 {
+		return a.id, nil
+	}
+
+	resp, err := a.client.AgentRegister(a.authContext(ctx), &projProto.AgentRegisterRequest{Name: a.name})
+	if err != nil {
+		return 0, err
+	}
+
+	a.id = resp.AgentId
+	grpclog.Infof("health-check agent %s: registered with id %d", a.name, a.id)
+
+	return a.id, nil
+}
+
+// forget - reset agent id if server does not know agent, agent is registered again on next sync
+func (a *Agent) forget(err error) {
+	if status.Convert(err).Message() != consts.UnknownAgentResp {
+		return
+	}
+
+	a.Lock()
+	a.id = 0
+	a.Unlock()
+}
+
+// replan - plan new and changed services, remove services not assigned anymore
+func (a *Agent) replan(ctx context.Context, services []models.Service) {
+	a.Lock()
+	defer a.Unlock()
+
+	var assigned = make(map[int]bool, len(services))
+	for _, service := range services {
+		assigned[service.ID] = true
+
+		entry, ok := a.planned[service.ID]
+		if ok && sameProbe(&entry.service, &service) {
+			continue
+		}
+
+		if ok {
+			a.crontab.Remove(entry.entryID)
+		}
+
+		var schedule = newJitterSchedule(
+			time.Duration(service.Frequency)*time.Second,
+			time.Duration(a.cfg.HealthCheck.Jitter)*time.Second,
+		)
+		var planned = service
+		entryID := a.crontab.Schedule(schedule, cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(
+			cron.FuncJob(func() {
+				a.check(ctx, &planned)
+			}),
+		))
+		a.planned[service.ID] = &agentEntry{service: service, entryID: entryID}
+	}
+
+	for serviceID, entry := range a.planned {
+		if !assigned[serviceID] {
+			a.crontab.Remove(entry.entryID)
+			delete(a.planned, serviceID)
+		}
+	}
+}
+
+// check - probe service and report result to server
+func (a *Agent) check(ctx context.Context, service *models.Service) {
+	a.running.Add(1)
+	defer a.running.Done()
+
+	select {
+	case a.slots <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() { <-a.slots }()
+
+	result := a.prober.Health(ctx, service)
+	if ctx.Err() != nil {
+		return
+	}
+
+	a.Lock()
+	var agentID = a.id
+	a.Unlock()
+
+	if agentID == 0 {
+		return
+	}
+
+	var pResult = &projProto.CheckResult{
+		ServiceId:  int64(result.ServiceID),
+		Status:     projProto.CheckStatus(result.Status),
+		Error:      result.Error,
+		Attempts:   int32(result.Attempts),
+		DurationMs: int64(result.Duration / time.Millisecond),
+		CheckedAt:  result.CheckedAt.Unix(),
+	}
+	if result.CertExpiresAt != nil {
+		pResult.CertExpiresAt = result.CertExpiresAt.Unix()
+	}
+
+	_, err := a.client.AgentReport(a.authContext(ctx), &projProto.AgentReportRequest{
+		AgentId: agentID,
+		Result:  pResult,
+	})
+	if err != nil {
+		a.forget(err)
+		grpclog.Errorf("health-check agent %s: service %s report error: %v", a.name, service.Name, err)
+	}
+}
+
+// authContext - outgoing context with agents token
+func (a *Agent) authContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(
+		ctx,
+		strings.ToLower(consts.AuthorizationHeader),
+		"Bearer "+a.cfg.Agents.Token,
+	)
+}
+
+// sameProbe - true if services are probed with the same settings
+func sameProbe(s1, s2 *models.Service) bool {
+	return s1.Name == s2.Name &&
+		s1.Link == s2.Link &&
+		s1.Token == s2.Token &&
+		s1.Frequency == s2.Frequency &&
+		s1.Timeout == s2.Timeout &&
+		s1.Retries == s2.Retries &&
+		s1.RetryBackoff == s2.RetryBackoff
+}
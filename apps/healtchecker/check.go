@@ -1,10 +1,16 @@
 package healtchecker
 
 import (
+	"context"
+	"crypto/x509"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -18,14 +24,16 @@ import (
 	"projectionist/utils"
 )
 
-const EveryDurationPtrn = "@every %s"
-
 type HealthCheck struct {
+	metrics  models.CheckerMetrics // counters are changed atomically
 	notifier *apps.Notifier
 	syncChan chan string // chan with service name
-	errChan  chan error
 	sync.Mutex
 	cronEntries map[int]cron.EntryID // map[service id]cron entry id
+	pending     map[int]bool         // services with queued or running planned check
+	jobs        chan int             // queue of planned checks with service id
+	workers     sync.WaitGroup
+	agents      *agentRegistry
 	httpClient  http.Client
 	cfg         *config.Config
 	dbProvider  provider.IDBProvider
@@ -34,13 +42,15 @@ type HealthCheck struct {
 
 func NewHealthCkeck(cfg *config.Config, db *sql.DB, syncChan chan string) *HealthCheck {
 	return &HealthCheck{
-		notifier:    apps.NewNotifier(cfg),
+		notifier:    apps.NewNotifier(cfg, db),
 		syncChan:    syncChan,
-		errChan:     make(chan error),
 		Mutex:       sync.Mutex{},
 		cfg:         cfg,
 		crontab:     cron.New(),
 		cronEntries: make(map[int]cron.EntryID),
+		pending:     make(map[int]bool),
+		jobs:        make(chan int, cfg.HealthCheck.QueueSize),
+		agents:      newAgentRegistry(),
 		httpClient: http.Client{Transport: &http.Transport{
 			MaxIdleConns:       cfg.HealthCheck.ConnCount,
 			IdleConnTimeout:    time.Duration(cfg.HealthCheck.ConnTimeout) * time.Second,
@@ -50,10 +60,30 @@ func NewHealthCkeck(cfg *config.Config, db *sql.DB, syncChan chan string) *Healt
 	}
 }
 
-// Run - run health check on services
-func (hc *HealthCheck) Run() error {
+// RunNotifications - run escalation of open incidents and delivery of notifications until ctx is done,
+// they are independent of local health checks because agents reports open incidents too
+func (hc *HealthCheck) RunNotifications(ctx context.Context) {
+	hc.workers.Add(1)
+	go hc.escalator(ctx)
+
+	if hc.cfg.NotifierConfig.Enable {
+		hc.workers.Add(1)
+		go func() {
+			defer hc.workers.Done()
+			hc.notifier.Run(ctx)
+		}()
+	}
+}
+
+// Run - run health check on services and watch services changes until ctx is done,
+// results are votes of agents quorum if agents are enabled
+func (hc *HealthCheck) Run(ctx context.Context) error {
 	grpclog.Info("health-check: initialization services started")
 
+	if hc.agentsEnabled() {
+		hc.agents.addLocal()
+	}
+
 	services, err := hc.dbProvider.Pagination(&models.Service{}, 0, -1)
 	if err != nil {
 		return err
@@ -66,76 +96,51 @@ func (hc *HealthCheck) Run() error {
 			continue
 		}
 
-		if service.IsDeleted() {
+		if service.IsDeleted() || service.Paused {
 			continue
 		}
 
-		err = hc.plan(service)
+		err = hc.plan(ctx, service)
 		if err != nil {
-			return err
+			grpclog.Errorf("health-check: service %s not planned: %v", service.Name, err)
 		}
+	}
 
+	for i := 0; i < hc.cfg.HealthCheck.Workers; i++ {
+		hc.workers.Add(1)
+		go hc.worker(ctx)
 	}
 
 	hc.crontab.Start()
 
-	hc.watcher()
-
-	err = hc.watchErr()
-	if err != nil {
-		grpclog.Errorf("healtchecker.watchErr error: %v", err)
-		return err
-	}
+	hc.watcher(ctx)
 
 	return nil
 }
 
-// Stop - stop healt checker cron tasks
+// Stop - stop health checker cron tasks and wait for running health checks and notifications,
+// ctx of Run and RunNotifications must be done before
 func (hc *HealthCheck) Stop() {
-	hc.crontab.Stop()
+	<-hc.crontab.Stop().Done()
+	hc.workers.Wait()
 	grpclog.Info("Health Checker is stopped")
 }
 
-// plan - add cron entry by service and change service health status
-func (hc *HealthCheck) plan(service *models.Service) error {
-	entryID, err := hc.crontab.AddFunc(fmt.Sprintf(EveryDurationPtrn, time.Duration(service.Frequency)*time.Second), func() {
-		err := hc.Health(service)
-		if err != nil {
-			if service.Status == models.Alive {
-				service.Status = models.Dead
-			}
-			//hc.notifier.Send() TODO send message logic
-			grpclog.Errorf("service with id %d and name %s Health error: %v", service.ID, service.Name, err)
-			err := hc.dbProvider.Update(service, service.ID)
-			if err != nil {
-				//hc.notifier.Send() TODO send message logic
-				grpclog.Errorf(
-					"for service with id %d and name %s status %v not updated",
-					service.ID,
-					service.Name,
-					models.Dead)
-			}
-			return
-		}
-
-		if service.Status == models.Dead {
-			service.Status = models.Alive
-			err = hc.dbProvider.Update(service, service.ID)
-			if err != nil {
-				//hc.notifier.Send() TODO send message logic
-				grpclog.Errorf(
-					"for service with id %d and name %s status %v not updated",
-					service.ID,
-					service.Name,
-					models.Alive,
-				)
-			}
-		}
-	})
-	if err != nil {
-		return err
+// plan - add cron entry by service, entry only puts service check to workers queue
+func (hc *HealthCheck) plan(ctx context.Context, service *models.Service) error {
+	if service.Frequency <= 0 {
+		return fmt.Errorf("invalid frequency %d", service.Frequency)
 	}
 
+	var serviceID = service.ID
+	var schedule = newJitterSchedule(
+		time.Duration(service.Frequency)*time.Second,
+		time.Duration(hc.cfg.HealthCheck.Jitter)*time.Second,
+	)
+	entryID := hc.crontab.Schedule(schedule, cron.FuncJob(func() {
+		hc.enqueue(serviceID)
+	}))
+
 	hc.Lock()
 	hc.cronEntries[service.ID] = entryID
 	hc.Unlock()
@@ -143,59 +148,579 @@ func (hc *HealthCheck) plan(service *models.Service) error {
 	return nil
 }
 
-// watcher - watch if serivce added, updated, deleted run this logic
-func (hc *HealthCheck) watcher() {
+// enqueue - put planned service check to workers queue,
+// check is skipped if previous check of service is not finished or queue is full
+func (hc *HealthCheck) enqueue(serviceID int) {
+	hc.Lock()
+	defer hc.Unlock()
+
+	if hc.pending[serviceID] {
+		atomic.AddInt64(&hc.metrics.Skipped, 1)
+		grpclog.Warningf("health-check: service with id %d check skipped, previous check is not finished", serviceID)
+		return
+	}
+
+	select {
+	case hc.jobs <- serviceID:
+		hc.pending[serviceID] = true
+	default:
+		atomic.AddInt64(&hc.metrics.Dropped, 1)
+		grpclog.Warningf("health-check: service with id %d check dropped, queue is full", serviceID)
+	}
+}
+
+// worker - run planned service checks from queue until ctx is done
+func (hc *HealthCheck) worker(ctx context.Context) {
+	defer hc.workers.Done()
+
 	for {
 		select {
-		case serviceName := <-hc.syncChan:
-			iService, err := hc.dbProvider.GetByName(&models.Service{}, serviceName)
-			if err != nil {
-				grpclog.Errorf("health-check: dbProvider.GetByName() error: %v", err)
-				hc.errChan <- err
-				break
-			}
-
-			service, ok := iService.(*models.Service)
-			if !ok {
-				grpclog.Errorf("health-check: %+v is not service", iService)
-				break
+		case <-ctx.Done():
+			return
+		case serviceID := <-hc.jobs:
+			atomic.AddInt64(&hc.metrics.Running, 1)
+			_, err := hc.check(ctx, serviceID, false)
+			if err != nil && ctx.Err() == nil {
+				grpclog.Errorf("health-check: service with id %d check error: %v", serviceID, err)
 			}
+			atomic.AddInt64(&hc.metrics.Running, -1)
+			atomic.AddInt64(&hc.metrics.Checked, 1)
 
 			hc.Lock()
-			entryID, ok := hc.cronEntries[service.ID]
+			delete(hc.pending, serviceID)
 			hc.Unlock()
-			if !ok {
-				err := hc.plan(service)
-				hc.errChan <- err
-				break
-			}
+		}
+	}
+}
 
-			if service.IsDeleted() {
-				hc.crontab.Remove(entryID)
-				break
-			}
-		default:
-			break
+// Metrics - current state of probe worker pool
+func (hc *HealthCheck) Metrics() models.CheckerMetrics {
+	hc.Lock()
+	var planned = len(hc.cronEntries)
+	hc.Unlock()
+
+	return models.CheckerMetrics{
+		Checked:    atomic.LoadInt64(&hc.metrics.Checked),
+		Skipped:    atomic.LoadInt64(&hc.metrics.Skipped),
+		Dropped:    atomic.LoadInt64(&hc.metrics.Dropped),
+		Running:    atomic.LoadInt64(&hc.metrics.Running),
+		Workers:    hc.cfg.HealthCheck.Workers,
+		QueueDepth: len(hc.jobs),
+		QueueSize:  cap(hc.jobs),
+		Planned:    planned,
+	}
+}
+
+// CheckNow - check service health immediately, even if service is paused, and apply result
+func (hc *HealthCheck) CheckNow(ctx context.Context, serviceID int) (*models.CheckResult, error) {
+	return hc.check(ctx, serviceID, true)
+}
+
+// check - check health of service with actual settings and apply result to service status,
+// if agents are enabled result is vote of local agent and quorum decision is applied,
+// planned check is skipped if service is in pause maintenance,
+// result is not applied if service is in any maintenance
+func (hc *HealthCheck) check(ctx context.Context, serviceID int, manual bool) (*models.CheckResult, error) {
+	iService, err := hc.dbProvider.GetByID(&models.Service{}, int64(serviceID))
+	if err != nil {
+		return nil, err
+	}
+
+	service, ok := iService.(*models.Service)
+	if !ok || service.IsDeleted() {
+		return nil, sql.ErrNoRows
+	}
+
+	window := hc.activeMaintenance(service)
+	if !manual && window != nil && window.Mode == models.MaintenancePause {
+		return nil, nil
+	}
+
+	result := hc.Health(ctx, service)
+	if ctx.Err() != nil {
+		// check is canceled, result is not reliable
+		return result, ctx.Err()
+	}
+
+	if window != nil {
+		grpclog.Infof(
+			"health-check: service %s in maintenance %d, result %v not applied",
+			service.Name,
+			window.ID,
+			result.Status,
+		)
+		return result, nil
+	}
+
+	if hc.agentsEnabled() {
+		decision := hc.agents.decide(
+			service,
+			localAgentID,
+			result,
+			hc.cfg.Agents.Quorum,
+			time.Duration(hc.cfg.Agents.TTL)*time.Second,
+		)
+		if decision != nil {
+			hc.apply(service, decision)
 		}
+		return result, nil
 	}
+
+	hc.apply(service, result)
+
+	return result, nil
 }
 
-// watchErr - watcher on errors
-func (hc *HealthCheck) watchErr() error {
+// unplan - remove cron entry of service if exist
+func (hc *HealthCheck) unplan(serviceID int) {
+	hc.Lock()
+	defer hc.Unlock()
+
+	entryID, ok := hc.cronEntries[serviceID]
+	if !ok {
+		return
+	}
+
+	hc.crontab.Remove(entryID)
+	delete(hc.cronEntries, serviceID)
+}
+
+// watcher - wait for added, updated or deleted services until ctx is done
+func (hc *HealthCheck) watcher(ctx context.Context) {
 	for {
 		select {
-		case err := <-hc.errChan:
-			if err != nil {
-				return err
+		case <-ctx.Done():
+			return
+		case serviceName := <-hc.syncChan:
+			hc.sync(ctx, serviceName)
+		}
+	}
+}
+
+// sync - replan service with new settings, remove deleted or paused service from plan
+func (hc *HealthCheck) sync(ctx context.Context, serviceName string) {
+	iService, err := hc.dbProvider.GetByName(&models.Service{}, serviceName)
+	if err != nil {
+		grpclog.Errorf("health-check: dbProvider.GetByName(%s) error: %v", serviceName, err)
+		return
+	}
+
+	service, ok := iService.(*models.Service)
+	if !ok {
+		grpclog.Errorf("health-check: %+v is not service", iService)
+		return
+	}
+
+	hc.unplan(service.ID)
+
+	if service.IsDeleted() || service.Paused {
+		grpclog.Infof("health-check: service %s removed from plan", service.Name)
+		return
+	}
+
+	err = hc.plan(ctx, service)
+	if err != nil {
+		grpclog.Errorf("health-check: service %s not planned: %v", service.Name, err)
+		return
+	}
+
+	grpclog.Infof("health-check: service %s planned every %d seconds", service.Name, service.Frequency)
+}
+
+// jitterSchedule - every delay schedule shifted by offset,
+// services with same frequency get different offsets and are not checked at the same time
+type jitterSchedule struct {
+	delay  time.Duration
+	offset time.Duration
+}
+
+// newJitterSchedule - schedule with random offset less than jitter and delay
+func newJitterSchedule(delay, jitter time.Duration) jitterSchedule {
+	if delay < time.Second {
+		delay = time.Second
+	}
+
+	if jitter > delay {
+		jitter = delay
+	}
+
+	var offset time.Duration
+	if jitter >= time.Second {
+		offset = time.Duration(rand.Int63n(int64(jitter/time.Second))) * time.Second
+	}
+
+	return jitterSchedule{delay: delay, offset: offset}
+}
+
+// Next - first time after t matching delay grid shifted by offset
+func (s jitterSchedule) Next(t time.Time) time.Time {
+	var next = t.Truncate(s.delay).Add(s.offset)
+	for !next.After(t) {
+		next = next.Add(s.delay)
+	}
+
+	return next
+}
+
+// activeMaintenance - current maintenance window of service, nil if service not in maintenance
+func (hc *HealthCheck) activeMaintenance(service *models.Service) *models.MaintenanceWindow {
+	db, ok := hc.dbProvider.GetDB().(*sql.DB)
+	if !ok || db == nil {
+		return nil
+	}
+
+	window, err := service.ActiveMaintenance(db, time.Now())
+	if err != nil {
+		grpclog.Errorf("health-check: service %s ActiveMaintenance error: %v", service.Name, err)
+		return nil
+	}
+
+	return window
+}
+
+// apply - change service health status by check result, save it and notify service recipients on status change
+func (hc *HealthCheck) apply(service *models.Service, result *models.CheckResult) {
+	var status models.Status = models.Alive
+	if !result.Success() {
+		status = hc.downStatus(service)
+		grpclog.Errorf(
+			"service with id %d and name %s Health %v after %d attempts: %s",
+			service.ID,
+			service.Name,
+			result.Status,
+			result.Attempts,
+			result.Error,
+		)
+	} else if hc.certExpiresSoon(service, result.CertExpiresAt) {
+		status = models.Warning
+	}
+
+	var certChanged = result.CertExpiresAt != nil &&
+		(service.CertExpiresAt == nil || !service.CertExpiresAt.Equal(*result.CertExpiresAt))
+	if status == service.Status && !certChanged {
+		return
+	}
+
+	var prevStatus = service.Status
+	service.Status = status
+	if certChanged {
+		service.CertExpiresAt = result.CertExpiresAt
+	}
+
+	// update only health fields, service emails already saved
+	err := hc.dbProvider.Update(&models.Service{
+		Status:        service.Status,
+		CertExpiresAt: service.CertExpiresAt,
+	}, service.ID)
+	if err != nil {
+		grpclog.Errorf(
+			"for service with id %d and name %s status %v not updated: %v",
+			service.ID,
+			service.Name,
+			status,
+			err,
+		)
+	}
+
+	if status == prevStatus {
+		return
+	}
+
+	hc.recordStatus(service)
+
+	// incident is open while service is dead or degraded after death, closed on recovery
+	var incident *models.Incident
+	if status == models.Dead {
+		incident = hc.openIncident(service, result)
+	} else if !status.IsDown() {
+		incident = hc.closeIncident(service)
+	}
+
+	var data = &models.NotificationData{
+		Service:  service,
+		Incident: incident,
+		Result:   result,
+		Time:     time.Now().UTC(),
+	}
+
+	switch status {
+	case models.Degraded:
+		// root cause is upstream, recipients of dependency are notified
+		grpclog.Infof("service %s is degraded by dependency, notification suppressed", service.Name)
+	case models.Dead:
+		data.Event = models.EventDown
+		data.Message = incidentMessage(incident, fmt.Sprintf("service is down (%v): %s", result.Status, result.Error))
+		hc.notify(data)
+	case models.Warning:
+		// dead service is up again with expiring certificate, subscribers learn that outage is over first
+		if prevStatus == models.Dead {
+			var recovered = *data
+			recovered.Event = models.EventRecovered
+			recovered.Message = incidentMessage(incident, "service is alive again")
+			hc.notify(&recovered)
+		}
+
+		data.Event = models.EventCertExpiring
+		data.Message = incidentMessage(incident, fmt.Sprintf(
+			"link certificate expires at %s", service.CertExpiresAt.Format(time.RFC1123)))
+		hc.notify(data)
+	default:
+		if prevStatus == models.Dead {
+			data.Event = models.EventRecovered
+			data.Message = incidentMessage(incident, "service is alive again")
+			hc.notify(data)
+		}
+	}
+}
+
+// openIncident - open incident of dead service, nil if incident is not opened
+func (hc *HealthCheck) openIncident(service *models.Service, result *models.CheckResult) *models.Incident {
+	db, ok := hc.dbProvider.GetDB().(*sql.DB)
+	if !ok || db == nil {
+		return nil
+	}
+
+	incident, err := models.OpenIncident(db, service.ID, result.Error, result.CheckedAt)
+	if err != nil {
+		grpclog.Errorf("health-check: service %s OpenIncident error: %v", service.Name, err)
+		return nil
+	}
+
+	grpclog.Infof("health-check: service %s incident #%d is open", service.Name, incident.ID)
+
+	return incident
+}
+
+// closeIncident - close open incident of recovered service, nil if service has no open incident
+func (hc *HealthCheck) closeIncident(service *models.Service) *models.Incident {
+	db, ok := hc.dbProvider.GetDB().(*sql.DB)
+	if !ok || db == nil {
+		return nil
+	}
+
+	incident, err := service.GetOpenIncident(db)
+	if err != nil {
+		grpclog.Errorf("health-check: service %s GetOpenIncident error: %v", service.Name, err)
+		return nil
+	}
+
+	if incident == nil {
+		return nil
+	}
+
+	err = incident.Close(db, time.Now())
+	if err != nil {
+		grpclog.Errorf("health-check: service %s incident #%d close error: %v", service.Name, incident.ID, err)
+		return nil
+	}
+
+	grpclog.Infof(
+		"health-check: service %s incident #%d is closed after %d seconds",
+		service.Name,
+		incident.ID,
+		incident.Duration,
+	)
+
+	return incident
+}
+
+// incidentMessage - notification message with reference to incident
+func incidentMessage(incident *models.Incident, message string) string {
+	if incident == nil {
+		return message
+	}
+
+	if incident.IsOpen() {
+		return fmt.Sprintf("incident #%d: %s", incident.ID, message)
+	}
+
+	return fmt.Sprintf(
+		"incident #%d resolved after %s: %s",
+		incident.ID,
+		time.Duration(incident.Duration)*time.Second,
+		message,
+	)
+}
+
+// recordStatus - save status transition to service uptime history
+func (hc *HealthCheck) recordStatus(service *models.Service) {
+	db, ok := hc.dbProvider.GetDB().(*sql.DB)
+	if !ok || db == nil {
+		return
+	}
+
+	err := models.SaveStatusChange(db, service.ID, service.Status, time.Now())
+	if err != nil {
+		grpclog.Errorf("health-check: service %s SaveStatusChange error: %v", service.Name, err)
+	}
+}
+
+// downStatus - status of not responding service: Degraded if one of its dependencies is down, else Dead
+func (hc *HealthCheck) downStatus(service *models.Service) models.Status {
+	db, ok := hc.dbProvider.GetDB().(*sql.DB)
+	if !ok || db == nil {
+		return models.Dead
+	}
+
+	dependencyDown, err := service.IsDependencyDown(db)
+	if err != nil {
+		grpclog.Errorf("health-check: service %s IsDependencyDown error: %v", service.Name, err)
+		return models.Dead
+	}
+
+	if dependencyDown {
+		return models.Degraded
+	}
+
+	return models.Dead
+}
+
+// certExpiresSoon - true if certificate expires in less than service warning window
+func (hc *HealthCheck) certExpiresSoon(service *models.Service, expiresAt *time.Time) bool {
+	if expiresAt == nil {
+		return false
+	}
+
+	var warnDays = service.CertWarnDays
+	if warnDays <= 0 {
+		warnDays = hc.cfg.HealthCheck.CertWarnDays
+	}
+
+	return time.Until(*expiresAt) < time.Duration(warnDays)*24*time.Hour
+}
+
+// notify - send notification to service emails and notification channels
+func (hc *HealthCheck) notify(data *models.NotificationData) {
+	if !hc.cfg.NotifierConfig.Enable {
+		return
+	}
+
+	db, ok := hc.dbProvider.GetDB().(*sql.DB)
+	if !ok {
+		grpclog.Errorf("health-check: notify: database is not sql")
+		return
+	}
+
+	hc.notifyEmails(db, data)
+	hc.notifyChannels(db, data)
+}
+
+// notifyEmails - send notification to service emails
+func (hc *HealthCheck) notifyEmails(db *sql.DB, data *models.NotificationData) {
+	var service = data.Service
+	emails, err := service.GetEmails(db)
+	if err != nil {
+		grpclog.Errorf("health-check: service %s GetEmails error: %v", service.Name, err)
+		return
+	}
+
+	if len(emails) == 0 {
+		return
+	}
+
+	var to = make([]string, 0, len(emails))
+	for _, email := range emails {
+		to = append(to, email.Email)
+	}
+
+	err = hc.notifier.Send(to, hc.render(db, models.ChannelEmail, data))
+	if err != nil {
+		grpclog.Errorf("health-check: service %s notification error: %v", service.Name, err)
+	}
+}
+
+// notifyChannels - send notification to every notification channel of service, failed channel does not stop others
+func (hc *HealthCheck) notifyChannels(db *sql.DB, data *models.NotificationData) {
+	var service = data.Service
+	channels, err := service.GetChannels(db)
+	if err != nil {
+		grpclog.Errorf("health-check: service %s GetChannels error: %v", service.Name, err)
+		return
+	}
+
+	for _, channel := range channels {
+		err = hc.notifier.SendChannel(context.Background(), channel, hc.render(db, channel.Type, data))
+		if err != nil {
+			grpclog.Errorf("health-check: service %s %s channel #%d error: %v", service.Name, channel.Type, channel.ID, err)
+		}
+	}
+}
+
+// render - notification rendered by customized template of channel and event,
+// default template is used if template is not customized or broken
+func (hc *HealthCheck) render(db *sql.DB, channel models.ChannelType, data *models.NotificationData) apps.Message {
+	var tmpl = models.NotificationTemplate{}
+	err := models.GetNotificationTemplate(db, channel, data.Event, &tmpl)
+	if err == nil {
+		message, err := apps.Render(tmpl, data)
+		if err == nil {
+			return message
+		}
+		grpclog.Errorf("health-check: %s template #%d render error: %v", tmpl.GetName(), tmpl.ID, err)
+	} else if err != sql.ErrNoRows {
+		grpclog.Errorf("health-check: GetNotificationTemplate(%s, %s) error: %v", channel, data.Event, err)
+	}
+
+	message, err := apps.Render(apps.DefaultTemplate(channel, data.Event), data)
+	if err != nil {
+		// default templates are static, error means broken data
+		grpclog.Errorf("health-check: default %s/%s template render error: %v", channel, data.Event, err)
+		return apps.Message{Service: data.Service.Name, Event: data.Event, Body: data.Message}
+	}
+
+	return message
+}
+
+// Health - send request by service health link and check service status,
+// failed request is retried service.Retries times with exponential backoff
+func (hc *HealthCheck) Health(ctx context.Context, service *models.Service) *models.CheckResult {
+	var result = &models.CheckResult{
+		ServiceID: service.ID,
+		CheckedAt: time.Now(),
+	}
+
+	var backoff = time.Duration(service.RetryBackoff) * time.Second
+	for attempt := 0; attempt <= service.Retries; attempt++ {
+		if attempt > 0 && backoff > 0 {
+			select {
+			case <-ctx.Done():
+				result.Status = models.CheckFailure
+				result.Error = ctx.Err().Error()
+				result.Duration = time.Since(result.CheckedAt)
+				return result
+			case <-time.After(backoff):
 			}
-		default:
+			backoff *= 2
+		}
+
+		result.Attempts++
+		err := hc.probe(ctx, service, result)
+		if err == nil {
+			result.Status = models.CheckSuccess
+			result.Error = ""
+			break
 		}
+
+		result.Status = models.CheckFailure
+		if isTimeout(err) {
+			result.Status = models.CheckTimeout
+		}
+		result.Error = err.Error()
 	}
+
+	result.Duration = time.Since(result.CheckedAt)
+
+	return result
 }
 
-// Health - send request by service healt link and check service status
-func (hc *HealthCheck) Health(service *models.Service) error {
-	req, err := http.NewRequest(http.MethodGet, service.Link, nil)
+// probe - send one request by service health link, request is canceled after service timeout
+func (hc *HealthCheck) probe(ctx context.Context, service *models.Service, result *models.CheckResult) error {
+	var timeout = service.EffectiveTimeout(hc.cfg.HealthCheck.Timeout)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, service.Link, nil)
 	if err != nil {
 		return err
 	}
@@ -206,6 +731,34 @@ func (hc *HealthCheck) Health(service *models.Service) error {
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	if resp.TLS != nil {
+		result.CertExpiresAt = certExpiresAt(resp.TLS.PeerCertificates)
+	}
 
 	return utils.CheckHealthStatusCode(resp.StatusCode, service.Name)
 }
+
+// certExpiresAt - earliest expiry time in certificate chain
+func certExpiresAt(chain []*x509.Certificate) *time.Time {
+	var expiresAt *time.Time
+	for _, cert := range chain {
+		if expiresAt == nil || cert.NotAfter.Before(*expiresAt) {
+			notAfter := cert.NotAfter
+			expiresAt = &notAfter
+		}
+	}
+
+	return expiresAt
+}
+
+// isTimeout - true if request error caused by expired timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,451 @@
+package healtchecker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	_ "github.com/mattn/go-sqlite3"
+
+	apps "projectionist/apps/notifier"
+	"projectionist/config"
+	"projectionist/db"
+	"projectionist/models"
+	"projectionist/provider"
+)
+
+func newTestHealthCheck() *HealthCheck {
+	cfg := &config.Config{}
+	cfg.HealthCheck.ConnCount = 1
+	cfg.HealthCheck.ConnTimeout = 1
+	cfg.HealthCheck.Timeout = 1
+	cfg.HealthCheck.Workers = 1
+	cfg.HealthCheck.QueueSize = 1
+
+	return NewHealthCkeck(cfg, nil, make(chan string))
+}
+
+func (hc *HealthCheck) isPlanned(serviceID int) bool {
+	hc.Lock()
+	defer hc.Unlock()
+	_, ok := hc.cronEntries[serviceID]
+	return ok
+}
+
+func TestHealthCheck_Health(t *testing.T) {
+	var failures int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/dead", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/hang", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+		}
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&failures, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		service      *models.Service
+		wantStatus   models.CheckStatus
+		wantAttempts int
+	}{
+		{
+			name:         "alive",
+			service:      &models.Service{ID: 1, Name: "ok", Link: server.URL + "/ok"},
+			wantStatus:   models.CheckSuccess,
+			wantAttempts: 1,
+		},
+		{
+			name:         "dead after retries",
+			service:      &models.Service{ID: 2, Name: "dead", Link: server.URL + "/dead", Retries: 2},
+			wantStatus:   models.CheckFailure,
+			wantAttempts: 3,
+		},
+		{
+			name:         "timeout",
+			service:      &models.Service{ID: 3, Name: "hang", Link: server.URL + "/hang", Timeout: 1},
+			wantStatus:   models.CheckTimeout,
+			wantAttempts: 1,
+		},
+		{
+			name:         "alive after retries",
+			service:      &models.Service{ID: 4, Name: "flaky", Link: server.URL + "/flaky", Retries: 3},
+			wantStatus:   models.CheckSuccess,
+			wantAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := newTestHealthCheck()
+
+			got := hc.Health(context.Background(), tt.service)
+			if got.Status != tt.wantStatus {
+				t.Errorf("Health() status = %v, want %v, error: %s", got.Status, tt.wantStatus, got.Error)
+			}
+
+			if got.Attempts != tt.wantAttempts {
+				t.Errorf("Health() attempts = %v, want %v", got.Attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestHealthCheck_HealthCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	hc := newTestHealthCheck()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	service := &models.Service{ID: 1, Name: "dead", Link: server.URL, Retries: 5, RetryBackoff: 10}
+
+	start := time.Now()
+	got := hc.Health(ctx, service)
+	if time.Since(start) > time.Second {
+		t.Errorf("Health() not stopped on context cancel, duration: %v", time.Since(start))
+	}
+
+	if got.Success() {
+		t.Errorf("Health() got success, want failure")
+	}
+
+	if got.Attempts != 1 {
+		t.Errorf("Health() attempts = %v, want %v", got.Attempts, 1)
+	}
+}
+
+func TestHealthCheck_HealthCertExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hc := newTestHealthCheck()
+	hc.httpClient = *server.Client()
+
+	service := &models.Service{ID: 1, Name: "tls", Link: server.URL}
+
+	got := hc.Health(context.Background(), service)
+	if !got.Success() {
+		t.Fatalf("Health() status = %v, want %v, error: %s", got.Status, models.CheckSuccess, got.Error)
+	}
+
+	if got.CertExpiresAt == nil || !got.CertExpiresAt.Equal(server.Certificate().NotAfter) {
+		t.Fatalf("Health() cert expires at = %v, want %v", got.CertExpiresAt, server.Certificate().NotAfter)
+	}
+
+	if hc.certExpiresSoon(service, got.CertExpiresAt) {
+		t.Errorf("certExpiresSoon() = true, want false for default window")
+	}
+
+	service.CertWarnDays = int(time.Until(*got.CertExpiresAt).Hours()/24) + 1
+	if !hc.certExpiresSoon(service, got.CertExpiresAt) {
+		t.Errorf("certExpiresSoon() = false, want true for %d days window", service.CertWarnDays)
+	}
+}
+
+func TestHealthCheck_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvider := provider.NewMockIDBProvider(ctrl)
+
+	hc := newTestHealthCheck()
+	hc.dbProvider = mockProvider
+
+	mockProvider.EXPECT().Pagination(&models.Service{}, 0, -1).Return([]models.Model{
+		&models.Service{ID: 1, Name: "first", Link: "http://127.0.0.1:1", Frequency: 100},
+		&models.Service{ID: 2, Name: "second", Link: "http://127.0.0.1:1", Frequency: 100},
+		&models.Service{ID: 3, Name: "deleted", Link: "http://127.0.0.1:1", Frequency: 100, Deleted: 1},
+	}, nil)
+	gomock.InOrder(
+		mockProvider.EXPECT().GetByName(&models.Service{}, "unknown").Return(nil, errors.New("no rows")),
+		mockProvider.EXPECT().GetByName(&models.Service{}, "first").Return(
+			&models.Service{ID: 1, Name: "first", Link: "http://127.0.0.1:1", Frequency: 200}, nil),
+		mockProvider.EXPECT().GetByName(&models.Service{}, "second").Return(
+			&models.Service{ID: 2, Name: "second", Link: "http://127.0.0.1:1", Frequency: 100, Deleted: 1}, nil),
+		mockProvider.EXPECT().GetByName(&models.Service{}, "third").Return(
+			&models.Service{ID: 4, Name: "third", Link: "http://127.0.0.1:1", Frequency: 300}, nil),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- hc.Run(ctx)
+	}()
+
+	for _, name := range []string{"unknown", "first", "second", "third"} {
+		hc.syncChan <- name
+	}
+
+	// unbuffered channel: the last service is received, wait until it is planned
+	deadline := time.Now().Add(time.Second)
+	for !hc.isPlanned(4) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	wantPlanned := map[int]time.Duration{1: 200 * time.Second, 4: 300 * time.Second}
+	for _, entry := range hc.crontab.Entries() {
+		var serviceID int
+		hc.Lock()
+		for id, entryID := range hc.cronEntries {
+			if entryID == entry.ID {
+				serviceID = id
+			}
+		}
+		hc.Unlock()
+
+		want, ok := wantPlanned[serviceID]
+		if !ok {
+			t.Errorf("unexpected cron entry %d for service %d", entry.ID, serviceID)
+			continue
+		}
+
+		if got := entry.Schedule.(jitterSchedule).delay; got != want {
+			t.Errorf("service %d planned every %v, want %v", serviceID, got, want)
+		}
+		delete(wantPlanned, serviceID)
+	}
+
+	if len(wantPlanned) != 0 {
+		t.Errorf("services not planned: %v", wantPlanned)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Run() not stopped after context cancel")
+	}
+
+	hc.Stop()
+}
+
+func TestJitterSchedule_Next(t *testing.T) {
+	var now = time.Date(2020, 1, 1, 10, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule jitterSchedule
+		want     time.Time
+	}{
+		{
+			name:     "without offset",
+			schedule: jitterSchedule{delay: time.Minute},
+			want:     time.Date(2020, 1, 1, 10, 1, 0, 0, time.UTC),
+		},
+		{
+			name:     "offset after now",
+			schedule: jitterSchedule{delay: time.Minute, offset: 40 * time.Second},
+			want:     time.Date(2020, 1, 1, 10, 0, 40, 0, time.UTC),
+		},
+		{
+			name:     "offset before now",
+			schedule: jitterSchedule{delay: time.Minute, offset: 10 * time.Second},
+			want:     time.Date(2020, 1, 1, 10, 1, 10, 0, time.UTC),
+		},
+		{
+			name:     "offset equal now",
+			schedule: jitterSchedule{delay: time.Minute, offset: 30 * time.Second},
+			want:     time.Date(2020, 1, 1, 10, 1, 30, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(now); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewJitterSchedule(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		schedule := newJitterSchedule(10*time.Second, time.Minute)
+		if schedule.offset < 0 || schedule.offset >= schedule.delay {
+			t.Fatalf("newJitterSchedule() offset %v not in [0, %v)", schedule.offset, schedule.delay)
+		}
+	}
+
+	if schedule := newJitterSchedule(time.Minute, 0); schedule.offset != 0 {
+		t.Errorf("newJitterSchedule() offset %v without jitter, want 0", schedule.offset)
+	}
+}
+
+func TestHealthCheck_Enqueue(t *testing.T) {
+	hc := newTestHealthCheck()
+
+	hc.enqueue(1)
+	hc.enqueue(1) // still queued
+	hc.enqueue(2) // queue is full
+
+	got := hc.Metrics()
+	want := models.CheckerMetrics{Skipped: 1, Dropped: 1, Workers: 1, QueueDepth: 1, QueueSize: 1}
+	if got != want {
+		t.Fatalf("Metrics() = %+v, want %+v", got, want)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvider := provider.NewMockIDBProvider(ctrl)
+	hc.dbProvider = mockProvider
+
+	checked := make(chan struct{})
+	mockProvider.EXPECT().GetByID(&models.Service{}, int64(1)).DoAndReturn(
+		func(m models.Model, id int64) (models.Model, error) {
+			close(checked)
+			return &models.Service{ID: 1, Deleted: 1}, nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hc.workers.Add(1)
+	go hc.worker(ctx)
+
+	<-checked
+	deadline := time.Now().Add(time.Second)
+	for hc.Metrics().Checked != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	hc.workers.Wait()
+
+	got = hc.Metrics()
+	want = models.CheckerMetrics{Checked: 1, Skipped: 1, Dropped: 1, Workers: 1, QueueSize: 1}
+	if got != want {
+		t.Errorf("Metrics() after check = %+v, want %+v", got, want)
+	}
+
+	hc.enqueue(1)
+	if got := hc.Metrics().QueueDepth; got != 1 {
+		t.Errorf("service not queued again after check, queue depth %d", got)
+	}
+}
+
+func TestIncidentMessage(t *testing.T) {
+	var endedAt = time.Now()
+
+	tests := []struct {
+		name     string
+		incident *models.Incident
+		want     string
+	}{
+		{
+			name: "without incident",
+			want: "service is down",
+		},
+		{
+			name:     "open incident",
+			incident: &models.Incident{ID: 5},
+			want:     "incident #5: service is down",
+		},
+		{
+			name:     "closed incident",
+			incident: &models.Incident{ID: 5, EndedAt: &endedAt, Duration: 90},
+			want:     "incident #5 resolved after 1m30s: service is down",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := incidentMessage(tt.incident, "service is down"); got != tt.want {
+				t.Errorf("incidentMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthCheck_ApplyRecoveredWithCertWarning(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthcheck")
+	if err != nil {
+		t.Fatalf("temp dir error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(dir, "test.sqlite"))
+	if err != nil {
+		t.Fatalf("open db error: %v", err)
+	}
+	defer sqlDB.Close()
+
+	if err = db.InitTables(sqlDB); err != nil {
+		t.Fatalf("init tables error: %v", err)
+	}
+
+	var mu sync.Mutex
+	var events []models.NotificationEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload apps.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode webhook error: %v", err)
+		}
+		mu.Lock()
+		events = append(events, payload.Event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	var service = &models.Service{
+		Name: "service", Link: "http://127.0.0.1/health", Token: "token", Frequency: 60, Status: models.Dead,
+		Channels: []models.Channel{{Type: models.ChannelWebhook, URL: server.URL}},
+	}
+	if err = service.Save(sqlDB); err != nil {
+		t.Fatalf("service Save() error: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.HealthCheck.QueueSize = 1
+	cfg.HealthCheck.CertWarnDays = 14
+	cfg.NotifierConfig.Enable = true
+	cfg.NotifierConfig.ChannelTimeout = 5
+	hc := NewHealthCkeck(cfg, sqlDB, make(chan string))
+
+	var expiresAt = time.Now().Add(24 * time.Hour)
+	hc.apply(service, &models.CheckResult{ServiceID: service.ID, Status: models.CheckSuccess, CertExpiresAt: &expiresAt})
+
+	if service.Status != models.Warning {
+		t.Errorf("service status = %v, want %v", service.Status, models.Warning)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var want = []models.NotificationEvent{models.EventRecovered, models.EventCertExpiring}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("notified events = %v, want %v", events, want)
+	}
+}
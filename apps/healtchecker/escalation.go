@@ -0,0 +1,120 @@
+package healtchecker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	"projectionist/models"
+)
+
+// escalator - evaluate escalation policies of open incidents every escalation interval until ctx is done,
+// escalation is disabled if interval is not positive
+func (hc *HealthCheck) escalator(ctx context.Context) {
+	defer hc.workers.Done()
+
+	if hc.cfg.HealthCheck.EscalationInterval <= 0 {
+		return
+	}
+
+	var ticker = time.NewTicker(time.Duration(hc.cfg.HealthCheck.EscalationInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			hc.escalate(now)
+		}
+	}
+}
+
+// escalate - notify levels of escalation policies about open not acknowledged incidents,
+// acknowledged or resolved incident is not escalated anymore
+func (hc *HealthCheck) escalate(now time.Time) {
+	db, ok := hc.dbProvider.GetDB().(*sql.DB)
+	if !ok || db == nil {
+		grpclog.Errorf("health-check: escalate: database is not sql")
+		return
+	}
+
+	incidents, err := models.GetEscalatingIncidents(db)
+	if err != nil {
+		grpclog.Errorf("health-check: GetEscalatingIncidents error: %v", err)
+		return
+	}
+
+	var policies = make(map[int]*models.EscalationPolicy)
+	for _, incident := range incidents {
+		var service = &models.Service{}
+		err = service.GetByID(db, int64(incident.ServiceID))
+		if err != nil {
+			grpclog.Errorf("health-check: escalate: incident #%d service GetByID error: %v", incident.ID, err)
+			continue
+		}
+
+		var policyID = service.GetEscalationPolicyID()
+		policy, ok := policies[policyID]
+		if !ok {
+			policy = &models.EscalationPolicy{}
+			err = policy.GetByID(db, int64(policyID))
+			if err != nil {
+				grpclog.Errorf("health-check: escalate: service %s policy GetByID error: %v", service.Name, err)
+				continue
+			}
+
+			policies[policyID] = policy
+		}
+
+		var levels = policy.Escalate(incident, now)
+		if len(levels) == 0 {
+			continue
+		}
+
+		var data = &models.NotificationData{
+			Event:    models.EventEscalation,
+			Service:  service,
+			Incident: incident,
+			Message:  escalationMessage(incident, now),
+			Time:     now.UTC(),
+		}
+		for _, level := range levels {
+			hc.notifyLevel(db, level, data)
+		}
+
+		var notified = incident.EscalationLevel + len(levels)
+		if notified > len(policy.Levels) {
+			notified = len(policy.Levels)
+		}
+
+		err = incident.SetEscalation(db, notified, now)
+		if err != nil {
+			grpclog.Errorf("health-check: incident #%d SetEscalation error: %v", incident.ID, err)
+		}
+	}
+}
+
+// notifyLevel - send notification to emails of escalation level
+func (hc *HealthCheck) notifyLevel(db *sql.DB, level models.EscalationLevel, data *models.NotificationData) {
+	if !hc.cfg.NotifierConfig.Enable {
+		return
+	}
+
+	err := hc.notifier.Send(level.Emails, hc.render(db, models.ChannelEmail, data))
+	if err != nil {
+		grpclog.Errorf("health-check: service %s escalation notification error: %v", data.Service.Name, err)
+	}
+}
+
+// escalationMessage - message about incident which is not acknowledged yet
+func escalationMessage(incident *models.Incident, now time.Time) string {
+	return incidentMessage(incident, fmt.Sprintf(
+		"not acknowledged for %s, %s",
+		now.Sub(incident.StartedAt).Round(time.Second),
+		incident.Reason,
+	))
+}
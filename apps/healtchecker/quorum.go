@@ -0,0 +1,247 @@
+package healtchecker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	"projectionist/consts"
+	"projectionist/models"
+)
+
+// localAgentID - id of server own health checks, remote agents get ids from 1
+const localAgentID int64 = 0
+
+// agent - registered remote health check agent or server own health checks
+type agent struct {
+	id     int64
+	name   string
+	seenAt time.Time
+	local  bool // server own checks are active while checker runs
+}
+
+// active - agent requested server during ttl or it is local
+func (a *agent) active(ttl time.Duration) bool {
+	return a.local || time.Since(a.seenAt) <= ttl
+}
+
+// agentRegistry - registered agents and their last check results of services
+type agentRegistry struct {
+	sync.Mutex
+	lastID  int64
+	agents  map[int64]*agent
+	reports map[int]map[int64]*models.CheckResult // map[service id]map[agent id]last check result
+}
+
+func newAgentRegistry() *agentRegistry {
+	return &agentRegistry{
+		agents:  make(map[int64]*agent),
+		reports: make(map[int]map[int64]*models.CheckResult),
+	}
+}
+
+// agentsEnabled - remote agents are allowed, check results are decided by quorum
+func (hc *HealthCheck) agentsEnabled() bool {
+	return hc.cfg.Agents.Token != ""
+}
+
+// addLocal - add server own checks as agent voting in quorum
+func (r *agentRegistry) addLocal() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.agents[localAgentID] = &agent{id: localAgentID, name: "local", seenAt: time.Now(), local: true}
+}
+
+// RegisterAgent - register agent by name, agent with the same name gets its previous id
+func (hc *HealthCheck) RegisterAgent(name string) int64 {
+	var r = hc.agents
+	r.Lock()
+	defer r.Unlock()
+
+	for _, a := range r.agents {
+		if !a.local && a.name == name {
+			a.seenAt = time.Now()
+			return a.id
+		}
+	}
+
+	r.lastID++
+	r.agents[r.lastID] = &agent{id: r.lastID, name: name, seenAt: time.Now()}
+	grpclog.Infof("health-check: agent %s registered with id %d", name, r.lastID)
+
+	return r.lastID
+}
+
+// AgentServices - services assigned to agent, every agent checks all planned services
+func (hc *HealthCheck) AgentServices(agentID int64) ([]*models.Service, error) {
+	err := hc.touchAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	iServices, err := hc.dbProvider.Pagination(&models.Service{}, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	var services = make([]*models.Service, 0, len(iServices))
+	for _, iService := range iServices {
+		service, ok := iService.(*models.Service)
+		if !ok || service.IsDeleted() || service.Paused {
+			continue
+		}
+		services = append(services, service)
+	}
+
+	return services, nil
+}
+
+// Report - accept agent check result and apply quorum decision to service status,
+// service is dead only when quorum of active agents reported failure
+func (hc *HealthCheck) Report(ctx context.Context, agentID int64, result *models.CheckResult) error {
+	err := hc.touchAgent(agentID)
+	if err != nil {
+		return err
+	}
+
+	iService, err := hc.dbProvider.GetByID(&models.Service{}, int64(result.ServiceID))
+	if err != nil {
+		return err
+	}
+
+	service, ok := iService.(*models.Service)
+	if !ok || service.IsDeleted() {
+		return sql.ErrNoRows
+	}
+
+	if service.Paused {
+		return nil
+	}
+
+	// clock of agent is not trusted, freshness and order of results are by time server receives them
+	result.CheckedAt = time.Now()
+
+	decision := hc.agents.decide(
+		service,
+		agentID,
+		result,
+		hc.cfg.Agents.Quorum,
+		time.Duration(hc.cfg.Agents.TTL)*time.Second,
+	)
+	if decision == nil {
+		return nil
+	}
+
+	window := hc.activeMaintenance(service)
+	if window != nil {
+		grpclog.Infof(
+			"health-check: service %s in maintenance %d, agents result %v not applied",
+			service.Name,
+			window.ID,
+			decision.Status,
+		)
+		return nil
+	}
+
+	hc.apply(service, decision)
+
+	return nil
+}
+
+// touchAgent - mark agent as active, inactive agent must register again
+func (hc *HealthCheck) touchAgent(agentID int64) error {
+	var r = hc.agents
+	r.Lock()
+	defer r.Unlock()
+
+	a, ok := r.agents[agentID]
+	if !ok || a.local {
+		return consts.ErrUnknownAgent
+	}
+
+	var ttl = time.Duration(hc.cfg.Agents.TTL) * time.Second
+	if time.Since(a.seenAt) > ttl {
+		r.remove(agentID)
+		return consts.ErrUnknownAgent
+	}
+
+	a.seenAt = time.Now()
+
+	return nil
+}
+
+// remove - remove agent and its results, registry must be locked
+func (r *agentRegistry) remove(agentID int64) {
+	delete(r.agents, agentID)
+	for _, reports := range r.reports {
+		delete(reports, agentID)
+	}
+}
+
+// decide - save agent result and count fresh results of active agents,
+// returns failure if failures reach quorum, last success if they do not, nil if there is no success to apply
+func (r *agentRegistry) decide(
+	service *models.Service,
+	agentID int64,
+	result *models.CheckResult,
+	quorum int,
+	ttl time.Duration,
+) *models.CheckResult {
+	r.Lock()
+	defer r.Unlock()
+
+	reports, ok := r.reports[service.ID]
+	if !ok {
+		reports = make(map[int64]*models.CheckResult)
+		r.reports[service.ID] = reports
+	}
+	reports[agentID] = result
+
+	var active int
+	for _, a := range r.agents {
+		if a.active(ttl) {
+			active++
+		}
+	}
+
+	var need = quorum
+	if need <= 0 {
+		need = active/2 + 1
+	}
+
+	// result older than two check periods is out of date
+	var maxAge = 2 * time.Duration(service.Frequency) * time.Second
+	var failures int
+	var lastFailure, lastSuccess *models.CheckResult
+	for id, report := range reports {
+		a, ok := r.agents[id]
+		if !ok || !a.active(ttl) || time.Since(report.CheckedAt) > maxAge {
+			continue
+		}
+
+		if report.Success() {
+			if lastSuccess == nil || report.CheckedAt.After(lastSuccess.CheckedAt) {
+				lastSuccess = report
+			}
+			continue
+		}
+
+		failures++
+		if lastFailure == nil || report.CheckedAt.After(lastFailure.CheckedAt) {
+			lastFailure = report
+		}
+	}
+
+	if failures >= need {
+		var decision = *lastFailure
+		decision.Error = fmt.Sprintf("%d of %d agents: %s", failures, active, lastFailure.Error)
+		return &decision
+	}
+
+	return lastSuccess
+}
@@ -0,0 +1,199 @@
+package healtchecker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+)
+
+func TestAgentRegistry_Decide(t *testing.T) {
+	var now = time.Now()
+	var service = &models.Service{ID: 1, Frequency: 60}
+
+	success := func() *models.CheckResult {
+		return &models.CheckResult{ServiceID: 1, Status: models.CheckSuccess, CheckedAt: now}
+	}
+	failure := func() *models.CheckResult {
+		return &models.CheckResult{ServiceID: 1, Status: models.CheckFailure, Error: "refused", CheckedAt: now}
+	}
+	stale := func() *models.CheckResult {
+		return &models.CheckResult{ServiceID: 1, Status: models.CheckFailure, CheckedAt: now.Add(-time.Hour)}
+	}
+
+	tests := []struct {
+		name       string
+		quorum     int
+		agents     int
+		reports    map[int64]*models.CheckResult // previous results by agent id
+		report     *models.CheckResult           // result of agent 1
+		wantStatus models.CheckStatus            // 0 if nothing to apply
+	}{
+		{
+			name:       "single failure is not quorum",
+			agents:     3,
+			reports:    map[int64]*models.CheckResult{2: success()},
+			report:     failure(),
+			wantStatus: models.CheckSuccess,
+		},
+		{
+			name:    "single failure without success",
+			agents:  3,
+			reports: map[int64]*models.CheckResult{},
+			report:  failure(),
+		},
+		{
+			name:       "majority failure",
+			agents:     3,
+			reports:    map[int64]*models.CheckResult{2: failure(), 3: success()},
+			report:     failure(),
+			wantStatus: models.CheckFailure,
+		},
+		{
+			name:    "stale failure is not counted",
+			agents:  3,
+			reports: map[int64]*models.CheckResult{2: stale()},
+			report:  failure(),
+		},
+		{
+			name:       "configured quorum",
+			quorum:     1,
+			agents:     3,
+			reports:    map[int64]*models.CheckResult{2: success()},
+			report:     failure(),
+			wantStatus: models.CheckFailure,
+		},
+		{
+			name:       "single agent",
+			agents:     1,
+			reports:    map[int64]*models.CheckResult{},
+			report:     failure(),
+			wantStatus: models.CheckFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newAgentRegistry()
+			for id := int64(1); id <= int64(tt.agents); id++ {
+				r.agents[id] = &agent{id: id, seenAt: now}
+			}
+			r.reports[service.ID] = tt.reports
+
+			got := r.decide(service, 1, tt.report, tt.quorum, time.Minute)
+			if tt.wantStatus == 0 {
+				if got != nil {
+					t.Errorf("decide() = %+v, want nil", got)
+				}
+				return
+			}
+
+			if got == nil || got.Status != tt.wantStatus {
+				t.Errorf("decide() = %+v, want status %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHealthCheck_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvider := provider.NewMockIDBProvider(ctrl)
+
+	hc := newTestHealthCheck()
+	hc.cfg.Agents.TTL = 60
+	hc.dbProvider = mockProvider
+
+	err := hc.Report(context.Background(), 1, &models.CheckResult{ServiceID: 1})
+	if err != consts.ErrUnknownAgent {
+		t.Fatalf("Report() of unknown agent error = %v, want %v", err, consts.ErrUnknownAgent)
+	}
+
+	first := hc.RegisterAgent("first")
+	second := hc.RegisterAgent("second")
+	if again := hc.RegisterAgent("first"); again != first {
+		t.Errorf("RegisterAgent() again = %d, want %d", again, first)
+	}
+
+	var service = &models.Service{ID: 1, Name: "service", Frequency: 60, Status: models.Alive}
+	mockProvider.EXPECT().GetByID(&models.Service{}, int64(1)).Return(service, nil).Times(2)
+	// one failure of two agents is not quorum, no success to apply
+	mockProvider.EXPECT().GetDB().Return(nil).AnyTimes()
+	mockProvider.EXPECT().Update(&models.Service{Status: models.Dead}, 1).Return(nil)
+
+	// agent clocks are skewed, results are fresh by time they are received
+	var failure = &models.CheckResult{ServiceID: 1, Status: models.CheckFailure, CheckedAt: time.Now().Add(-time.Hour)}
+	err = hc.Report(context.Background(), first, failure)
+	if err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+
+	var future = &models.CheckResult{ServiceID: 1, Status: models.CheckFailure, CheckedAt: time.Now().Add(time.Hour)}
+	err = hc.Report(context.Background(), second, future)
+	if err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+
+	if service.Status != models.Dead {
+		t.Errorf("service status = %v, want %v", service.Status, models.Dead)
+	}
+
+	if checkedAt := hc.agents.reports[service.ID][second].CheckedAt; checkedAt.After(time.Now()) {
+		t.Errorf("report checked at agent time %v, want time of receiving", checkedAt)
+	}
+}
+
+func TestHealthCheck_LocalVote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvider := provider.NewMockIDBProvider(ctrl)
+
+	hc := newTestHealthCheck()
+	hc.cfg.Agents.Token = "token"
+	hc.cfg.Agents.TTL = 60
+	hc.dbProvider = mockProvider
+	hc.agents.addLocal()
+
+	if _, err := hc.AgentServices(localAgentID); err != consts.ErrUnknownAgent {
+		t.Errorf("AgentServices() of local agent id error = %v, want %v", err, consts.ErrUnknownAgent)
+	}
+
+	remote := hc.RegisterAgent("local")
+	if remote == localAgentID {
+		t.Fatalf("RegisterAgent() returns local agent id")
+	}
+
+	var service = &models.Service{ID: 1, Name: "service", Link: "http://127.0.0.1:1", Frequency: 60, Status: models.Alive}
+	mockProvider.EXPECT().GetByID(&models.Service{}, int64(1)).Return(service, nil).AnyTimes()
+	mockProvider.EXPECT().GetDB().Return(nil).AnyTimes()
+
+	// local failure is one vote of two, service status is not changed
+	result, err := hc.CheckNow(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("CheckNow() error: %v", err)
+	}
+	if result.Success() {
+		t.Fatalf("CheckNow() of closed port succeeded")
+	}
+	if service.Status != models.Alive {
+		t.Errorf("service status after local failure = %v, want %v", service.Status, models.Alive)
+	}
+
+	// failure of remote agent completes quorum
+	mockProvider.EXPECT().Update(&models.Service{Status: models.Dead}, 1).Return(nil)
+
+	var failure = &models.CheckResult{ServiceID: 1, Status: models.CheckFailure, CheckedAt: time.Now()}
+	if err = hc.Report(context.Background(), remote, failure); err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+
+	if service.Status != models.Dead {
+		t.Errorf("service status = %v, want %v", service.Status, models.Dead)
+	}
+}
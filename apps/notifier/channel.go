@@ -0,0 +1,149 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"projectionist/models"
+)
+
+const (
+	// SignatureHeader - header of webhook request with hex HMAC-SHA256 of body signed by channel secret
+	SignatureHeader = "X-Projectionist-Signature"
+	signaturePrefix = "sha256="
+	telegramMethod  = "/bot%s/sendMessage"
+)
+
+// Channel - destination of service notifications
+type Channel interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// WebhookPayload - body of generic webhook request
+type WebhookPayload struct {
+	Service string                   `json:"service"`
+	Event   models.NotificationEvent `json:"event"`
+	Subject string                   `json:"subject"`
+	Message string                   `json:"message"`
+	Time    time.Time                `json:"time"`
+}
+
+// NewChannel - channel by type of channel settings
+func NewChannel(channel models.Channel, client *http.Client) (Channel, error) {
+	switch channel.Type {
+	case models.ChannelWebhook:
+		return &webhookChannel{client: client, url: channel.URL, secret: channel.Secret}, nil
+	case models.ChannelSlack:
+		return &slackChannel{client: client, url: channel.URL}, nil
+	case models.ChannelTelegram:
+		return &telegramChannel{client: client, url: channel.URL, token: channel.Secret, chatID: channel.ChatID}, nil
+	}
+
+	return nil, fmt.Errorf("unknown channel type: %s", channel.Type)
+}
+
+// Sign - hex HMAC-SHA256 of body with secret as sent in SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+type webhookChannel struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func (c *webhookChannel) Send(ctx context.Context, message Message) error {
+	body, err := json.Marshal(WebhookPayload{
+		Service: message.Service,
+		Event:   message.Event,
+		Subject: message.Subject,
+		Message: message.Body,
+		Time:    time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var header = http.Header{}
+	if c.secret != "" {
+		header.Set(SignatureHeader, Sign(c.secret, body))
+	}
+
+	return postJSON(ctx, c.client, c.url, header, body)
+}
+
+type slackChannel struct {
+	client *http.Client
+	url    string
+}
+
+func (c *slackChannel) Send(ctx context.Context, message Message) error {
+	body, err := json.Marshal(map[string]string{
+		"text": message.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, c.client, c.url, nil, body)
+}
+
+type telegramChannel struct {
+	client *http.Client
+	url    string
+	token  string
+	chatID string
+}
+
+func (c *telegramChannel) Send(ctx context.Context, message Message) error {
+	body, err := json.Marshal(map[string]string{
+		"chat_id": c.chatID,
+		"text":    message.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	var link = strings.TrimRight(c.url, "/") + fmt.Sprintf(telegramMethod, c.token)
+
+	return postJSON(ctx, c.client, link, nil, body)
+}
+
+// postJSON - send json body, any not 2xx respond is error
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("channel respond status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
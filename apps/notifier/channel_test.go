@@ -0,0 +1,133 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"projectionist/models"
+)
+
+type stubRequest struct {
+	path      string
+	signature string
+	body      map[string]interface{}
+}
+
+func newStub(t *testing.T, status int, requests chan<- stubRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body error: %v", err)
+		}
+
+		var body = map[string]interface{}{}
+		err = json.Unmarshal(raw, &body)
+		if err != nil {
+			t.Errorf("body is not json: %s", raw)
+		}
+
+		var signature = r.Header.Get(SignatureHeader)
+		if signature != "" && signature != Sign("secret", raw) {
+			t.Errorf("invalid signature %s", signature)
+		}
+
+		requests <- stubRequest{path: r.URL.Path, signature: signature, body: body}
+		w.WriteHeader(status)
+	}))
+}
+
+func TestChannel_Send(t *testing.T) {
+	tests := []struct {
+		name      string
+		channel   models.Channel
+		status    int
+		wantErr   bool
+		wantPath  string
+		wantField string
+		wantValue string
+		wantSign  bool
+	}{
+		{
+			name:      "signed webhook",
+			channel:   models.Channel{Type: models.ChannelWebhook, Secret: "secret"},
+			status:    http.StatusOK,
+			wantPath:  "/",
+			wantField: "service",
+			wantValue: "api",
+			wantSign:  true,
+		},
+		{
+			name:      "webhook without secret",
+			channel:   models.Channel{Type: models.ChannelWebhook},
+			status:    http.StatusNoContent,
+			wantPath:  "/",
+			wantField: "message",
+			wantValue: "Service api notification: down",
+		},
+		{
+			name:      "slack",
+			channel:   models.Channel{Type: models.ChannelSlack},
+			status:    http.StatusOK,
+			wantPath:  "/",
+			wantField: "text",
+			wantValue: "Service api notification: down",
+		},
+		{
+			name:      "telegram",
+			channel:   models.Channel{Type: models.ChannelTelegram, Secret: "123:abc", ChatID: "-42"},
+			status:    http.StatusOK,
+			wantPath:  "/bot123:abc/sendMessage",
+			wantField: "chat_id",
+			wantValue: "-42",
+		},
+		{
+			name:     "error status",
+			channel:  models.Channel{Type: models.ChannelSlack},
+			status:   http.StatusBadRequest,
+			wantErr:  true,
+			wantPath: "/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests = make(chan stubRequest, 1)
+			server := newStub(t, tt.status, requests)
+			defer server.Close()
+
+			tt.channel.URL = server.URL
+			channel, err := NewChannel(tt.channel, server.Client())
+			if err != nil {
+				t.Fatalf("NewChannel() error = %v", err)
+			}
+
+			err = channel.Send(context.Background(), Message{Service: "api", Body: "Service api notification: down"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			req := <-requests
+			if req.path != tt.wantPath {
+				t.Errorf("path = %s, want %s", req.path, tt.wantPath)
+			}
+
+			if (req.signature != "") != tt.wantSign {
+				t.Errorf("signature = %q, want signed %v", req.signature, tt.wantSign)
+			}
+
+			if tt.wantField != "" && req.body[tt.wantField] != tt.wantValue {
+				t.Errorf("body[%s] = %v, want %s", tt.wantField, req.body[tt.wantField], tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestNewChannel_Unknown(t *testing.T) {
+	_, err := NewChannel(models.Channel{Type: "pager"}, http.DefaultClient)
+	if err == nil {
+		t.Error("NewChannel() of unknown type must fail")
+	}
+}
@@ -0,0 +1,105 @@
+package apps
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"projectionist/models"
+)
+
+// digestSubject - subject of digest with count of batched notifications
+const digestSubject = "%d notifications"
+
+// recipientLimits - digest and rate limit settings of recipient
+type recipientLimits struct {
+	digestWindow time.Duration
+	rateLimit    int
+	rateWindow   time.Duration
+}
+
+// batched - true if several due emails to recipient are sent as one digest
+func (l recipientLimits) batched() bool {
+	return l.digestWindow > 0 || l.rateLimit > 0
+}
+
+// limits - recipient settings overrides notifier config
+func (n *Notifier) limits(recipient string) (recipientLimits, error) {
+	var limits = recipientLimits{
+		digestWindow: time.Duration(n.cfg.DigestWindow) * time.Second,
+		rateLimit:    n.cfg.RateLimit,
+		rateWindow:   time.Duration(n.cfg.RateWindow) * time.Second,
+	}
+
+	var settings = models.RecipientSettings{}
+	err := settings.GetByName(n.db, recipient)
+	if err == sql.ErrNoRows {
+		return limits, nil
+	}
+
+	if err != nil {
+		return limits, fmt.Errorf("recipient %s settings: %v", recipient, err)
+	}
+
+	if settings.DigestWindow != nil {
+		limits.digestWindow = time.Duration(*settings.DigestWindow) * time.Second
+	}
+
+	if settings.RateLimit != nil {
+		limits.rateLimit = *settings.RateLimit
+	}
+
+	return limits, nil
+}
+
+// rateLimited - time when next email to recipient is allowed, nil if email is allowed now
+func (n *Notifier) rateLimited(recipient string, limits recipientLimits, now time.Time) (*time.Time, error) {
+	if limits.rateLimit <= 0 || limits.rateWindow <= 0 {
+		return nil, nil
+	}
+
+	sent, oldest, err := models.CountSentDeliveries(n.db, recipient, now.Add(-limits.rateWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	if sent < limits.rateLimit || oldest == nil {
+		return nil, nil
+	}
+
+	var allowedAt = oldest.Add(limits.rateWindow)
+
+	return &allowedAt, nil
+}
+
+// digest - one email with all queued emails to recipient, digest keeps Message-ID of the first email
+func digest(deliveries []*models.Delivery) *models.Delivery {
+	var body strings.Builder
+	body.WriteString("<html lang=\"en\">\n<head>\n    <meta charset=\"UTF-8\">\n</head>\n<body>\n")
+	body.WriteString(fmt.Sprintf("<h1>"+digestSubject+"</h1>\n", len(deliveries)))
+	for _, delivery := range deliveries {
+		body.WriteString("<h2>" + html.EscapeString(delivery.Subject) + "</h2>\n")
+		body.WriteString("<div>" + htmlBodyContent(delivery.Body) + "</div>\n")
+	}
+	body.WriteString("</body>\n</html>\n")
+
+	var first = *deliveries[0]
+	first.Subject = fmt.Sprintf(digestSubject, len(deliveries))
+	first.Body = body.String()
+
+	return &first
+}
+
+// htmlBodyContent - content of body element if email is whole html document
+func htmlBodyContent(document string) string {
+	var lower = strings.ToLower(document)
+	start := strings.Index(lower, "<body>")
+	end := strings.LastIndex(lower, "</body>")
+	if start < 0 || end < start {
+		return document
+	}
+
+	return strings.TrimSpace(document[start+len("<body>") : end])
+}
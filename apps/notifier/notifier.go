@@ -1,66 +1,90 @@
 package apps
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"net/http"
 	"net/smtp"
-	"strconv"
+	"time"
 
 	"projectionist/config"
-)
-
-const (
-	SubjectTemplate = "Service %s notification"
-	BodyHtmlPtrn    = `
-
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <title>%s</title>
-</head>
-<body>
-    <h1>%s</h1>
-    Service %s notification: %s.
-</body>
-</html>
-`
-	MsgTmpl = "To: %s\r\n" +
-		"Subject: %s\r\n" +
-		"MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n" +
-		"%s\r\n"
+	"projectionist/models"
 )
 
 type Notifier struct {
-	cfg  *config.NotifierConfig
-	auth smtp.Auth
-	Body string
+	cfg    *config.NotifierConfig
+	auth   smtp.Auth
+	db     *sql.DB      // outbound email queue
+	client *http.Client // client of notification channels
 }
 
-func NewNotifier(cfg *config.Config) *Notifier {
-	auth := smtp.PlainAuth("", cfg.NotifierConfig.From, cfg.NotifierConfig.Password, cfg.NotifierConfig.Address)
+func NewNotifier(cfg *config.Config, db *sql.DB) *Notifier {
+	var auth smtp.Auth
+	if cfg.NotifierConfig.Password != "" {
+		auth = smtp.PlainAuth("", cfg.NotifierConfig.From, cfg.NotifierConfig.Password, cfg.NotifierConfig.Address)
+	}
+
 	notifier := &Notifier{
-		cfg:  &cfg.NotifierConfig,
-		auth: auth,
-		Body: BodyHtmlPtrn,
+		cfg:    &cfg.NotifierConfig,
+		auth:   auth,
+		db:     db,
+		client: &http.Client{Timeout: time.Duration(cfg.NotifierConfig.ChannelTimeout) * time.Second},
 	}
 
 	return notifier
 }
 
-// TODO: test this
-func (n *Notifier) Send(to []string, serviceName, message string) error {
-	addr := n.cfg.Address + strconv.Itoa(n.cfg.Port)
-
-	for _, toEmail := range to {
-		subject := fmt.Sprintf(SubjectTemplate, serviceName)
-		htmlBody := fmt.Sprintf(BodyHtmlPtrn, subject, subject, serviceName, message)
+// Send - put email of every recipient to outbound queue, emails are delivered by Run,
+// email to recipient in digest mode waits for the end of digest window
+func (n *Notifier) Send(to []string, message Message) error {
+	if n.db == nil {
+		return fmt.Errorf("outbound queue database is empty")
+	}
 
-		msg := fmt.Sprintf(MsgTmpl, toEmail, subject, htmlBody)
+	var now = time.Now()
+	for _, recipient := range to {
+		var delivery = &models.Delivery{
+			MessageID:     newMessageID(n.cfg.From),
+			Recipient:     recipient,
+			Subject:       message.Subject,
+			Body:          message.Body,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
 
-		err := smtp.SendMail(addr, n.auth, n.cfg.From, to, []byte(msg))
+		limits, err := n.limits(recipient)
 		if err != nil {
 			return err
 		}
+
+		if limits.digestWindow > 0 {
+			next, err := models.NextDigestAttempt(n.db, recipient)
+			if err != nil {
+				return fmt.Errorf("digest of %s: %v", recipient, err)
+			}
+
+			delivery.NextAttemptAt = now.Add(limits.digestWindow)
+			if next != nil {
+				delivery.NextAttemptAt = *next
+			}
+		}
+
+		err = delivery.Save(n.db)
+		if err != nil {
+			return fmt.Errorf("queue email to %s: %v", recipient, err)
+		}
 	}
 
 	return nil
 }
+
+// SendChannel - send message to notification channel of service
+func (n *Notifier) SendChannel(ctx context.Context, channel models.Channel, message Message) error {
+	ch, err := NewChannel(channel, n.client)
+	if err != nil {
+		return err
+	}
+
+	return ch.Send(ctx, message)
+}
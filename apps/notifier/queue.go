@@ -0,0 +1,147 @@
+package apps
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	"projectionist/models"
+)
+
+const (
+	queueBatch      = 100
+	maxRetryBackoff = time.Hour
+)
+
+// Run - deliver queued emails every queue interval until ctx is done
+func (n *Notifier) Run(ctx context.Context) {
+	if n.db == nil || n.cfg.QueueInterval <= 0 {
+		return
+	}
+
+	var ticker = time.NewTicker(time.Duration(n.cfg.QueueInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		n.processQueue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processQueue - try to deliver due emails, failed email is retried with exponential backoff
+// until attempts are exhausted or smtp server rejects it permanently.
+// Several due emails to recipient in digest mode or with rate limit are sent as one digest,
+// emails to recipient over rate limit are postponed
+func (n *Notifier) processQueue(ctx context.Context, now time.Time) {
+	deliveries, err := models.GetDueDeliveries(n.db, now, queueBatch)
+	if err != nil {
+		grpclog.Errorf("notifier: GetDueDeliveries error: %v", err)
+		return
+	}
+
+	var recipients []string
+	var byRecipient = make(map[string][]*models.Delivery)
+	for _, delivery := range deliveries {
+		if _, ok := byRecipient[delivery.Recipient]; !ok {
+			recipients = append(recipients, delivery.Recipient)
+		}
+		byRecipient[delivery.Recipient] = append(byRecipient[delivery.Recipient], delivery)
+	}
+
+	for _, recipient := range recipients {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var queued = byRecipient[recipient]
+		limits, err := n.limits(recipient)
+		if err != nil {
+			grpclog.Errorf("notifier: %v", err)
+			continue
+		}
+
+		allowedAt, err := n.rateLimited(recipient, limits, now)
+		if err != nil {
+			grpclog.Errorf("notifier: recipient %s rate limit error: %v", recipient, err)
+			continue
+		}
+
+		if allowedAt != nil {
+			n.postpone(queued, *allowedAt)
+			continue
+		}
+
+		if len(queued) > 1 && limits.batched() {
+			n.send(digest(queued), queued)
+			continue
+		}
+
+		for _, delivery := range queued {
+			n.send(delivery, []*models.Delivery{delivery})
+		}
+	}
+}
+
+// send - deliver email and save status of queued deliveries sent by it
+func (n *Notifier) send(email *models.Delivery, queued []*models.Delivery) {
+	err := n.deliver(email)
+	if err == nil {
+		var sentAt = time.Now()
+		for _, delivery := range queued {
+			if delivery.ID == email.ID {
+				err = delivery.MarkSent(n.db, sentAt)
+			} else {
+				err = delivery.MarkDigested(n.db, email.ID, sentAt)
+			}
+			if err != nil {
+				grpclog.Errorf("notifier: delivery #%d status error: %v", delivery.ID, err)
+			}
+		}
+		return
+	}
+
+	grpclog.Errorf("notifier: delivery #%d to %s attempt %d error: %v",
+		email.ID, email.Recipient, email.Attempts+1, err)
+
+	for _, delivery := range queued {
+		var statusErr error
+		if isPermanent(err) || delivery.Attempts+1 >= n.cfg.Attempts {
+			statusErr = delivery.MarkFailed(n.db, err)
+		} else {
+			statusErr = delivery.MarkRetry(n.db, err, time.Now().Add(n.retryBackoff(delivery.Attempts+1)))
+		}
+		if statusErr != nil {
+			grpclog.Errorf("notifier: delivery #%d status error: %v", delivery.ID, statusErr)
+		}
+	}
+}
+
+// postpone - move deliveries over rate limit to time when recipient is allowed to get email
+func (n *Notifier) postpone(deliveries []*models.Delivery, at time.Time) {
+	for _, delivery := range deliveries {
+		err := delivery.Postpone(n.db, at)
+		if err != nil {
+			grpclog.Errorf("notifier: delivery #%d Postpone error: %v", delivery.ID, err)
+		}
+	}
+}
+
+// retryBackoff - delay after failed attempt, doubled on each next attempt
+func (n *Notifier) retryBackoff(attempts int) time.Duration {
+	var backoff = time.Duration(n.cfg.RetryBackoff) * time.Second
+	for i := 1; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,153 @@
+package apps
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"projectionist/models"
+)
+
+// smtp connection security, see config.NotifierConfig.TLS
+const (
+	TLSNone     = "none"
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+)
+
+// deliver - send queued email to its recipient
+func (n *Notifier) deliver(delivery *models.Delivery) error {
+	switch n.cfg.TLS {
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return fmt.Errorf("unknown smtp tls mode: %q", n.cfg.TLS)
+	}
+
+	var timeout = time.Duration(n.cfg.Timeout) * time.Second
+	var addr = net.JoinHostPort(n.cfg.Address, strconv.Itoa(n.cfg.Port))
+	var tlsConfig = &tls.Config{ServerName: n.cfg.Address, InsecureSkipVerify: n.cfg.TLSSkipVerify}
+
+	var dialer = &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if n.cfg.TLS == TLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Address)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if n.cfg.TLS == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", addr)
+		}
+
+		err = client.StartTLS(tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	if n.auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			err = client.Auth(n.auth)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = client.Mail(n.cfg.From)
+	if err != nil {
+		return err
+	}
+
+	err = client.Rcpt(delivery.Recipient)
+	if err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buildMail(n.cfg.From, delivery, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildMail - html email with MIME headers, body is quoted-printable
+func buildMail(from string, delivery *models.Delivery, date time.Time) []byte {
+	var buf bytes.Buffer
+	var header = func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+
+	header("From", from)
+	header("To", delivery.Recipient)
+	header("Subject", mime.QEncoding.Encode("utf-8", delivery.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", delivery.MessageID)
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/html; charset="UTF-8"`)
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	var body = quotedprintable.NewWriter(&buf)
+	body.Write([]byte(delivery.Body))
+	body.Close()
+	buf.WriteString("\r\n")
+
+	return buf.Bytes()
+}
+
+// newMessageID - unique Message-ID in domain of sender address
+func newMessageID(from string) string {
+	var domain = "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 && i < len(from)-1 {
+		domain = strings.Trim(from[i+1:], "<> ")
+	}
+
+	var random = make([]byte, 12)
+	rand.Read(random)
+
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
+
+// isPermanent - smtp server rejected email with 5xx code, retry is useless
+func isPermanent(err error) bool {
+	smtpErr, ok := err.(*textproto.Error)
+	return ok && smtpErr.Code >= 500
+}
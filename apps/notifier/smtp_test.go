@@ -0,0 +1,331 @@
+package apps
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"projectionist/config"
+	"projectionist/db"
+	"projectionist/models"
+)
+
+// smtpStub - local smtp server, RCPT of recipient from reject is answered by its reply
+type smtpStub struct {
+	listener net.Listener
+	reject   map[string]string
+	sync.Mutex
+	mails []string
+}
+
+func newSmtpStub(t *testing.T, reject map[string]string) *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	stub := &smtpStub{listener: listener, reject: reject}
+	go stub.serve()
+
+	return stub
+}
+
+func (s *smtpStub) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.session(conn)
+	}
+}
+
+func (s *smtpStub) session(conn net.Conn) {
+	defer conn.Close()
+
+	var r = bufio.NewReader(conn)
+	var reply = func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 stub ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		var cmd = strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-stub")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "RCPT"):
+			var answer = "250 ok"
+			for recipient, rejectReply := range s.reject {
+				if strings.Contains(cmd, strings.ToUpper(recipient)) {
+					answer = rejectReply
+				}
+			}
+			reply(answer)
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var mail strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				mail.WriteString(dataLine)
+			}
+			s.Lock()
+			s.mails = append(s.mails, mail.String())
+			s.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *smtpStub) received() []string {
+	s.Lock()
+	defer s.Unlock()
+
+	return append([]string(nil), s.mails...)
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func newQueueDB(t *testing.T) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "notifier")
+	if err != nil {
+		t.Fatalf("temp dir error: %v", err)
+	}
+
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(dir, "test.sqlite"))
+	if err != nil {
+		t.Fatalf("open db error: %v", err)
+	}
+
+	err = db.InitTables(sqlDB)
+	if err != nil {
+		t.Fatalf("init tables error: %v", err)
+	}
+
+	return sqlDB, func() {
+		sqlDB.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestNotifier_ProcessQueue(t *testing.T) {
+	stub := newSmtpStub(t, map[string]string{
+		"busy@example.com":    "451 try again later",
+		"unknown@example.com": "550 no such user",
+	})
+	defer stub.listener.Close()
+
+	sqlDB, closeDB := newQueueDB(t)
+	defer closeDB()
+
+	n := NewNotifier(&config.Config{NotifierConfig: config.NotifierConfig{
+		Address:      "127.0.0.1",
+		Port:         stub.port(),
+		From:         "projectionist@example.com",
+		TLS:          TLSNone,
+		Timeout:      5,
+		Attempts:     2,
+		RetryBackoff: 30,
+	}}, sqlDB)
+
+	err := n.Send(
+		[]string{"ok@example.com", "busy@example.com", "unknown@example.com"},
+		Message{Subject: "Service api notification", Body: "<p>service is down</p>"},
+	)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var now = time.Now()
+	n.processQueue(context.Background(), now)
+
+	want := map[string]struct {
+		status   models.DeliveryStatus
+		attempts int
+	}{
+		"ok@example.com":      {models.DeliverySent, 1},
+		"busy@example.com":    {models.DeliveryPending, 1},
+		"unknown@example.com": {models.DeliveryFailed, 1},
+	}
+	checkDeliveries(t, sqlDB, want)
+
+	mails := stub.received()
+	if len(mails) != 1 {
+		t.Fatalf("stub received %d mails, want 1", len(mails))
+	}
+
+	for _, header := range []string{
+		"From: projectionist@example.com\r\n",
+		"To: ok@example.com\r\n",
+		"Subject: Service api notification\r\n",
+		"Date: ",
+		"Message-ID: <",
+		"MIME-Version: 1.0\r\n",
+		"Content-Transfer-Encoding: quoted-printable\r\n",
+	} {
+		if !strings.Contains(mails[0], header) {
+			t.Errorf("mail without header %q:\n%s", header, mails[0])
+		}
+	}
+
+	// retry is not due before backoff
+	n.processQueue(context.Background(), now.Add(10*time.Second))
+	checkDeliveries(t, sqlDB, want)
+
+	// last attempt
+	n.processQueue(context.Background(), now.Add(time.Minute))
+	want["busy@example.com"] = struct {
+		status   models.DeliveryStatus
+		attempts int
+	}{models.DeliveryFailed, 2}
+	checkDeliveries(t, sqlDB, want)
+}
+
+func checkDeliveries(t *testing.T, sqlDB *sql.DB, want map[string]struct {
+	status   models.DeliveryStatus
+	attempts int
+}) {
+	t.Helper()
+
+	deliveries, err := models.GetDeliveries(sqlDB, models.DeliveryFilter{}, 0, 10)
+	if err != nil {
+		t.Fatalf("GetDeliveries() error = %v", err)
+	}
+
+	if len(deliveries) != len(want) {
+		t.Fatalf("got %d deliveries, want %d", len(deliveries), len(want))
+	}
+
+	for _, delivery := range deliveries {
+		w := want[delivery.Recipient]
+		if delivery.Status != w.status || delivery.Attempts != w.attempts {
+			t.Errorf("delivery to %s status %v attempts %d, want %v attempts %d (last error %q)",
+				delivery.Recipient, delivery.Status, delivery.Attempts, w.status, w.attempts, delivery.LastError)
+		}
+	}
+}
+
+func TestNotifier_RetryBackoff(t *testing.T) {
+	n := &Notifier{cfg: &config.NotifierConfig{RetryBackoff: 30}}
+
+	for attempts, want := range map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		4:  4 * time.Minute,
+		20: maxRetryBackoff,
+	} {
+		if got := n.retryBackoff(attempts); got != want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestNotifier_DigestAndRateLimit(t *testing.T) {
+	stub := newSmtpStub(t, nil)
+	defer stub.listener.Close()
+
+	sqlDB, closeDB := newQueueDB(t)
+	defer closeDB()
+
+	var digestWindow = 300
+	err := (&models.RecipientSettings{Email: "digest@example.com", DigestWindow: &digestWindow}).Save(sqlDB)
+	if err != nil {
+		t.Fatalf("save recipient settings error: %v", err)
+	}
+
+	n := NewNotifier(&config.Config{NotifierConfig: config.NotifierConfig{
+		Address:    "127.0.0.1",
+		Port:       stub.port(),
+		From:       "projectionist@example.com",
+		TLS:        TLSNone,
+		Timeout:    5,
+		Attempts:   3,
+		RateLimit:  1,
+		RateWindow: 3600,
+	}}, sqlDB)
+
+	var send = func(to, service string) {
+		err := n.Send([]string{to}, Message{
+			Subject: "Service " + service + " notification",
+			Body:    "<html><body>service " + service + " is down</body></html>",
+		})
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	send("limited@example.com", "api")
+	for _, service := range []string{"api", "web", "db"} {
+		send("digest@example.com", service)
+	}
+
+	// digest waits for the end of window, limited recipient gets the first email
+	var now = time.Now()
+	n.processQueue(context.Background(), now)
+	mails := stub.received()
+	if len(mails) != 1 || !strings.Contains(mails[0], "To: limited@example.com") {
+		t.Fatalf("got %d mails, want 1 to limited recipient: %v", len(mails), mails)
+	}
+
+	// emails over rate limit are postponed
+	send("limited@example.com", "web")
+	send("limited@example.com", "db")
+	n.processQueue(context.Background(), time.Now())
+
+	pending, err := models.CountDeliveries(sqlDB, models.DeliveryFilter{Status: models.DeliveryPending})
+	if err != nil || pending != 5 {
+		t.Fatalf("pending deliveries = %d (%v), want 5", pending, err)
+	}
+
+	n.processQueue(context.Background(), now.Add(10*time.Minute))
+	mails = stub.received()
+	if len(mails) != 2 || !strings.Contains(mails[1], "To: digest@example.com") {
+		t.Fatalf("got %d mails, want digest as second: %v", len(mails), mails)
+	}
+
+	for _, want := range []string{"Subject: 3 notifications", "service api is down", "service db is down"} {
+		if !strings.Contains(mails[1], want) {
+			t.Errorf("digest without %q:\n%s", want, mails[1])
+		}
+	}
+
+	// rate window is passed, postponed emails are sent as one digest
+	n.processQueue(context.Background(), now.Add(2*time.Hour))
+	mails = stub.received()
+	if len(mails) != 3 || !strings.Contains(mails[2], "Subject: 2 notifications") {
+		t.Fatalf("got %d mails, want digest of postponed emails as third: %v", len(mails), mails)
+	}
+
+	pending, err = models.CountDeliveries(sqlDB, models.DeliveryFilter{Status: models.DeliveryPending})
+	if err != nil || pending != 0 {
+		t.Errorf("pending deliveries = %d (%v), want 0", pending, err)
+	}
+}
@@ -0,0 +1,134 @@
+package apps
+
+import (
+	"bytes"
+	"time"
+
+	"projectionist/models"
+)
+
+const (
+	// DefaultSubject - subject of notifications without customized template
+	DefaultSubject = "Service {{.Service.Name}} notification"
+	// DefaultText - body of not email notifications without customized template
+	DefaultText = "Service {{.Service.Name}} notification: {{.Message}}"
+	// DefaultHtml - body of email notifications without customized template
+	DefaultHtml = `
+
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Service {{.Service.Name}} notification</title>
+</head>
+<body>
+    <h1>Service {{.Service.Name}} notification</h1>
+    Service {{.Service.Name}} notification: {{.Message}}.
+</body>
+</html>
+`
+)
+
+// Message - rendered notification
+type Message struct {
+	Service string
+	Event   models.NotificationEvent
+	Subject string
+	Body    string
+}
+
+// DefaultTemplate - template of channel notifications about event used if template is not customized
+func DefaultTemplate(channel models.ChannelType, event models.NotificationEvent) models.NotificationTemplate {
+	var tmpl = models.NotificationTemplate{
+		Channel: channel,
+		Event:   event,
+		Subject: DefaultSubject,
+		Body:    DefaultText,
+	}
+
+	if channel == models.ChannelEmail {
+		tmpl.Body = DefaultHtml
+	}
+
+	return tmpl
+}
+
+// Render - execute subject and body templates with notification data
+func Render(tmpl models.NotificationTemplate, data *models.NotificationData) (Message, error) {
+	subject, body, err := tmpl.Parse()
+	if err != nil {
+		return Message{}, err
+	}
+
+	var subjectBuf, bodyBuf bytes.Buffer
+	err = subject.Execute(&subjectBuf, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	err = body.Execute(&bodyBuf, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Service: data.Service.Name,
+		Event:   data.Event,
+		Subject: subjectBuf.String(),
+		Body:    bodyBuf.String(),
+	}, nil
+}
+
+// SampleData - notification data of example service used for templates preview
+func SampleData(event models.NotificationEvent) *models.NotificationData {
+	var now = time.Now().UTC().Truncate(time.Second)
+	var startedAt = now.Add(-20 * time.Minute)
+	var certExpiresAt = now.Add(10 * 24 * time.Hour)
+
+	var data = &models.NotificationData{
+		Event: event,
+		Service: &models.Service{
+			ID:            1,
+			Name:          "example",
+			Link:          "https://example.com/health",
+			Frequency:     60,
+			Status:        models.Dead,
+			CertExpiresAt: &certExpiresAt,
+		},
+		Incident: &models.Incident{ID: 1, ServiceID: 1, Reason: "connection refused", StartedAt: startedAt},
+		Result: &models.CheckResult{
+			ServiceID: 1,
+			Status:    models.CheckFailure,
+			Error:     "connection refused",
+			Attempts:  3,
+			Duration:  120 * time.Millisecond,
+			CheckedAt: now,
+		},
+		Message: "incident #1: service is down (failure): connection refused",
+		Time:    now,
+	}
+
+	switch event {
+	case models.EventRecovered:
+		var endedAt = now
+		data.Service.Status = models.Alive
+		data.Incident.EndedAt = &endedAt
+		data.Incident.Duration = int(now.Sub(startedAt).Seconds())
+		data.Result.Status = models.CheckSuccess
+		data.Result.Error = ""
+		data.Result.Attempts = 1
+		data.Message = "incident #1 resolved after 20m0s: service is alive again"
+	case models.EventCertExpiring:
+		data.Service.Status = models.Warning
+		data.Incident = nil
+		data.Result.Status = models.CheckSuccess
+		data.Result.Error = ""
+		data.Result.Attempts = 1
+		data.Result.CertExpiresAt = &certExpiresAt
+		data.Message = "link certificate expires at " + certExpiresAt.Format(time.RFC1123)
+	case models.EventEscalation:
+		data.Result = nil
+		data.Message = "incident #1: not acknowledged for 20m0s, connection refused"
+	}
+
+	return data
+}
@@ -0,0 +1,86 @@
+package apps
+
+import (
+	"strings"
+	"testing"
+
+	"projectionist/models"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name        string
+		tmpl        models.NotificationTemplate
+		data        *models.NotificationData
+		wantSubject string
+		wantBody    string
+		wantErr     bool
+	}{
+		{
+			name:        "default text",
+			tmpl:        DefaultTemplate(models.ChannelSlack, models.EventDown),
+			data:        SampleData(models.EventDown),
+			wantSubject: "Service example notification",
+			wantBody:    "Service example notification: incident #1: service is down (failure): connection refused",
+		},
+		{
+			name:        "default email",
+			tmpl:        DefaultTemplate(models.ChannelEmail, models.EventRecovered),
+			data:        SampleData(models.EventRecovered),
+			wantSubject: "Service example notification",
+			wantBody:    "incident #1 resolved after 20m0s: service is alive again.",
+		},
+		{
+			name: "custom with incident and result",
+			tmpl: models.NotificationTemplate{
+				Channel: models.ChannelTelegram,
+				Event:   models.EventDown,
+				Subject: "[{{.Event}}] {{.Service.Name}}",
+				Body:    "#{{.Incident.ID}} {{.Service.Link}} {{.Result.Status}} after {{.Result.Attempts}} attempts",
+			},
+			data:        SampleData(models.EventDown),
+			wantSubject: "[down] example",
+			wantBody:    "#1 https://example.com/health failure after 3 attempts",
+		},
+		{
+			name: "email body is escaped",
+			tmpl: models.NotificationTemplate{
+				Channel: models.ChannelEmail,
+				Event:   models.EventDown,
+				Body:    "<p>{{.Message}}</p>",
+			},
+			data:     &models.NotificationData{Service: &models.Service{Name: "api"}, Message: "<script>"},
+			wantBody: "<p>&lt;script&gt;</p>",
+		},
+		{
+			name: "missing incident",
+			tmpl: models.NotificationTemplate{
+				Channel: models.ChannelWebhook,
+				Event:   models.EventCertExpiring,
+				Body:    "{{.Incident.ID}}",
+			},
+			data:    SampleData(models.EventCertExpiring),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.tmpl, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.Subject != tt.wantSubject {
+				t.Errorf("Render() subject = %q, want %q", got.Subject, tt.wantSubject)
+			}
+
+			if !strings.Contains(got.Body, tt.wantBody) {
+				t.Errorf("Render() body = %q, want containing %q", got.Body, tt.wantBody)
+			}
+		})
+	}
+}
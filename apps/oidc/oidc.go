@@ -0,0 +1,393 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"projectionist/config"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// StateTTL - time to log in at provider, it is lifetime of state cookie
+	StateTTL = 10 * time.Minute
+	// clockSkew - allowed difference of provider and local clocks
+	clockSkew = time.Minute
+)
+
+var (
+	// ErrState - callback state does not match state of login started by the same browser
+	ErrState = fmt.Errorf("invalid oidc state")
+	// ErrIDToken - id token is missing, not signed by provider or issued to other client
+	ErrIDToken = fmt.Errorf("invalid oidc id token")
+)
+
+// Provider - OpenID Connect provider of single sign-on by authorization code flow
+type Provider struct {
+	cfg    *config.OIDCCfg
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey // signing keys by key id
+}
+
+// discovery - endpoints of provider from its discovery document
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Identity - user claims of verified id token
+type Identity struct {
+	Subject  string
+	Username string
+	Groups   []string
+}
+
+// NewProvider - provider of config, its discovery document is requested on first use
+func NewProvider(cfg *config.OIDCCfg, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Provider{cfg: cfg, client: client}
+}
+
+// AuthCodeURL - login page of provider, it redirects back to callback url with code and state
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var query = url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+
+	var sep = "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return d.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange - identity of user by authorization code, nonce must match nonce of login request
+func (p *Provider) Exchange(ctx context.Context, code, nonce string, now time.Time) (*Identity, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var form = url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+
+	req, err := http.NewRequest(http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err = p.do(req, &tokens); err != nil {
+		return nil, fmt.Errorf("token request error: %v", err)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, ErrIDToken
+	}
+
+	return p.verify(ctx, d, tokens.IDToken, nonce, now)
+}
+
+// verify - identity of id token signed by provider for this client
+func (p *Provider) verify(ctx context.Context, d *discovery, rawIDToken, nonce string, now time.Time) (*Identity, error) {
+	var claims = jwt.MapClaims{}
+	var parser = &jwt.Parser{ValidMethods: []string{"RS256", "RS384", "RS512"}, SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, d, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrIDToken, err)
+	}
+
+	if iss, _ := claims["iss"].(string); iss != d.Issuer {
+		return nil, fmt.Errorf("%v: issuer %q", ErrIDToken, iss)
+	}
+
+	if !hasAudience(claims["aud"], p.cfg.ClientID) {
+		return nil, fmt.Errorf("%v: audience %v", ErrIDToken, claims["aud"])
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.Add(-clockSkew).Unix() > int64(exp) {
+		return nil, fmt.Errorf("%v: expired", ErrIDToken)
+	}
+
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, fmt.Errorf("%v: nonce mismatch", ErrIDToken)
+	}
+
+	var identity = &Identity{Groups: stringsClaim(claims[p.cfg.GroupsClaim])}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%v: no subject", ErrIDToken)
+	}
+
+	identity.Username, _ = claims[p.cfg.UsernameClaim].(string)
+	if identity.Username == "" {
+		identity.Username, _ = claims["email"].(string)
+	}
+	if identity.Username == "" {
+		return nil, fmt.Errorf("%v: no %s claim", ErrIDToken, p.cfg.UsernameClaim)
+	}
+
+	return identity, nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var issuer = strings.TrimRight(p.cfg.Issuer, "/")
+	req, err := http.NewRequest(http.MethodGet, issuer+discoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var d = &discovery{}
+	if err = p.do(req.WithContext(ctx), d); err != nil {
+		return nil, fmt.Errorf("discovery request error: %v", err)
+	}
+
+	if strings.TrimRight(d.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", d.Issuer, p.cfg.Issuer)
+	}
+
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document of %s is incomplete", issuer)
+	}
+
+	p.discovery = d
+	return d, nil
+}
+
+// getKey - signing key by key id, keys are reloaded when provider rotates them
+func (p *Provider) getKey(ctx context.Context, d *discovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, d.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = p.do(req.WithContext(ctx), &set); err != nil {
+		return nil, fmt.Errorf("jwks request error: %v", err)
+	}
+
+	p.keys = make(map[string]*rsa.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := jwk.rsaKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q error: %v", jwk.Kid, err)
+		}
+		p.keys[jwk.Kid] = key
+	}
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey - loaded key by id, the only key is used if token has no key id
+func (p *Provider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// do - send request and decode json response
+func (p *Provider) do(req *http.Request, v interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %d: %s", req.URL.Host, resp.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// jsonWebKey - key of provider JWK set, only RSA keys are supported
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (k jsonWebKey) rsaKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	var exponent = new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid rsa key")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// hasAudience - aud claim is client id or list containing it
+func hasAudience(aud interface{}, clientID string) bool {
+	for _, a := range stringsClaim(aud) {
+		if a == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stringsClaim - claim of string or list of strings
+func stringsClaim(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var result = make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+// stateClaims - login started by browser, it is kept in cookie until callback
+type stateClaims struct {
+	State string `json:"state"`
+	Nonce string `json:"nonce"`
+	jwt.StandardClaims
+}
+
+// NewState - random state and nonce of login, they are signed into cookie value
+func NewState(secret string, now time.Time) (cookie, state, nonce string, err error) {
+	if state, err = randomString(); err != nil {
+		return "", "", "", err
+	}
+
+	if nonce, err = randomString(); err != nil {
+		return "", "", "", err
+	}
+
+	var claims = &stateClaims{State: state, Nonce: nonce, StandardClaims: jwt.StandardClaims{
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(StateTTL).Unix(),
+	}}
+	cookie, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(stateKey(secret))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return cookie, state, nonce, nil
+}
+
+// ParseState - nonce of login by cookie value and state of callback
+func ParseState(secret, cookie, state string, now time.Time) (string, error) {
+	var claims = &stateClaims{}
+	var parser = &jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Name}, SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(cookie, claims, func(token *jwt.Token) (interface{}, error) {
+		return stateKey(secret), nil
+	})
+	if err != nil || state == "" || claims.State != state || now.Unix() > claims.ExpiresAt {
+		return "", ErrState
+	}
+
+	return claims.Nonce, nil
+}
+
+// stateKey - key of state cookie, it differs from key of access tokens so cookie is never accepted as token
+func stateKey(secret string) []byte {
+	return []byte("oidc-state:" + secret)
+}
+
+func randomString() (string, error) {
+	var buf = make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
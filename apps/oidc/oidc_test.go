@@ -0,0 +1,177 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"projectionist/config"
+)
+
+// stubProvider - local stand-in of OpenID Connect provider, token endpoint issues id token of claims
+type stubProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+	code   string
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error: %v", err)
+	}
+
+	var stub = &stubProvider{key: key, code: "code"}
+	var mux = http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 stub.server.URL,
+			"authorization_endpoint": stub.server.URL + "/authorize",
+			"token_endpoint":         stub.server.URL + "/token",
+			"jwks_uri":               stub.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != "projectionist" || secret != "secret" || r.PostFormValue("code") != stub.code {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		var token = jwt.NewWithClaims(jwt.SigningMethodRS256, stub.claims)
+		token.Header["kid"] = "key1"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "access", "id_token": idToken})
+	})
+	stub.server = httptest.NewServer(mux)
+
+	return stub
+}
+
+func TestProvider(t *testing.T) {
+	var stub = newStubProvider(t)
+	defer stub.server.Close()
+
+	var cfg = &config.OIDCCfg{
+		Issuer:        stub.server.URL,
+		ClientID:      "projectionist",
+		ClientSecret:  "secret",
+		RedirectURL:   "http://localhost/v1/api/oidc/callback",
+		Scopes:        []string{"openid", "profile"},
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+	}
+	var p = NewProvider(cfg, stub.server.Client())
+	var ctx = context.Background()
+	var now = time.Now()
+
+	authURL, err := p.AuthCodeURL(ctx, "state", "nonce")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error: %v", err)
+	}
+
+	parsed, _ := url.Parse(authURL)
+	if parsed.Path != "/authorize" || parsed.Query().Get("state") != "state" || parsed.Query().Get("nonce") != "nonce" {
+		t.Errorf("AuthCodeURL() = %s, want authorize endpoint with state and nonce", authURL)
+	}
+
+	var valid = jwt.MapClaims{
+		"iss":                stub.server.URL,
+		"aud":                []string{"projectionist", "other"},
+		"sub":                "subject1",
+		"exp":                now.Add(time.Minute).Unix(),
+		"nonce":              "nonce",
+		"preferred_username": "alice",
+		"groups":             []string{"ops", "dev"},
+	}
+
+	tests := []struct {
+		name    string
+		change  jwt.MapClaims
+		code    string
+		wantErr bool
+	}{
+		{name: "valid"},
+		{name: "wrong code", code: "other", wantErr: true},
+		{name: "other issuer", change: jwt.MapClaims{"iss": "http://evil"}, wantErr: true},
+		{name: "other audience", change: jwt.MapClaims{"aud": "other"}, wantErr: true},
+		{name: "expired", change: jwt.MapClaims{"exp": now.Add(-time.Hour).Unix()}, wantErr: true},
+		{name: "replayed nonce", change: jwt.MapClaims{"nonce": "old"}, wantErr: true},
+		{name: "no username", change: jwt.MapClaims{"preferred_username": ""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		stub.claims = jwt.MapClaims{}
+		for k, v := range valid {
+			stub.claims[k] = v
+		}
+		for k, v := range tt.change {
+			stub.claims[k] = v
+		}
+
+		var code = tt.code
+		if code == "" {
+			code = stub.code
+		}
+
+		identity, err := p.Exchange(ctx, code, "nonce", now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Exchange() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+
+		if err == nil && (identity.Subject != "subject1" || identity.Username != "alice" || len(identity.Groups) != 2) {
+			t.Errorf("%s: Exchange() identity = %+v", tt.name, identity)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	var now = time.Now()
+	cookie, state, nonce, err := NewState("secret", now)
+	if err != nil {
+		t.Fatalf("NewState() error: %v", err)
+	}
+
+	got, err := ParseState("secret", cookie, state, now)
+	if err != nil || got != nonce {
+		t.Errorf("ParseState() = %q, %v, want %q", got, err, nonce)
+	}
+
+	if _, err = ParseState("secret", cookie, "other", now); err != ErrState {
+		t.Errorf("ParseState() of other state error = %v, want %v", err, ErrState)
+	}
+
+	if _, err = ParseState("secret", cookie, state, now.Add(StateTTL+time.Second)); err != ErrState {
+		t.Errorf("ParseState() of expired state error = %v, want %v", err, ErrState)
+	}
+
+	if _, err = ParseState("other", cookie, state, now); err != ErrState {
+		t.Errorf("ParseState() with other secret error = %v, want %v", err, ErrState)
+	}
+}
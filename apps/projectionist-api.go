@@ -5,28 +5,42 @@ import (
 	"fmt"
 	"github.com/dgraph-io/badger/v2"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 
 	"google.golang.org/grpc/grpclog"
 
+	"projectionist/apps/oidc"
 	"projectionist/config"
 	"projectionist/consts"
 	"projectionist/controllers"
 	"projectionist/db"
 	"projectionist/middleware"
+	"projectionist/models"
 	"projectionist/provider"
+	"projectionist/validate"
 )
 
 type App struct {
 	syncChan    chan string
+	checker     controllers.Checker
 	cfg         *config.Config
 	dbProvider  provider.IDBProvider
 	cfgProvider provider.IDBProvider
+	setup       *validate.SetupToken // first SuperAdmin creation while database has no users
+	oidc        *oidc.Provider       // single sign-on provider, nil if it is not configured
+	keys        *validate.KeySet     // keys signing access tokens
 }
 
-func NewApp(cfg *config.Config, sqlDB *sql.DB, badgerDB *badger.DB, syncShan chan string) (*App, error) {
+func NewApp(
+	cfg *config.Config,
+	sqlDB *sql.DB,
+	badgerDB *badger.DB,
+	syncShan chan string,
+	checker controllers.Checker,
+) (*App, error) {
 	// initialization database tables
 	if err := db.InitTables(sqlDB); err != nil {
 		return nil, err
@@ -37,11 +51,38 @@ func NewApp(cfg *config.Config, sqlDB *sql.DB, badgerDB *badger.DB, syncShan cha
 		return nil, err
 	}
 
+	setup, err := validate.NewSetupToken(sqlDB)
+	if err != nil {
+		return nil, err
+	}
+
+	if token := setup.Token(); token != "" {
+		grpclog.Warningf(
+			"No users exist, create the first SuperAdmin by POST %s with setup token: %s",
+			consts.UrlApiSetupV1, token,
+		)
+	}
+
+	keys, err := validate.NewKeySet(sqlDB, cfg, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	validate.SetKeySet(keys)
+
+	var oidcProvider *oidc.Provider
+	if cfg.Auth.OIDC.Issuer != "" {
+		oidcProvider = oidc.NewProvider(&cfg.Auth.OIDC, nil)
+	}
+
 	return &App{
 		syncChan:    syncShan,
+		checker:     checker,
 		cfg:         cfg,
 		dbProvider:  provider.NewDBProvider(sqlDB),
 		cfgProvider: cfgProvider,
+		setup:       setup,
+		oidc:        oidcProvider,
+		keys:        keys,
 	}, nil
 }
 
@@ -50,6 +91,8 @@ func (a *App) Run() {
 
 	router := a.newRouter()
 
+	go a.rotateKeys()
+
 	grpclog.Infof("Start rest api service on: %v", address)
 	grpclog.Fatal(http.ListenAndServe(
 		address,
@@ -61,34 +104,118 @@ func (a *App) Run() {
 	)
 }
 
+// rotateKeys - replace token signing key when it becomes older than rotation interval or algorithm is changed,
+// rotation is checked as often as JWK set may be cached, so next key is published in time,
+// it runs without rotation interval too to promote published next key and remove expired keys
+func (a *App) rotateKeys() {
+	var ticker = time.NewTicker(validate.JWKSMaxAge)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := a.keys.RotateIfDue(now); err != nil {
+			grpclog.Errorf("Signing key rotation error: %v", err)
+		}
+	}
+}
+
 func (a *App) newRouter() *mux.Router {
+	sqlDB, _ := a.dbProvider.GetDB().(*sql.DB)
+
 	router := mux.NewRouter()
 	router.Use(
-		middleware.JwtAuthentication(a.cfg.TokenSecretKey),
+		middleware.JwtAuthentication(a.cfg.TokenSecretKey, sqlDB),
 	)
 
 	sh := http.StripPrefix("/swaggerui/", http.FileServer(http.Dir("./apps/swagger/")))
 	router.PathPrefix("/swaggerui/").Handler(sh)
 
-	router.HandleFunc(consts.UrlApiLoginV1, controllers.LoginApi(a.dbProvider, a.cfg.TokenSecretKey)).Methods(http.MethodPost)
-
-	router.HandleFunc(consts.UrlUserV1, controllers.NewUser(a.dbProvider)).Methods(http.MethodPost)
-	router.HandleFunc(consts.UrlUserV1, controllers.GetUserList(a.dbProvider)).Methods(http.MethodGet)
-	router.HandleFunc(consts.UrlUserV1+"/{id}", controllers.GetUser(a.dbProvider)).Methods(http.MethodGet)
-	router.HandleFunc(consts.UrlUserV1+"/{id}", controllers.UpdateUser(a.dbProvider)).Methods(http.MethodPut)
-	router.HandleFunc(consts.UrlUserV1+"/{id}", controllers.DeleteUser(a.dbProvider)).Methods(http.MethodGet)
-
-	router.HandleFunc(consts.UrlCfgV1, controllers.NewCfg(a.cfgProvider)).Methods(http.MethodPost)
-	router.HandleFunc(consts.UrlCfgV1, controllers.GetCfgList(a.cfgProvider)).Methods(http.MethodGet)
-	router.HandleFunc(consts.UrlCfgV1+"/{id}", controllers.GetCfg(a.cfgProvider)).Methods(http.MethodGet)
-	router.HandleFunc(consts.UrlCfgV1+"/{id}", controllers.UpdateCfg(a.cfgProvider)).Methods(http.MethodPut)
-	router.HandleFunc(consts.UrlCfgV1+"/{id}", controllers.DeleteCfg(a.cfgProvider)).Methods(http.MethodDelete)
-
-	router.HandleFunc(consts.UrlServiceV1, controllers.NewService(a.dbProvider, a.syncChan)).Methods(http.MethodPost)
-	router.HandleFunc(consts.UrlServiceV1, controllers.GetServiceList(a.dbProvider)).Methods(http.MethodGet)
-	router.HandleFunc(consts.UrlServiceV1+"/{id}", controllers.GetService(a.dbProvider)).Methods(http.MethodGet)
-	router.HandleFunc(consts.UrlServiceV1+"/{id}", controllers.UpdateService(a.dbProvider, a.syncChan)).Methods(http.MethodPut)
-	router.HandleFunc(consts.UrlServiceV1+"/{id}", controllers.DeleteService(a.dbProvider, a.syncChan)).Methods(http.MethodDelete)
+	statusSite := http.StripPrefix(consts.UrlStatusSite, http.FileServer(http.Dir("./apps/status/")))
+	router.PathPrefix(consts.UrlStatusSite).Handler(statusSite)
+	router.HandleFunc(consts.UrlStatusV1, controllers.GetStatusPage(a.dbProvider)).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlJWKS, controllers.GetJWKS(a.keys)).Methods(http.MethodGet)
+
+	router.HandleFunc(consts.UrlApiLoginV1, controllers.LoginApi(a.dbProvider, a.cfg)).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlApiLoginTwoFactorV1, controllers.LoginTwoFactorApi(a.dbProvider, a.cfg)).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlApiSetupV1, controllers.SetupApi(a.dbProvider, a.cfg, a.setup)).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlApiRefreshV1, controllers.RefreshApi(a.dbProvider, a.cfg)).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlApiLogoutV1, controllers.LogoutApi(a.dbProvider)).Methods(http.MethodPost)
+
+	if a.oidc != nil {
+		router.HandleFunc(consts.UrlApiOIDCLoginV1, controllers.OIDCLoginApi(a.oidc, a.cfg)).Methods(http.MethodGet)
+		router.HandleFunc(consts.UrlApiOIDCCallbackV1, controllers.OIDCCallbackApi(a.dbProvider, a.cfg, a.oidc)).Methods(http.MethodGet)
+	}
+
+	router.HandleFunc(consts.UrlUserV1, middleware.Permit(models.ResourceUser, models.ActionWrite, controllers.NewUser(a.dbProvider, a.cfg))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlUserV1, middleware.Permit(models.ResourceUser, models.ActionRead, controllers.GetUserList(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlUserV1+"/{id}", middleware.Permit(models.ResourceUser, models.ActionRead, controllers.GetUser(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlUserV1+"/{id}", middleware.Permit(models.ResourceUser, models.ActionWrite, controllers.UpdateUser(a.dbProvider))).Methods(http.MethodPut)
+	router.HandleFunc(consts.UrlUserV1+"/{id}", middleware.Permit(models.ResourceUser, models.ActionDelete, controllers.DeleteUser(a.dbProvider))).Methods(http.MethodDelete)
+	router.HandleFunc(consts.UrlUserTwoFAV1, middleware.Permit(models.ResourceUser, models.ActionWrite, controllers.ResetTwoFactor(a.dbProvider))).Methods(http.MethodDelete)
+	router.HandleFunc(consts.UrlUserPasswordV1, middleware.Permit(models.ResourceUser, models.ActionWrite, controllers.ResetPassword(a.dbProvider, a.cfg))).Methods(http.MethodPut)
+	router.HandleFunc(consts.UrlUserUnlockV1, middleware.Permit(models.ResourceUser, models.ActionWrite, controllers.UnlockUser(a.dbProvider))).Methods(http.MethodPost)
+
+	router.HandleFunc(consts.UrlAuditV1, middleware.Permit(models.ResourceAudit, models.ActionRead, controllers.GetAuditLog(a.dbProvider))).Methods(http.MethodGet)
+
+	// password of request user
+	router.HandleFunc(consts.UrlPasswordV1, controllers.ChangePassword(a.dbProvider, a.cfg)).Methods(http.MethodPut)
+
+	// two-factor authentication of request user
+	router.HandleFunc(consts.UrlTwoFactorEnrollV1, controllers.EnrollTwoFactor(a.dbProvider)).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlTwoFactorConfirmV1, controllers.ConfirmTwoFactor(a.dbProvider)).Methods(http.MethodPost)
+
+	// api keys of request user
+	router.HandleFunc(consts.UrlAPIKeyV1, controllers.NewAPIKey(a.dbProvider)).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlAPIKeyV1, controllers.GetAPIKeyList(a.dbProvider)).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlAPIKeyV1+"/{id}", controllers.RevokeAPIKey(a.dbProvider)).Methods(http.MethodDelete)
+
+	router.HandleFunc(consts.UrlCfgV1, middleware.Permit(models.ResourceConfig, models.ActionWrite, controllers.NewCfg(a.cfgProvider))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlCfgV1, middleware.Permit(models.ResourceConfig, models.ActionRead, controllers.GetCfgList(a.cfgProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlCfgV1+"/{id}", middleware.Permit(models.ResourceConfig, models.ActionRead, controllers.GetCfg(a.cfgProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlCfgV1+"/{id}", middleware.Permit(models.ResourceConfig, models.ActionWrite, controllers.UpdateCfg(a.cfgProvider))).Methods(http.MethodPut)
+	router.HandleFunc(consts.UrlCfgV1+"/{id}", middleware.Permit(models.ResourceConfig, models.ActionDelete, controllers.DeleteCfg(a.cfgProvider))).Methods(http.MethodDelete)
+
+	router.HandleFunc(consts.UrlServiceV1, middleware.Permit(models.ResourceService, models.ActionWrite, controllers.NewService(a.dbProvider, a.cfg, a.syncChan))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlServiceV1, middleware.Permit(models.ResourceService, models.ActionRead, controllers.GetServiceList(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlServiceGraphV1, middleware.Permit(models.ResourceService, models.ActionRead, controllers.GetServiceGraph(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlServiceV1+"/{id}", middleware.Permit(models.ResourceService, models.ActionRead, controllers.GetService(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlServiceV1+"/{id}", middleware.Permit(models.ResourceService, models.ActionWrite, controllers.UpdateService(a.dbProvider, a.cfg, a.syncChan))).Methods(http.MethodPut)
+	router.HandleFunc(consts.UrlServiceV1+"/{id}", middleware.Permit(models.ResourceService, models.ActionDelete, controllers.DeleteService(a.dbProvider, a.syncChan))).Methods(http.MethodDelete)
+
+	router.HandleFunc(consts.UrlServiceCheckV1, middleware.Permit(models.ResourceService, models.ActionWrite, controllers.CheckService(a.checker))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlServicePauseV1, middleware.Permit(models.ResourceService, models.ActionWrite, controllers.PauseService(a.dbProvider, a.syncChan))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlServiceResumeV1, middleware.Permit(models.ResourceService, models.ActionWrite, controllers.ResumeService(a.dbProvider, a.syncChan))).Methods(http.MethodPost)
+
+	router.HandleFunc(consts.UrlHealthCheckMetricsV1, middleware.Permit(models.ResourceHealthCheck, models.ActionRead, controllers.GetCheckerMetrics(a.checker))).Methods(http.MethodGet)
+
+	router.HandleFunc(consts.UrlIncidentV1, middleware.Permit(models.ResourceIncident, models.ActionRead, controllers.GetIncidentList(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlIncidentV1+"/{id}", middleware.Permit(models.ResourceIncident, models.ActionRead, controllers.GetIncident(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlIncidentAcknowledgeV1, middleware.Permit(models.ResourceIncident, models.ActionWrite, controllers.AcknowledgeIncident(a.dbProvider))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlIncidentNoteV1, middleware.Permit(models.ResourceIncident, models.ActionWrite, controllers.AddIncidentNote(a.dbProvider))).Methods(http.MethodPost)
+
+	router.HandleFunc(consts.UrlEscalationV1, middleware.Permit(models.ResourceEscalation, models.ActionWrite, controllers.NewEscalationPolicy(a.dbProvider))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlEscalationV1, middleware.Permit(models.ResourceEscalation, models.ActionRead, controllers.GetEscalationPolicyList(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlEscalationV1+"/{id}", middleware.Permit(models.ResourceEscalation, models.ActionRead, controllers.GetEscalationPolicy(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlEscalationV1+"/{id}", middleware.Permit(models.ResourceEscalation, models.ActionWrite, controllers.UpdateEscalationPolicy(a.dbProvider))).Methods(http.MethodPut)
+	router.HandleFunc(consts.UrlEscalationV1+"/{id}", middleware.Permit(models.ResourceEscalation, models.ActionDelete, controllers.DeleteEscalationPolicy(a.dbProvider))).Methods(http.MethodDelete)
+
+	router.HandleFunc(consts.UrlTemplatePreviewV1, middleware.Permit(models.ResourceTemplate, models.ActionRead, controllers.PreviewTemplate())).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlTemplateV1, middleware.Permit(models.ResourceTemplate, models.ActionWrite, controllers.NewTemplate(a.dbProvider))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlTemplateV1, middleware.Permit(models.ResourceTemplate, models.ActionRead, controllers.GetTemplateList(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlTemplateV1+"/{id}", middleware.Permit(models.ResourceTemplate, models.ActionRead, controllers.GetTemplate(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlTemplateV1+"/{id}", middleware.Permit(models.ResourceTemplate, models.ActionWrite, controllers.UpdateTemplate(a.dbProvider))).Methods(http.MethodPut)
+	router.HandleFunc(consts.UrlTemplateV1+"/{id}", middleware.Permit(models.ResourceTemplate, models.ActionDelete, controllers.DeleteTemplate(a.dbProvider))).Methods(http.MethodDelete)
+
+	router.HandleFunc(consts.UrlDeliveryV1, middleware.Permit(models.ResourceNotification, models.ActionRead, controllers.GetDeliveryList(a.dbProvider))).Methods(http.MethodGet)
+
+	router.HandleFunc(consts.UrlRecipientV1, middleware.Permit(models.ResourceNotification, models.ActionWrite, controllers.NewRecipientSettings(a.dbProvider))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlRecipientV1, middleware.Permit(models.ResourceNotification, models.ActionRead, controllers.GetRecipientSettingsList(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlRecipientV1+"/{id}", middleware.Permit(models.ResourceNotification, models.ActionRead, controllers.GetRecipientSettings(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlRecipientV1+"/{id}", middleware.Permit(models.ResourceNotification, models.ActionWrite, controllers.UpdateRecipientSettings(a.dbProvider))).Methods(http.MethodPut)
+	router.HandleFunc(consts.UrlRecipientV1+"/{id}", middleware.Permit(models.ResourceNotification, models.ActionDelete, controllers.DeleteRecipientSettings(a.dbProvider))).Methods(http.MethodDelete)
+
+	router.HandleFunc(consts.UrlServiceMaintenanceV1, middleware.Permit(models.ResourceMaintenance, models.ActionWrite, controllers.NewMaintenance(a.dbProvider))).Methods(http.MethodPost)
+	router.HandleFunc(consts.UrlServiceMaintenanceV1, middleware.Permit(models.ResourceMaintenance, models.ActionRead, controllers.GetMaintenanceList(a.dbProvider))).Methods(http.MethodGet)
+	router.HandleFunc(consts.UrlServiceMaintenanceV1+"/{window_id}", middleware.Permit(models.ResourceMaintenance, models.ActionDelete, controllers.DeleteMaintenance(a.dbProvider))).Methods(http.MethodDelete)
 
 	return router
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 )
@@ -17,11 +18,116 @@ type Config struct {
 	EmailPassword   string         `json:"email_password"`
 	HealthCheck     HealthCheckCfg `json:"health_check"`
 	NotifierConfig  NotifierConfig `json:"notifier"`
+	Agents          AgentsCfg      `json:"agents"`
+	Auth            AuthCfg        `json:"auth"`
+	// DevMode - allows insecure defaults like default token secret key, never enable it in production
+	DevMode bool `json:"dev_mode"`
+}
+
+// DefaultTokenSecretKey - token secret key of config without it, server does not start with it outside of dev mode
+const DefaultTokenSecretKey = "Secret"
+
+type AuthCfg struct {
+	AccessTokenTTL  int `json:"access_token_ttl"`  // seconds of access token validity
+	RefreshTokenTTL int `json:"refresh_token_ttl"` // seconds of session validity since login or last refresh
+	// LoginFailures - count of failed logins of username before lockout, every failure doubles delay
+	// before next allowed attempt starting from LoginBackoff seconds
+	LoginFailures   int         `json:"login_failures"`
+	IPLoginFailures int         `json:"ip_login_failures"` // count of failed logins from client address before lockout
+	LoginBackoff    int         `json:"login_backoff"`     // seconds
+	LockoutDuration int         `json:"lockout_duration"`  // seconds
+	OIDC            OIDCCfg     `json:"oidc"`
+	LDAP            LDAPCfg     `json:"ldap"`
+	Password        PasswordCfg `json:"password"`
+	Signing         SigningCfg  `json:"signing"`
+}
+
+// SigningCfg - signing of access tokens
+type SigningCfg struct {
+	// Algorithm - "HS256" signs by token secret key, "RS256" or "ES256" sign by generated keys
+	// published at /.well-known/jwks.json
+	Algorithm string `json:"algorithm"`
+	// RotationInterval - seconds between generations of new signing key, previous key verifies
+	// tokens until they expire, negative - no rotation
+	RotationInterval int `json:"rotation_interval"`
+}
+
+// PasswordCfg - policy of local user passwords, it is checked when password is set
+type PasswordCfg struct {
+	MinLength     int  `json:"min_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	// BreachedFile - local list of breached passwords, one per line as plain text or SHA-1 hex
+	// with optional ":count" suffix, passwords are not checked if empty
+	BreachedFile string `json:"breached_file"`
+	BcryptCost   int    `json:"bcrypt_cost"` // cost of password hashes, previous hashes keep their cost
+}
+
+// LDAPCfg - password check of directory users by LDAP bind, disabled if url is empty,
+// local users not linked to directory are checked by local password, e.g. break-glass admins
+type LDAPCfg struct {
+	URL           string `json:"url"` // ldap://host:389 or ldaps://host:636
+	StartTLS      bool   `json:"start_tls"`
+	TLSSkipVerify bool   `json:"tls_skip_verify"`
+	Timeout       int    `json:"timeout"` // connection and request timeout in seconds
+	// BindDN - service account searching user entries, anonymous search if empty
+	BindDN       string `json:"bind_dn"`
+	BindPassword string `json:"bind_password"`
+	BaseDN       string `json:"base_dn"`
+	// UserFilter - search filter of user entry, %s is replaced by escaped username
+	UserFilter     string `json:"user_filter"`
+	GroupAttribute string `json:"group_attribute"` // user entry attribute of group DNs
+	// RoleGroups - role name ("superadmin", "admin" or "viewer") by group DN, the highest role of user groups is used
+	RoleGroups map[string]string `json:"role_groups"`
+	// DefaultRole - role of user without mapped groups, login of such user is denied if empty
+	DefaultRole string `json:"default_role"`
+}
+
+// OIDCCfg - single sign-on by OpenID Connect provider, disabled if issuer is empty
+type OIDCCfg struct {
+	Issuer       string `json:"issuer"` // provider url, its discovery document is <issuer>/.well-known/openid-configuration
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	// RedirectURL - callback url registered in provider, e.g. https://projectionist.example.com/v1/api/oidc/callback
+	RedirectURL   string   `json:"redirect_url"`
+	Scopes        []string `json:"scopes"`
+	UsernameClaim string   `json:"username_claim"` // id token claim of username
+	GroupsClaim   string   `json:"groups_claim"`   // id token claim of groups list
+	// RoleGroups - role name ("superadmin", "admin" or "viewer") by provider group, the highest role of user groups is used
+	RoleGroups map[string]string `json:"role_groups"`
+	// DefaultRole - role of user without mapped groups, login of such user is denied if empty
+	DefaultRole string `json:"default_role"`
 }
 
 type HealthCheckCfg struct {
 	ConnCount   int `json:"conn_count"`
 	ConnTimeout int `json:"conn_timeout"`
+	Timeout     int `json:"timeout"` // default probe request timeout in seconds
+	// CertWarnDays - default count of days before link certificate expiry when service status is warning
+	CertWarnDays int `json:"cert_warn_days"`
+	Workers      int `json:"workers"`    // count of concurrent probe workers
+	QueueSize    int `json:"queue_size"` // max count of planned checks waiting for worker
+	// Jitter - max shift of service checks start in seconds, spreads services with same frequency
+	Jitter int `json:"jitter"`
+	// EscalationInterval - seconds between evaluations of escalation policies of open incidents
+	EscalationInterval int `json:"escalation_interval"`
+}
+
+type AgentsCfg struct {
+	Token  string `json:"token"`  // shared secret of remote health check agents, agents are disabled if empty
+	Server string `json:"server"` // grpc address of main server, used in agent mode
+	// Quorum - count of agents reported failure to mark service dead, majority of active agents if 0
+	Quorum       int `json:"quorum"`
+	TTL          int `json:"ttl"`           // seconds after last request when agent is considered inactive
+	SyncInterval int `json:"sync_interval"` // seconds between agent requests of assigned services
+	// CertFile and KeyFile - TLS certificate of grpc server, agents verify it by CAFile or by system roots if it is empty
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	CAFile   string `json:"ca_file"`
+	// Insecure - allow plaintext agent connections, tokens of agents and services are sent unencrypted
+	Insecure bool `json:"insecure"`
 }
 
 type NotifierConfig struct {
@@ -30,25 +136,36 @@ type NotifierConfig struct {
 	Port     int    `json:"port"`
 	From     string `json:"from"`
 	Password string `json:"password"`
+	// ChannelTimeout - timeout in seconds of webhook, Slack and Telegram notification requests
+	ChannelTimeout int `json:"channel_timeout"`
+	// TLS - smtp connection security: "none", "starttls" or "tls" for implicit tls
+	TLS           string `json:"tls"`
+	TLSSkipVerify bool   `json:"tls_skip_verify"`
+	Timeout       int    `json:"timeout"` // smtp dial and session timeout in seconds
+	// Attempts - max count of delivery attempts of queued email
+	Attempts      int `json:"attempts"`
+	RetryBackoff  int `json:"retry_backoff"`  // delay in seconds before second attempt, doubled on each next attempt
+	QueueInterval int `json:"queue_interval"` // seconds between checks of outbound queue
+	// DigestWindow - seconds during which emails to the same recipient are batched to one digest, 0 - disabled
+	DigestWindow int `json:"digest_window"`
+	// RateLimit - max count of emails to the same recipient per rate window, 0 - unlimited,
+	// emails over limit are postponed and sent as digest
+	RateLimit  int `json:"rate_limit"`
+	RateWindow int `json:"rate_window"` // seconds
 }
 
 func NewConfig() (*Config, error) {
+	var cfg = &Config{}
+
 	buff, err := ioutil.ReadFile("config.json")
 	if err != nil {
-		if os.ErrNotExist == err {
-			return &Config{
-				Host:            "127.0.0.1",
-				Port:            8080,
-				GrpcPort:        8081,
-				GrpcApiPort:     8082,
-				TokenSecretKey:  "Secret",
-				AccessAddresses: []string{"*"},
-			}, nil
+		if os.IsNotExist(err) {
+			cfg.checkDefault()
+			return cfg, nil
 		}
 		return nil, err
 	}
 
-	var cfg = &Config{}
 	err = json.Unmarshal(buff, cfg)
 	if err != nil {
 		return nil, err
@@ -59,9 +176,72 @@ func NewConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// redacted - replacement of secret in logs, empty secret is shown as empty
+func redacted(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return "[redacted]"
+}
+
+// String - config with redacted secrets, nested configs redact their secrets too
+func (cfg Config) String() string {
+	type plain Config
+	cfg.TokenSecretKey = redacted(cfg.TokenSecretKey)
+	cfg.EmailPassword = redacted(cfg.EmailPassword)
+	return fmt.Sprintf("%+v", plain(cfg))
+}
+
+// String - notifier config with redacted smtp password
+func (cfg NotifierConfig) String() string {
+	type plain NotifierConfig
+	cfg.Password = redacted(cfg.Password)
+	return fmt.Sprintf("%+v", plain(cfg))
+}
+
+// String - agents config with redacted shared token
+func (cfg AgentsCfg) String() string {
+	type plain AgentsCfg
+	cfg.Token = redacted(cfg.Token)
+	return fmt.Sprintf("%+v", plain(cfg))
+}
+
+// String - OIDC config with redacted client secret
+func (cfg OIDCCfg) String() string {
+	type plain OIDCCfg
+	cfg.ClientSecret = redacted(cfg.ClientSecret)
+	return fmt.Sprintf("%+v", plain(cfg))
+}
+
+// String - LDAP config with redacted bind password
+func (cfg LDAPCfg) String() string {
+	type plain LDAPCfg
+	cfg.BindPassword = redacted(cfg.BindPassword)
+	return fmt.Sprintf("%+v", plain(cfg))
+}
+
+// CheckSecurity - error if agents connect in plaintext without explicit opt-in
+// or if config has insecure defaults and dev mode is disabled
+func (cfg *Config) CheckSecurity() error {
+	if cfg.Agents.Token != "" && cfg.Agents.CertFile == "" && !cfg.Agents.Insecure {
+		return fmt.Errorf("agents need grpc tls, set agents cert_file and key_file or enable agents insecure")
+	}
+
+	if cfg.DevMode {
+		return nil
+	}
+
+	if cfg.TokenSecretKey == DefaultTokenSecretKey {
+		return fmt.Errorf("token_secret_key is the default secret, set own secret or enable dev_mode")
+	}
+
+	return nil
+}
+
 func (cfg *Config) checkDefault() {
 	if cfg.TokenSecretKey == "" {
-		cfg.TokenSecretKey = "Secret"
+		cfg.TokenSecretKey = DefaultTokenSecretKey
 	}
 
 	if cfg.Host == "" {
@@ -91,4 +271,132 @@ func (cfg *Config) checkDefault() {
 	if cfg.HealthCheck.ConnTimeout == 0 {
 		cfg.HealthCheck.ConnTimeout = 30
 	}
+
+	if cfg.HealthCheck.Timeout == 0 {
+		cfg.HealthCheck.Timeout = 10
+	}
+
+	if cfg.HealthCheck.CertWarnDays == 0 {
+		cfg.HealthCheck.CertWarnDays = 14
+	}
+
+	if cfg.HealthCheck.Workers == 0 {
+		cfg.HealthCheck.Workers = 10
+	}
+
+	if cfg.HealthCheck.QueueSize == 0 {
+		cfg.HealthCheck.QueueSize = 1000
+	}
+
+	if cfg.HealthCheck.Jitter == 0 {
+		cfg.HealthCheck.Jitter = 30
+	}
+
+	if cfg.NotifierConfig.ChannelTimeout == 0 {
+		cfg.NotifierConfig.ChannelTimeout = 10
+	}
+
+	if cfg.NotifierConfig.TLS == "" {
+		cfg.NotifierConfig.TLS = "none"
+	}
+
+	if cfg.NotifierConfig.Timeout == 0 {
+		cfg.NotifierConfig.Timeout = 30
+	}
+
+	if cfg.NotifierConfig.Attempts == 0 {
+		cfg.NotifierConfig.Attempts = 5
+	}
+
+	if cfg.NotifierConfig.RetryBackoff == 0 {
+		cfg.NotifierConfig.RetryBackoff = 30
+	}
+
+	if cfg.NotifierConfig.QueueInterval == 0 {
+		cfg.NotifierConfig.QueueInterval = 10
+	}
+
+	if cfg.NotifierConfig.RateWindow == 0 {
+		cfg.NotifierConfig.RateWindow = 3600
+	}
+
+	if cfg.HealthCheck.EscalationInterval == 0 {
+		cfg.HealthCheck.EscalationInterval = 30
+	}
+
+	if cfg.Auth.AccessTokenTTL == 0 {
+		cfg.Auth.AccessTokenTTL = 900
+	}
+
+	if cfg.Auth.RefreshTokenTTL == 0 {
+		cfg.Auth.RefreshTokenTTL = 30 * 24 * 3600
+	}
+
+	if cfg.Auth.LoginFailures == 0 {
+		cfg.Auth.LoginFailures = 5
+	}
+
+	if cfg.Auth.IPLoginFailures == 0 {
+		cfg.Auth.IPLoginFailures = 20
+	}
+
+	if cfg.Auth.LoginBackoff == 0 {
+		cfg.Auth.LoginBackoff = 1
+	}
+
+	if cfg.Auth.LockoutDuration == 0 {
+		cfg.Auth.LockoutDuration = 900
+	}
+
+	if len(cfg.Auth.OIDC.Scopes) == 0 {
+		cfg.Auth.OIDC.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	if cfg.Auth.OIDC.UsernameClaim == "" {
+		cfg.Auth.OIDC.UsernameClaim = "preferred_username"
+	}
+
+	if cfg.Auth.OIDC.GroupsClaim == "" {
+		cfg.Auth.OIDC.GroupsClaim = "groups"
+	}
+
+	if cfg.Auth.Signing.Algorithm == "" {
+		cfg.Auth.Signing.Algorithm = "HS256"
+	}
+
+	if cfg.Auth.Signing.RotationInterval == 0 {
+		cfg.Auth.Signing.RotationInterval = 30 * 24 * 3600
+	}
+
+	if cfg.Auth.Password.MinLength <= 0 {
+		cfg.Auth.Password.MinLength = 8
+	}
+
+	if cfg.Auth.Password.BcryptCost == 0 {
+		cfg.Auth.Password.BcryptCost = 10
+	}
+
+	if cfg.Auth.LDAP.Timeout <= 0 {
+		cfg.Auth.LDAP.Timeout = 10
+	}
+
+	if cfg.Auth.LDAP.UserFilter == "" {
+		cfg.Auth.LDAP.UserFilter = "(uid=%s)"
+	}
+
+	if cfg.Auth.LDAP.GroupAttribute == "" {
+		cfg.Auth.LDAP.GroupAttribute = "memberOf"
+	}
+
+	if cfg.Agents.Server == "" {
+		cfg.Agents.Server = fmt.Sprintf("%s:%d", cfg.Host, cfg.GrpcPort)
+	}
+
+	if cfg.Agents.TTL == 0 {
+		cfg.Agents.TTL = 90
+	}
+
+	if cfg.Agents.SyncInterval == 0 {
+		cfg.Agents.SyncInterval = 30
+	}
 }
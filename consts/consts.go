@@ -4,9 +4,18 @@ const (
 	PAGE_PARAM  = "page"
 	COUNT_PARAM = "count"
 
-	KEY_USERS    = "users"
-	KEY_CONFIGS  = "configs"
-	KEY_SERVICES = "services"
+	KEY_USERS       = "users"
+	KEY_CONFIGS     = "configs"
+	KEY_SERVICES    = "services"
+	KEY_MAINTENANCE = "maintenance"
+	KEY_INCIDENTS   = "incidents"
+	KEY_ESCALATION  = "escalation"
+	KEY_TEMPLATES   = "templates"
+	KEY_DELIVERIES  = "deliveries"
+	KEY_RECIPIENTS  = "recipients"
+	KEY_API_KEY     = "api_key"
+	KEY_API_KEYS    = "api_keys"
+	KEY_AUDIT       = "audit"
 
 	JsonOriginalType = "application/json+original"
 )
@@ -5,21 +5,38 @@ import (
 )
 
 const (
-	SmtWhenWrongResp         = "Something when wrong"
-	NameIsEmptyResp          = "Name is empty"
-	BadInputDataResp         = "Bad input data"
-	IdIsEmptyResp            = "Id is empty"
-	IdIsNotNumberResp        = "Id is not number"
-	InputDataInvalidResp     = "Invalid input data"
-	NotSavedResp             = "Save error"
-	NotExistResp             = "Not exist"
-	PageAndCountRequiredResp = "Page and count required"
-	PageMustNumberResp       = "Page must be a number"
-	CountMustNumberResp      = "Count must be a number"
-	NotUpdatedResp           = "Not updated"
-	NotDeletedResp           = "Not deleted"
+	SmtWhenWrongResp           = "Something when wrong"
+	NameIsEmptyResp            = "Name is empty"
+	BadInputDataResp           = "Bad input data"
+	IdIsEmptyResp              = "Id is empty"
+	IdIsNotNumberResp          = "Id is not number"
+	InputDataInvalidResp       = "Invalid input data"
+	NotSavedResp               = "Save error"
+	NotExistResp               = "Not exist"
+	PageAndCountRequiredResp   = "Page and count required"
+	PageMustNumberResp         = "Page must be a number"
+	CountMustNumberResp        = "Count must be a number"
+	NotUpdatedResp             = "Not updated"
+	NotDeletedResp             = "Not deleted"
+	UnknownAgentResp           = "Unknown agent"
+	AlreadyAcknowledgedResp    = "Already acknowledged"
+	TemplateRenderResp         = "Template render error"
+	PermissionDeniedResp       = "Permission denied"
+	SessionInvalidResp         = "Session is revoked or expired"
+	SetupDoneResp              = "Setup is already done"
+	APIKeyInvalidResp          = "API key is revoked or expired"
+	LoginThrottledResp         = "Too many failed logins, try again later"
+	TwoFactorCodeResp          = "Invalid two-factor code"
+	TwoFactorEnabledResp       = "Two-factor authentication is already enabled"
+	SSOFailedResp              = "Single sign-on failed"
+	SSONoRoleResp              = "Single sign-on user has no role"
+	SSOUsernameTakenResp       = "Username is taken by other user"
+	PasswordChangeRequiredResp = "Password change required"
+	PasswordExternalResp       = "Password is managed by identity provider"
+	PasswordPolicyResp         = "Password does not match password policy"
 )
 
 var (
-	ErrNotFound = errors.New("not exist")
+	ErrNotFound     = errors.New("not exist")
+	ErrUnknownAgent = errors.New("unknown agent")
 )
@@ -6,16 +6,88 @@ const (
 	urlPrefixService  = "/service"
 	urlPrefixUser     = "/user"
 	urlPrefixCfg      = "/cfg"
+	urlGraph          = "/graph"
+	urlMaintenance    = "/maintenance"
+	urlCheck          = "/check"
+	urlPause          = "/pause"
+	urlResume         = "/resume"
+	urlHealthCheck    = "/health-check"
+	urlMetrics        = "/metrics"
+	urlStatus         = "/status"
+	urlPrefixIncident = "/incident"
+	urlAcknowledge    = "/acknowledge"
+	urlNote           = "/note"
+	urlEscalation     = "/escalation"
+	urlTemplate       = "/template"
+	urlPreview        = "/preview"
+	urlDelivery       = "/delivery"
+	urlRecipient      = "/recipient"
+	urlAPIKey         = "/apikey"
+	urlUnlock         = "/unlock"
+	urlAudit          = "/audit"
 
-	urlLogin  = "/login"
-	urlLogout = "/logout"
+	urlLogin    = "/login"
+	urlLogout   = "/logout"
+	urlRefresh  = "/refresh"
+	urlSetup    = "/setup"
+	urlTwoFA    = "/2fa"
+	urlEnroll   = "/enroll"
+	urlConfirm  = "/confirm"
+	urlOIDC     = "/oidc"
+	urlCallback = "/callback"
+	urlPassword = "/password"
 
-	UrlApiLoginV1 = urlPrefixVersion1 + urlApiPrefix + urlLogin
-	UrlLogin      = urlLogin
+	UrlApiLoginV1   = urlPrefixVersion1 + urlApiPrefix + urlLogin
+	UrlApiLogoutV1  = urlPrefixVersion1 + urlApiPrefix + urlLogout
+	UrlApiRefreshV1 = urlPrefixVersion1 + urlApiPrefix + urlRefresh
+	UrlApiSetupV1   = urlPrefixVersion1 + urlApiPrefix + urlSetup
 
-	UrlServiceV1 = urlPrefixVersion1 + urlApiPrefix + urlPrefixService
+	UrlApiLoginTwoFactorV1 = UrlApiLoginV1 + urlTwoFA
+	UrlTwoFactorV1         = urlPrefixVersion1 + urlApiPrefix + urlTwoFA
+	UrlTwoFactorEnrollV1   = UrlTwoFactorV1 + urlEnroll
+	UrlTwoFactorConfirmV1  = UrlTwoFactorV1 + urlConfirm
+	UrlLogin               = urlLogin
 
-	UrlUserV1 = urlPrefixVersion1 + urlApiPrefix + urlPrefixUser
+	UrlApiOIDCLoginV1    = urlPrefixVersion1 + urlApiPrefix + urlOIDC + urlLogin
+	UrlApiOIDCCallbackV1 = urlPrefixVersion1 + urlApiPrefix + urlOIDC + urlCallback
+
+	UrlPasswordV1 = urlPrefixVersion1 + urlApiPrefix + urlPassword
+
+	UrlServiceV1      = urlPrefixVersion1 + urlApiPrefix + urlPrefixService
+	UrlServiceGraphV1 = UrlServiceV1 + urlGraph
+
+	UrlServiceMaintenanceV1 = UrlServiceV1 + "/{id}" + urlMaintenance
+	UrlServiceCheckV1       = UrlServiceV1 + "/{id}" + urlCheck
+	UrlServicePauseV1       = UrlServiceV1 + "/{id}" + urlPause
+	UrlServiceResumeV1      = UrlServiceV1 + "/{id}" + urlResume
+
+	UrlHealthCheckMetricsV1 = urlPrefixVersion1 + urlApiPrefix + urlHealthCheck + urlMetrics
+
+	UrlStatusV1   = urlPrefixVersion1 + urlApiPrefix + urlStatus
+	UrlStatusSite = urlStatus + "/"
+
+	UrlIncidentV1            = urlPrefixVersion1 + urlApiPrefix + urlPrefixIncident
+	UrlIncidentAcknowledgeV1 = UrlIncidentV1 + "/{id}" + urlAcknowledge
+	UrlIncidentNoteV1        = UrlIncidentV1 + "/{id}" + urlNote
+
+	UrlEscalationV1 = urlPrefixVersion1 + urlApiPrefix + urlEscalation
+
+	UrlTemplateV1        = urlPrefixVersion1 + urlApiPrefix + urlTemplate
+	UrlTemplatePreviewV1 = UrlTemplateV1 + urlPreview
+
+	UrlDeliveryV1  = urlPrefixVersion1 + urlApiPrefix + urlDelivery
+	UrlRecipientV1 = urlPrefixVersion1 + urlApiPrefix + urlRecipient
+
+	UrlUserV1         = urlPrefixVersion1 + urlApiPrefix + urlPrefixUser
+	UrlUserUnlockV1   = UrlUserV1 + "/{id}" + urlUnlock
+	UrlUserTwoFAV1    = UrlUserV1 + "/{id}" + urlTwoFA
+	UrlUserPasswordV1 = UrlUserV1 + "/{id}" + urlPassword
+
+	UrlAuditV1 = urlPrefixVersion1 + urlApiPrefix + urlAudit
+
+	UrlAPIKeyV1 = urlPrefixVersion1 + urlApiPrefix + urlAPIKey
 
 	UrlCfgV1 = urlPrefixVersion1 + urlApiPrefix + urlPrefixCfg
+
+	UrlJWKS = "/.well-known/jwks.json"
 )
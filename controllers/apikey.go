@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+// NewAPIKey - create api key of request user, key is returned only in this response
+func NewAPIKey(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := sessionToken(w, r)
+		if !ok {
+			return
+		}
+
+		var apiKey = models.APIKey{}
+		err := json.NewDecoder(r.Body).Decode(&apiKey)
+		if err != nil {
+			log.Printf("new api key decode request body error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
+		var now = time.Now()
+		err = apiKey.Validate()
+		if err != nil || (apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now)) {
+			log.Printf("new api key validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		// key can not grant more than role of its owner
+		for _, scope := range apiKey.Scopes {
+			if !token.Role.Can(scope.Resource, scope.Action) {
+				w.WriteHeader(http.StatusForbidden)
+				utils.JsonRespond(w, utils.Message(false, consts.PermissionDeniedResp))
+				return
+			}
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		key, err := models.NewAPIKeyValue()
+		if err != nil {
+			log.Printf("models.NewAPIKeyValue() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		apiKey.ID = 0
+		apiKey.UserID = int(token.UserId)
+		apiKey.CreatedAt = now
+		apiKey.LastUsedAt = nil
+		apiKey.RevokedAt = nil
+		apiKey.SetKey(key)
+
+		err = apiKey.Save(iDB)
+		if err != nil {
+			log.Printf("new api key save error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		respond := utils.Message(true, "New api key created")
+		respond[consts.KEY_API_KEY] = apiKey
+		respond["key"] = key
+
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// GetAPIKeyList - api keys of request user
+func GetAPIKeyList(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := sessionToken(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		keys, err := models.GetUserAPIKeys(iDB, int(token.UserId))
+		if err != nil {
+			log.Printf("models.GetUserAPIKeys() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		respond := utils.Message(true, "")
+		respond[consts.KEY_API_KEYS] = keys
+		respond["total"] = len(keys)
+
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// RevokeAPIKey - revoke api key of request user, SuperAdmin can revoke key of any user
+func RevokeAPIKey(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := sessionToken(w, r)
+		if !ok {
+			return
+		}
+
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		apiKey, err := models.GetAPIKey(iDB, id)
+		if err == nil && apiKey.UserID != int(token.UserId) && token.Role != models.SuperAdmin {
+			err = sql.ErrNoRows
+		}
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("models.GetAPIKey() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = apiKey.Revoke(iDB, time.Now())
+		if err != nil {
+			log.Printf("api key %d Revoke() error: %v", apiKey.ID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "Api key revoked"))
+	})
+}
+
+// sessionToken - claims of user logged in by password, api keys are not allowed to manage api keys
+func sessionToken(w http.ResponseWriter, r *http.Request) (*models.Token, bool) {
+	token := models.TokenFromContext(r.Context())
+	if token == nil || token.APIKeyID != 0 {
+		w.WriteHeader(http.StatusForbidden)
+		utils.JsonRespond(w, utils.Message(false, consts.PermissionDeniedResp))
+		return nil, false
+	}
+
+	return token, true
+}
+
+func requestDB(w http.ResponseWriter, dbProvider provider.IDBProvider) (*sql.DB, bool) {
+	iDB, ok := dbProvider.GetDB().(*sql.DB)
+	if !ok || iDB == nil {
+		log.Printf("database empty in db provider")
+		w.WriteHeader(http.StatusInternalServerError)
+		utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+		return nil, false
+	}
+
+	return iDB, true
+}
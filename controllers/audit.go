@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+// GetAuditLog - page of audit log, newest first
+func GetAuditLog(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, count, ok := getPageAndCount(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		total, err := models.CountAuditEntries(iDB)
+		if err != nil {
+			log.Printf("models.CountAuditEntries() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var entries = []*models.AuditEntry{}
+		if page > 0 {
+			start, _ := utils.Pagination(page, count)
+			entries, err = models.GetAuditEntries(iDB, start, count)
+			if err != nil {
+				log.Printf("models.GetAuditEntries() error: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+				return
+			}
+		}
+
+		var respond = utils.Message(true, "")
+		respond[consts.KEY_AUDIT] = entries
+		respond["total"] = total
+		utils.JsonRespond(w, respond)
+	})
+}
@@ -3,20 +3,24 @@ package controllers
 import (
 	"database/sql"
 	"encoding/json"
-	"github.com/dgrijalva/jwt-go"
-	"golang.org/x/crypto/bcrypt"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"projectionist/config"
 	"projectionist/consts"
 	"projectionist/forms"
 	"projectionist/models"
 	"projectionist/provider"
 	"projectionist/utils"
+	"projectionist/validate"
+	"strconv"
+	"time"
 )
 
 func LoginApi(
 	dbProvider provider.IDBProvider,
-	tokenSecretKey string,
+	cfg *config.Config,
 ) http.HandlerFunc {
 	return http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
 		var respond = make(map[string]interface{})
@@ -37,48 +41,52 @@ func LoginApi(
 			return
 		}
 
-		var iUser models.Model
-		var user = &models.User{}
-
-		iUser, err = dbProvider.GetByName(user, form.Username)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				respond = utils.Message(false, consts.NotExistResp)
-				resp.WriteHeader(http.StatusNotFound)
-				utils.JsonRespond(resp, respond)
-				return
-			}
-
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
 			respond = utils.Message(false, consts.SmtWhenWrongResp)
-			log.Printf("LoginApi() error: %v", err)
+			log.Printf("LoginApi() error: database empty in db provider")
 			resp.WriteHeader(http.StatusInternalServerError)
 			utils.JsonRespond(resp, respond)
 			return
 		}
 
-		user, ok := iUser.(*models.User)
-		if !ok {
+		user, err := validate.Login(iDB, cfg, form.Username, form.Password, clientIP(r), time.Now())
+		if err != nil {
+			log.Printf("LoginApi() login of %s error: %v", form.Username, err)
+			respondLoginError(resp, err)
+			return
+		}
+
+		enabled, err := validate.TwoFactorEnabled(iDB, user.ID)
+		if err != nil {
 			respond = utils.Message(false, consts.SmtWhenWrongResp)
-			log.Printf("LoginApi() error: iUser is not User model")
+			log.Printf("LoginApi() validate.TwoFactorEnabled() error: %v", err)
 			resp.WriteHeader(http.StatusInternalServerError)
 			utils.JsonRespond(resp, respond)
 			return
 		}
 
-		if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(form.Password)); err != nil {
-			respond = utils.Message(false, "Not authorized")
-			log.Printf("LoginApi() bcrypt.CompareHashAndPassword() error: %v", err)
-			resp.WriteHeader(http.StatusUnauthorized)
+		if enabled {
+			twoFactorToken, err := validate.NewTwoFactorToken(cfg, user, time.Now())
+			if err != nil {
+				respond = utils.Message(false, "Authorization failed")
+				log.Printf("LoginApi() validate.NewTwoFactorToken() error: %v", err)
+				resp.WriteHeader(http.StatusInternalServerError)
+				utils.JsonRespond(resp, respond)
+				return
+			}
+
+			respond = utils.Message(true, "Two-factor code required")
+			respond["two_factor_required"] = true
+			respond["two_factor_token"] = twoFactorToken
 			utils.JsonRespond(resp, respond)
 			return
 		}
 
-		var tokenM = &models.Token{UserId: uint64(user.ID)}
-		var token = jwt.NewWithClaims(jwt.GetSigningMethod(jwt.SigningMethodHS256.Name), tokenM)
-		tokenStr, err := token.SignedString([]byte(tokenSecretKey))
+		tokens, err := validate.NewSession(iDB, cfg, user, time.Now())
 		if err != nil {
 			respond = utils.Message(false, "Authorization failed")
-			log.Printf("LoginApi() token.SignedString() error: %v", err)
+			log.Printf("LoginApi() validate.NewSession() error: %v", err)
 			resp.WriteHeader(http.StatusInternalServerError)
 			utils.JsonRespond(resp, respond)
 			return
@@ -86,11 +94,234 @@ func LoginApi(
 
 		log.Printf("Login successful for %v", user.Username)
 
-		respond = utils.Message(true, "Login successful")
-		user.Password = ""
-		user.Token = tokenStr
-		respond["user"] = user
+		respondTokens(resp, utils.Message(true, "Login successful"), user, tokens)
+	})
+}
+
+// RefreshApi - new access token and refresh token by refresh token of active session
+func RefreshApi(
+	dbProvider provider.IDBProvider,
+	cfg *config.Config,
+) http.HandlerFunc {
+	return http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		var form = forms.RefreshForm{}
+		var err = json.NewDecoder(r.Body).Decode(&form)
+		if err == nil {
+			err = form.Validate()
+		}
+		if err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(resp, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("RefreshApi() error: database empty in db provider")
+			resp.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(resp, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		tokens, user, err := validate.RefreshSession(iDB, cfg, form.RefreshToken, time.Now())
+		if err != nil {
+			if err == validate.ErrSessionInvalid {
+				resp.WriteHeader(http.StatusUnauthorized)
+				utils.JsonRespond(resp, utils.Message(false, consts.SessionInvalidResp))
+				return
+			}
+
+			log.Printf("RefreshApi() validate.RefreshSession() error: %v", err)
+			resp.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(resp, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		respondTokens(resp, utils.Message(true, "Token refreshed"), user, tokens)
+	})
+}
+
+// LogoutApi - revoke session of request access token with its refresh token
+func LogoutApi(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		token := models.TokenFromContext(r.Context())
+		if token == nil {
+			resp.WriteHeader(http.StatusUnauthorized)
+			utils.JsonRespond(resp, utils.Message(false, "Not authorized"))
+			return
+		}
+
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("LogoutApi() error: database empty in db provider")
+			resp.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(resp, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err := validate.Logout(iDB, token, time.Now())
+		if err != nil {
+			if err == validate.ErrSessionInvalid {
+				resp.WriteHeader(http.StatusUnauthorized)
+				utils.JsonRespond(resp, utils.Message(false, consts.SessionInvalidResp))
+				return
+			}
+
+			log.Printf("LogoutApi() validate.Logout() error: %v", err)
+			resp.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(resp, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		utils.JsonRespond(resp, utils.Message(true, "Logout successful"))
+	})
+}
+
+func respondTokens(resp http.ResponseWriter, respond map[string]interface{}, user *models.User, tokens *validate.Tokens) {
+	user.Password = ""
+	user.Token = tokens.AccessToken
+	respond["user"] = user
+	respond["refresh_token"] = tokens.RefreshToken
+	respond["expires_at"] = tokens.ExpiresAt
+	utils.JsonRespond(resp, respond)
+}
+
+// SetupApi - create the first SuperAdmin of fresh database by one-time setup token printed at start
+func SetupApi(dbProvider provider.IDBProvider, cfg *config.Config, setup *validate.SetupToken) http.HandlerFunc {
+	return http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		var form = forms.SetupForm{}
+		var err = json.NewDecoder(r.Body).Decode(&form)
+		if err == nil {
+			err = form.Validate()
+		}
+		if err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(resp, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		var user = &models.User{Username: form.Username, Password: form.Password, Role: models.SuperAdmin}
+		err = user.Validate()
+		if err != nil {
+			log.Printf("SetupApi() user validate error: %v", err)
+			resp.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(resp, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		if !checkPasswordPolicy(resp, cfg, user.Username, user.Password) {
+			return
+		}
+
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("SetupApi() error: database empty in db provider")
+			resp.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(resp, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = setup.CreateSuperAdmin(iDB, form.Token, user)
+		switch err {
+		case nil:
+		case validate.ErrSetupDone:
+			resp.WriteHeader(http.StatusForbidden)
+			utils.JsonRespond(resp, utils.Message(false, consts.SetupDoneResp))
+			return
+		case validate.ErrSetupToken:
+			resp.WriteHeader(http.StatusUnauthorized)
+			utils.JsonRespond(resp, utils.Message(false, "Not authorized"))
+			return
+		default:
+			log.Printf("SetupApi() CreateSuperAdmin() error: %v", err)
+			resp.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(resp, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		log.Printf("First SuperAdmin %s is created by setup token", user.Username)
+
+		respond := utils.Message(true, "New user created")
+		respond["userID"] = user.ID
 		utils.JsonRespond(resp, respond)
+	})
+}
+
+// clientIP - address of client without port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// LoginTwoFactorApi - second login step of user with two-factor authentication,
+// two-factor token of login response is exchanged for session by authenticator or recovery code
+func LoginTwoFactorApi(
+	dbProvider provider.IDBProvider,
+	cfg *config.Config,
+) http.HandlerFunc {
+	return http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		var form = forms.TwoFactorLoginForm{}
+		var err = json.NewDecoder(r.Body).Decode(&form)
+		if err == nil {
+			err = form.Validate()
+		}
+		if err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(resp, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		iDB, ok := requestDB(resp, dbProvider)
+		if !ok {
+			return
+		}
+
+		user, err := validate.LoginTwoFactor(iDB, cfg, form.Token, form.Code, clientIP(r), time.Now())
+		if err != nil {
+			log.Printf("LoginTwoFactorApi() error: %v", err)
+			respondLoginError(resp, err)
+			return
+		}
+
+		tokens, err := validate.NewSession(iDB, cfg, user, time.Now())
+		if err != nil {
+			log.Printf("LoginTwoFactorApi() validate.NewSession() error: %v", err)
+			resp.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(resp, utils.Message(false, "Authorization failed"))
+			return
+		}
+
+		log.Printf("Login successful for %v", user.Username)
 
+		respondTokens(resp, utils.Message(true, "Login successful"), user, tokens)
 	})
 }
+
+// respondLoginError - respond of rejected login attempt
+func respondLoginError(resp http.ResponseWriter, err error) {
+	if throttled, ok := err.(*validate.ThrottledError); ok {
+		resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		resp.WriteHeader(http.StatusTooManyRequests)
+		utils.JsonRespond(resp, utils.Message(false, consts.LoginThrottledResp))
+		return
+	}
+
+	switch err {
+	case sql.ErrNoRows:
+		resp.WriteHeader(http.StatusNotFound)
+		utils.JsonRespond(resp, utils.Message(false, consts.NotExistResp))
+	case validate.ErrNotAuthorized:
+		resp.WriteHeader(http.StatusUnauthorized)
+		utils.JsonRespond(resp, utils.Message(false, "Not authorized"))
+	case validate.ErrTwoFactorCode:
+		resp.WriteHeader(http.StatusUnauthorized)
+		utils.JsonRespond(resp, utils.Message(false, consts.TwoFactorCodeResp))
+	default:
+		resp.WriteHeader(http.StatusInternalServerError)
+		utils.JsonRespond(resp, utils.Message(false, consts.SmtWhenWrongResp))
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+// GetDeliveryList - queued emails newest first with per-recipient delivery status,
+// filtered by status (pending, sent or failed) query parameter
+func GetDeliveryList(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, count, ok := getPageAndCount(w, r)
+		if !ok {
+			return
+		}
+
+		var filter = models.DeliveryFilter{}
+		if status := r.URL.Query().Get("status"); status != "" {
+			filter.Status = models.ParseDeliveryStatus(status)
+			if filter.Status == 0 {
+				log.Printf("unknown delivery status: %s", status)
+				w.WriteHeader(http.StatusBadRequest)
+				utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+				return
+			}
+		}
+
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("database empty in db provider")
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		total, err := models.CountDeliveries(iDB, filter)
+		if err != nil {
+			log.Printf("models.CountDeliveries() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var deliveries = []*models.Delivery{}
+		if page > 0 {
+			start, _ := utils.Pagination(page, count)
+			deliveries, err = models.GetDeliveries(iDB, filter, start, count)
+			if err != nil {
+				log.Printf("models.GetDeliveries() error: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+				return
+			}
+		}
+
+		var respond = utils.Message(true, "")
+		respond[consts.KEY_DELIVERIES] = deliveries
+		respond["total"] = total
+		utils.JsonRespond(w, respond)
+	})
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+func NewEscalationPolicy(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var policy = models.EscalationPolicy{}
+		err := json.NewDecoder(r.Body).Decode(&policy)
+		if err != nil {
+			log.Printf("new escalation policy decode request body error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
+		err = policy.Validate()
+		if err != nil {
+			log.Printf("new escalation policy validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		err, exist := dbProvider.IsExistByName(&policy)
+		if exist && err == nil {
+			w.WriteHeader(http.StatusForbidden)
+			utils.JsonRespond(w, utils.Message(false, "An escalation policy with the same name already exists."))
+			return
+		}
+
+		if err != nil {
+			log.Printf("new escalation policy create error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = dbProvider.Save(&policy)
+		if err != nil {
+			log.Printf("new escalation policy save error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		respond := utils.Message(true, "New escalation policy created")
+		respond["policyID"] = policy.ID
+
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func GetEscalationPolicy(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iPolicy, err := dbProvider.GetByID(&models.EscalationPolicy{}, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.GetByID escalation policy error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		respond["policy"] = iPolicy
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func GetEscalationPolicyList(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, count, ok := getPageAndCount(w, r)
+		if !ok {
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		if page <= 0 {
+			utils.JsonRespond(w, respond)
+			return
+		}
+
+		start, end := utils.Pagination(page, count)
+
+		total, err := dbProvider.Count(&models.EscalationPolicy{})
+		if err != nil {
+			log.Printf("GetEscalationPolicyList() count error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		if start > total {
+			utils.JsonRespond(w, respond)
+			return
+		}
+
+		if end > total {
+			end = total
+		}
+
+		policies, err := dbProvider.Pagination(&models.EscalationPolicy{}, start, end)
+		if err != nil {
+			log.Printf("GetEscalationPolicyList() pagination error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		respond[consts.KEY_ESCALATION] = policies
+		respond["total"] = total
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func UpdateEscalationPolicy(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		var policy = models.EscalationPolicy{}
+		err := json.NewDecoder(r.Body).Decode(&policy)
+		if err != nil {
+			log.Printf("UpdateEscalationPolicy() decode error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
+		err = policy.Validate()
+		if err != nil {
+			log.Printf("UpdateEscalationPolicy() validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		err = dbProvider.Update(&policy, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.Update escalation policy error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		policy.ID = id
+		var respond = utils.Message(true, "escalation policy updated")
+		respond["policy"] = policy
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func DeleteEscalationPolicy(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		err := dbProvider.Delete(&models.EscalationPolicy{}, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.Delete escalation policy error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotDeletedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "escalation policy deleted"))
+	})
+}
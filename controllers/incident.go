@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+// GetIncidentList - incidents newest first, filtered by state (open or closed) and service_id query parameters
+func GetIncidentList(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, count, ok := getPageAndCount(w, r)
+		if !ok {
+			return
+		}
+
+		var filter = models.IncidentFilter{State: r.URL.Query().Get("state")}
+		if serviceID := r.URL.Query().Get("service_id"); serviceID != "" {
+			var err error
+			filter.ServiceID, err = strconv.Atoi(serviceID)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				utils.JsonRespond(w, utils.Message(false, consts.IdIsNotNumberResp))
+				return
+			}
+		}
+
+		err := filter.Validate()
+		if err != nil {
+			log.Printf("incidents filter validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("database empty in db provider")
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		total, err := models.CountIncidents(iDB, filter)
+		if err != nil {
+			log.Printf("models.CountIncidents() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var incidents = []*models.Incident{}
+		if page > 0 {
+			start, _ := utils.Pagination(page, count)
+			incidents, err = models.GetIncidents(iDB, filter, start, count)
+			if err != nil {
+				log.Printf("models.GetIncidents() error: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+				return
+			}
+		}
+
+		var respond = utils.Message(true, "")
+		respond[consts.KEY_INCIDENTS] = incidents
+		respond["total"] = total
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// GetIncident - incident with notes
+func GetIncident(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		incident, ok := getIncident(w, r, dbProvider)
+		if !ok {
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		respond["incident"] = incident
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// AcknowledgeIncident - mark incident as taken by authorized user
+func AcknowledgeIncident(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		incident, ok := getIncident(w, r, dbProvider)
+		if !ok {
+			return
+		}
+
+		iDB := dbProvider.GetDB().(*sql.DB)
+		err := incident.Acknowledge(iDB, requestUserID(r), time.Now())
+		if err != nil {
+			if err == models.ErrIncidentAcknowledged {
+				w.WriteHeader(http.StatusConflict)
+				utils.JsonRespond(w, utils.Message(false, consts.AlreadyAcknowledgedResp))
+				return
+			}
+			log.Printf("incident %d Acknowledge() error: %v", incident.ID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		var respond = utils.Message(true, "incident acknowledged")
+		respond["incident"] = incident
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// AddIncidentNote - add note of authorized user to incident
+func AddIncidentNote(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var note = models.IncidentNote{}
+		err := json.NewDecoder(r.Body).Decode(&note)
+		if err != nil {
+			log.Printf("incident note decode request body error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
+		if strings.TrimSpace(note.Text) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		incident, ok := getIncident(w, r, dbProvider)
+		if !ok {
+			return
+		}
+
+		note.ID = 0
+		note.UserID = requestUserID(r)
+		note.CreatedAt = time.Time{}
+
+		iDB := dbProvider.GetDB().(*sql.DB)
+		err = incident.AddNote(iDB, &note)
+		if err != nil {
+			log.Printf("incident %d AddNote() error: %v", incident.ID, err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		var respond = utils.Message(true, "incident note added")
+		respond["note"] = note
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// getIncident - incident by id from request url, writes error respond if incident not found
+func getIncident(w http.ResponseWriter, r *http.Request, dbProvider provider.IDBProvider) (*models.Incident, bool) {
+	id, ok := getRequestID(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	iIncident, err := dbProvider.GetByID(&models.Incident{}, int64(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+			return nil, false
+		}
+		log.Printf("dbProvider.GetByID incident error: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+		return nil, false
+	}
+
+	return iIncident.(*models.Incident), true
+}
+
+// getPageAndCount - page and count query parameters, writes bad request respond if they are invalid
+func getPageAndCount(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	page, count, err := utils.GetPageAndCountFromReq(r)
+	if err == nil {
+		return page, count, true
+	}
+
+	for _, resp := range []string{
+		consts.PageAndCountRequiredResp,
+		consts.PageMustNumberResp,
+		consts.CountMustNumberResp,
+	} {
+		if err.Error() == strings.ToLower(resp) {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, resp))
+			return 0, 0, false
+		}
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+	utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+	return 0, 0, false
+}
+
+// requestUserID - id of authorized user, 0 if request is not authorized
+func requestUserID(r *http.Request) int {
+	token := models.TokenFromContext(r.Context())
+	if token == nil {
+		return 0
+	}
+
+	return int(token.UserId)
+}
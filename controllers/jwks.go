@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"projectionist/utils"
+	"projectionist/validate"
+)
+
+// GetJWKS - public keys verifying access tokens as JWK set, authorization is not required
+func GetJWKS(keys *validate.KeySet) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(validate.JWKSMaxAge.Seconds())))
+		utils.JsonRespond(w, map[string]interface{}{"keys": keys.JWKS()})
+	})
+}
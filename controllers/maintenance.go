@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+func NewMaintenance(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serviceID, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		var window = models.MaintenanceWindow{}
+		err := json.NewDecoder(r.Body).Decode(&window)
+		if err != nil {
+			log.Printf("new maintenance decode request body error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
+		window.ServiceID = serviceID
+
+		err = window.Validate()
+		if err != nil {
+			log.Printf("new maintenance validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		if !serviceExist(w, dbProvider, serviceID) {
+			return
+		}
+
+		err = dbProvider.Save(&window)
+		if err != nil {
+			log.Printf("new maintenance save error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		respond := utils.Message(true, "New maintenance created")
+		respond["maintenanceID"] = window.ID
+
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func GetMaintenanceList(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serviceID, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("database empty in db provider")
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var service = &models.Service{ID: serviceID}
+		windows, err := service.GetMaintenanceWindows(iDB)
+		if err != nil {
+			log.Printf("GetMaintenanceWindows() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		respond[consts.KEY_MAINTENANCE] = windows
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func DeleteMaintenance(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serviceID, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		windowID, err := utils.GetIntVarFromReq(r, "window_id")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.IdIsNotNumberResp))
+			return
+		}
+
+		iWindow, err := dbProvider.GetByID(&models.MaintenanceWindow{}, int64(windowID))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.GetByID maintenance error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		if iWindow.(*models.MaintenanceWindow).ServiceID != serviceID {
+			w.WriteHeader(http.StatusNotFound)
+			utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+			return
+		}
+
+		err = dbProvider.Delete(&models.MaintenanceWindow{}, windowID)
+		if err != nil {
+			log.Printf("dbProvider.Delete maintenance error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotDeletedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "maintenance deleted"))
+	})
+}
+
+// getRequestID - id from request url, writes bad request respond if id is invalid
+func getRequestID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	var id, err = utils.GetIDFromReq(r)
+	if err != nil {
+		if err.Error() == strings.ToLower(consts.IdIsEmptyResp) {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.IdIsEmptyResp))
+			return 0, false
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		utils.JsonRespond(w, utils.Message(false, consts.IdIsNotNumberResp))
+		return 0, false
+	}
+
+	return id, true
+}
+
+// serviceExist - true if not deleted service with id exist, else writes not found respond
+func serviceExist(w http.ResponseWriter, dbProvider provider.IDBProvider, id int) bool {
+	iService, err := dbProvider.GetByID(&models.Service{}, int64(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+			return false
+		}
+		log.Printf("dbProvider.GetByID service error: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+		return false
+	}
+
+	if iService.IsDeleted() {
+		w.WriteHeader(http.StatusNotFound)
+		utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+		return false
+	}
+
+	return true
+}
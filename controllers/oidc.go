@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"projectionist/apps/oidc"
+	"projectionist/config"
+	"projectionist/consts"
+	"projectionist/provider"
+	"projectionist/utils"
+	"projectionist/validate"
+)
+
+// oidcStateCookie - cookie binding provider callback to browser started login
+const oidcStateCookie = "projectionist_oidc_state"
+
+// OIDCLoginApi - redirect to login page of single sign-on provider
+func OIDCLoginApi(oidcProvider *oidc.Provider, cfg *config.Config) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var now = time.Now()
+		cookie, state, nonce, err := oidc.NewState(cfg.TokenSecretKey, now)
+		if err != nil {
+			log.Printf("OIDCLoginApi() oidc.NewState() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		authURL, err := oidcProvider.AuthCodeURL(r.Context(), state, nonce)
+		if err != nil {
+			log.Printf("OIDCLoginApi() AuthCodeURL() error: %v", err)
+			w.WriteHeader(http.StatusBadGateway)
+			utils.JsonRespond(w, utils.Message(false, consts.SSOFailedResp))
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    cookie,
+			Path:     consts.UrlApiOIDCCallbackV1,
+			Expires:  now.Add(oidc.StateTTL),
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, authURL, http.StatusFound)
+	})
+}
+
+// OIDCCallbackApi - session of user returned by single sign-on provider with authorization code,
+// user is created on first login with role of its provider groups
+func OIDCCallbackApi(
+	dbProvider provider.IDBProvider,
+	cfg *config.Config,
+	oidcProvider *oidc.Provider,
+) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query = r.URL.Query()
+		if errCode := query.Get("error"); errCode != "" {
+			log.Printf("OIDCCallbackApi() provider error: %s %s", errCode, query.Get("error_description"))
+			w.WriteHeader(http.StatusUnauthorized)
+			utils.JsonRespond(w, utils.Message(false, consts.SSOFailedResp))
+			return
+		}
+
+		var now = time.Now()
+		var nonce string
+		cookie, err := r.Cookie(oidcStateCookie)
+		if err == nil {
+			nonce, err = oidc.ParseState(cfg.TokenSecretKey, cookie.Value, query.Get("state"), now)
+		}
+		if err != nil || query.Get("code") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		// state is single use
+		http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: consts.UrlApiOIDCCallbackV1, MaxAge: -1})
+
+		identity, err := oidcProvider.Exchange(r.Context(), query.Get("code"), nonce, now)
+		if err != nil {
+			log.Printf("OIDCCallbackApi() Exchange() error: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			utils.JsonRespond(w, utils.Message(false, consts.SSOFailedResp))
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		user, err := validate.LoginOIDC(iDB, cfg, identity.Subject, identity.Username, identity.Groups, clientIP(r), now)
+		switch err {
+		case nil:
+		case validate.ErrSSONoRole:
+			w.WriteHeader(http.StatusForbidden)
+			utils.JsonRespond(w, utils.Message(false, consts.SSONoRoleResp))
+			return
+		case validate.ErrSSOUsernameTaken:
+			w.WriteHeader(http.StatusConflict)
+			utils.JsonRespond(w, utils.Message(false, consts.SSOUsernameTakenResp))
+			return
+		default:
+			log.Printf("OIDCCallbackApi() validate.LoginOIDC() of %s error: %v", identity.Username, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		tokens, err := validate.NewSession(iDB, cfg, user, now)
+		if err != nil {
+			log.Printf("OIDCCallbackApi() validate.NewSession() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, "Authorization failed"))
+			return
+		}
+
+		log.Printf("Single sign-on login successful for %v", user.Username)
+
+		respondTokens(w, utils.Message(true, "Login successful"), user, tokens)
+	})
+}
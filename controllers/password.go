@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"projectionist/config"
+	"projectionist/consts"
+	"projectionist/forms"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+	"projectionist/validate"
+)
+
+// ChangePassword - replace password of request user after current password is checked,
+// other sessions are revoked and new tokens are responded
+func ChangePassword(dbProvider provider.IDBProvider, cfg *config.Config) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := sessionToken(w, r)
+		if !ok {
+			return
+		}
+
+		var form = forms.PasswordChangeForm{}
+		var err = json.NewDecoder(r.Body).Decode(&form)
+		if err == nil {
+			err = form.Validate()
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		var now = time.Now()
+		user, err := validate.ChangePassword(
+			iDB, cfg, int(token.UserId), form.CurrentPassword, form.NewPassword, clientIP(r), now,
+		)
+		if err != nil {
+			if respondPasswordError(w, err) {
+				return
+			}
+			log.Printf("ChangePassword() validate.ChangePassword() error: %v", err)
+			respondLoginError(w, err)
+			return
+		}
+
+		tokens, err := validate.NewSession(iDB, cfg, user, now)
+		if err != nil {
+			log.Printf("ChangePassword() validate.NewSession() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, "Authorization failed"))
+			return
+		}
+
+		respondTokens(w, utils.Message(true, "Password changed"), user, tokens)
+	})
+}
+
+// ResetPassword - set temporary password of user, user must change it on next login
+func ResetPassword(dbProvider provider.IDBProvider, cfg *config.Config) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		var form = forms.PasswordResetForm{}
+		var err = json.NewDecoder(r.Body).Decode(&form)
+		if err == nil {
+			err = form.Validate()
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		var user = &models.User{}
+		err = user.GetByID(iDB, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("user GetByID() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = validate.ResetPassword(iDB, cfg, user, form.Password, requestUserID(r), time.Now())
+		if err != nil {
+			if respondPasswordError(w, err) {
+				return
+			}
+			log.Printf("validate.ResetPassword() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "Password reset, user must change it on next login"))
+	})
+}
+
+// checkPasswordPolicy - respond policy violations of new password, false if password is not accepted
+func checkPasswordPolicy(w http.ResponseWriter, cfg *config.Config, username, password string) bool {
+	err := validate.CheckPasswordPolicy(&cfg.Auth.Password, username, password)
+	if err == nil {
+		return true
+	}
+
+	if !respondPasswordError(w, err) {
+		log.Printf("validate.CheckPasswordPolicy() error: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+	}
+
+	return false
+}
+
+// respondPasswordError - respond rejected password, false if err is not password error
+func respondPasswordError(w http.ResponseWriter, err error) bool {
+	if policyErr, ok := err.(*validate.PasswordPolicyError); ok {
+		respond := utils.Message(false, consts.PasswordPolicyResp)
+		respond["violations"] = policyErr.Violations
+		w.WriteHeader(http.StatusBadRequest)
+		utils.JsonRespond(w, respond)
+		return true
+	}
+
+	if err == validate.ErrPasswordExternal {
+		w.WriteHeader(http.StatusConflict)
+		utils.JsonRespond(w, utils.Message(false, consts.PasswordExternalResp))
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+func NewRecipientSettings(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var settings = models.RecipientSettings{}
+		err := json.NewDecoder(r.Body).Decode(&settings)
+		if err != nil {
+			log.Printf("new recipient settings decode request body error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
+		err = settings.Validate()
+		if err != nil {
+			log.Printf("new recipient settings validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		err, exist := dbProvider.IsExistByName(&settings)
+		if exist && err == nil {
+			w.WriteHeader(http.StatusForbidden)
+			utils.JsonRespond(w, utils.Message(false, "Settings of the same recipient already exist."))
+			return
+		}
+
+		if err != nil {
+			log.Printf("new recipient settings create error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = dbProvider.Save(&settings)
+		if err != nil {
+			log.Printf("new recipient settings save error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		respond := utils.Message(true, "New recipient settings created")
+		respond["settingsID"] = settings.ID
+
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func GetRecipientSettings(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iSettings, err := dbProvider.GetByID(&models.RecipientSettings{}, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.GetByID recipient settings error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		respond["settings"] = iSettings
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func GetRecipientSettingsList(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, count, ok := getPageAndCount(w, r)
+		if !ok {
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		if page <= 0 {
+			utils.JsonRespond(w, respond)
+			return
+		}
+
+		start, end := utils.Pagination(page, count)
+
+		total, err := dbProvider.Count(&models.RecipientSettings{})
+		if err != nil {
+			log.Printf("GetRecipientSettingsList() count error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		if start > total {
+			utils.JsonRespond(w, respond)
+			return
+		}
+
+		if end > total {
+			end = total
+		}
+
+		settingsList, err := dbProvider.Pagination(&models.RecipientSettings{}, start, end)
+		if err != nil {
+			log.Printf("GetRecipientSettingsList() pagination error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		respond[consts.KEY_RECIPIENTS] = settingsList
+		respond["total"] = total
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func UpdateRecipientSettings(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		var settings = models.RecipientSettings{}
+		err := json.NewDecoder(r.Body).Decode(&settings)
+		if err != nil {
+			log.Printf("UpdateRecipientSettings() decode error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
+		err = settings.Validate()
+		if err != nil {
+			log.Printf("UpdateRecipientSettings() validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		err = dbProvider.Update(&settings, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.Update recipient settings error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		settings.ID = id
+		var respond = utils.Message(true, "recipient settings updated")
+		respond["settings"] = settings
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func DeleteRecipientSettings(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		err := dbProvider.Delete(&models.RecipientSettings{}, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.Delete recipient settings error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotDeletedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "recipient settings deleted"))
+	})
+}
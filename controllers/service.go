@@ -1,19 +1,22 @@
 package controllers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"strings"
 
+	"projectionist/config"
 	"projectionist/consts"
 	"projectionist/models"
 	"projectionist/provider"
 	"projectionist/utils"
 )
 
-func NewService(dbProvider provider.IDBProvider, syncShan chan string) http.HandlerFunc {
+func NewService(dbProvider provider.IDBProvider, cfg *config.Config, syncShan chan string) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var service = models.Service{}
 
@@ -26,6 +29,9 @@ func NewService(dbProvider provider.IDBProvider, syncShan chan string) http.Hand
 		}
 
 		err = service.Validate()
+		if err == nil {
+			err = service.ValidateTimeout(cfg.HealthCheck.Timeout)
+		}
 		if err != nil {
 			log.Printf("new service validate error: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
@@ -115,12 +121,63 @@ func GetService(dbProvider provider.IDBProvider) http.HandlerFunc {
 			return
 		}
 
+		service.Channels, err = service.GetChannels(iDB.(*sql.DB))
+		if err != nil {
+			log.Printf("error: get service channels: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		service.Dependencies, err = service.GetDependencies(iDB.(*sql.DB))
+		if err != nil {
+			log.Printf("error: get service dependencies: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		service.Maintenance, err = service.GetMaintenanceWindows(iDB.(*sql.DB))
+		if err != nil {
+			log.Printf("error: get service maintenance: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		service.HideChannelSecrets()
+
 		var respond = utils.Message(true, "")
 		respond["service"] = service
 		utils.JsonRespond(w, respond)
 	})
 }
 
+// GetServiceGraph - dependency graph of services with current statuses
+func GetServiceGraph(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("database empty in db provider")
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		graph, err := models.GetServiceGraph(iDB)
+		if err != nil {
+			log.Printf("models.GetServiceGraph() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		respond["graph"] = graph
+		utils.JsonRespond(w, respond)
+	})
+}
+
 func GetServiceList(dbProvider provider.IDBProvider) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		page, count, err := utils.GetPageAndCountFromReq(r)
@@ -184,13 +241,20 @@ func GetServiceList(dbProvider provider.IDBProvider) http.HandlerFunc {
 			return
 		}
 
+		for _, serviceModel := range serviceModels {
+			if service, ok := serviceModel.(*models.Service); ok {
+				service.HideChannelSecrets()
+			}
+		}
+
 		var respond = utils.Message(true, "")
 		respond[consts.KEY_SERVICES] = serviceModels
 		utils.JsonRespond(w, respond)
 	})
 }
 
-func UpdateService(dbProvider provider.IDBProvider, syncShan chan string) http.HandlerFunc {
+// UpdateService - update fields of service present in request, service merged with them must be valid
+func UpdateService(dbProvider provider.IDBProvider, cfg *config.Config, syncShan chan string) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var id, err = utils.GetIDFromReq(r)
 		if err != nil {
@@ -204,8 +268,20 @@ func UpdateService(dbProvider provider.IDBProvider, syncShan chan string) http.H
 			return
 		}
 
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			log.Printf("read request body error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+			return
+		}
+
 		var service = models.Service{ID: id}
-		err = json.NewDecoder(r.Body).Decode(&service)
+		var fields = map[string]json.RawMessage{}
+		err = json.Unmarshal(body, &service)
+		if err == nil {
+			err = json.Unmarshal(body, &fields)
+		}
 		if err != nil {
 			log.Printf("decode request body error: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
@@ -213,6 +289,52 @@ func UpdateService(dbProvider provider.IDBProvider, syncShan chan string) http.H
 			return
 		}
 
+		// zero values of present fields are saved, e.g. timeout 0 resets it to config default
+		service.UpdateFields = make(map[string]bool, len(fields))
+		for field := range fields {
+			service.UpdateFields[strings.ToLower(field)] = true
+		}
+
+		iCurrent, err := dbProvider.GetByID(&models.Service{}, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.GetByID error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		current, ok := iCurrent.(*models.Service)
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		// service after update must be valid as new one
+		var merged = *current
+		err = json.Unmarshal(body, &merged)
+		if err == nil {
+			merged.UpdateFields = service.UpdateFields
+			err = merged.ValidateUpdate()
+		}
+		if err == nil {
+			err = merged.ValidateTimeout(cfg.HealthCheck.Timeout)
+		}
+		if err != nil {
+			log.Printf("update service validate error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		// certificate expiry is set only by health checker
+		service.CertExpiresAt = nil
+
 		err = dbProvider.Update(&service, id)
 		if err != nil {
 			log.Printf("dbProvider.Update update service error: %v", err)
@@ -231,6 +353,8 @@ func UpdateService(dbProvider provider.IDBProvider, syncShan chan string) http.H
 
 		syncShan <- iService.GetName()
 
+		service.HideChannelSecrets()
+
 		var respond = utils.Message(true, "service updated")
 		respond["service"] = service
 		utils.JsonRespond(w, respond)
@@ -273,3 +397,100 @@ func DeleteService(dbProvider provider.IDBProvider, syncChan chan string) http.H
 		utils.JsonRespond(w, respond)
 	})
 }
+
+// Checker - checks service health on demand
+type Checker interface {
+	CheckNow(ctx context.Context, serviceID int) (*models.CheckResult, error)
+	Metrics() models.CheckerMetrics
+}
+
+// GetCheckerMetrics - respond with health checker probe pool metrics
+func GetCheckerMetrics(checker Checker) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var respond = utils.Message(true, "")
+		respond["metrics"] = checker.Metrics()
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// CheckService - check service health immediately and respond with check result
+func CheckService(checker Checker) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		result, err := checker.CheckNow(r.Context(), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("checker.CheckNow() service %d error: %v", id, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var respond = utils.Message(true, "service checked")
+		respond["result"] = result
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// PauseService - stop planned health checks of service without deleting it
+func PauseService(dbProvider provider.IDBProvider, syncChan chan string) http.HandlerFunc {
+	return setServicePaused(dbProvider, syncChan, true, "service paused")
+}
+
+// ResumeService - resume planned health checks of paused service
+func ResumeService(dbProvider provider.IDBProvider, syncChan chan string) http.HandlerFunc {
+	return setServicePaused(dbProvider, syncChan, false, "service resumed")
+}
+
+func setServicePaused(dbProvider provider.IDBProvider, syncChan chan string, paused bool, message string) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := dbProvider.GetDB().(*sql.DB)
+		if !ok || iDB == nil {
+			log.Printf("database empty in db provider")
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var service = &models.Service{}
+		err := service.GetByID(iDB, int64(id))
+		if err == nil && service.IsDeleted() {
+			err = sql.ErrNoRows
+		}
+
+		if err == nil {
+			err = service.SetPaused(iDB, paused)
+		}
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("service %d SetPaused(%v) error: %v", id, paused, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		syncChan <- service.Name
+
+		var respond = utils.Message(true, message)
+		respond["service"] = service
+		utils.JsonRespond(w, respond)
+	})
+}
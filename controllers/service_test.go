@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	_ "github.com/mattn/go-sqlite3"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"projectionist/config"
+	"projectionist/consts"
+	"projectionist/db"
+	"projectionist/middleware"
+	"projectionist/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testChecker struct {
+	result *models.CheckResult
+	err    error
+}
+
+func (c *testChecker) CheckNow(ctx context.Context, serviceID int) (*models.CheckResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.result.ServiceID = serviceID
+	return c.result, nil
+}
+
+func (c *testChecker) Metrics() models.CheckerMetrics {
+	return models.CheckerMetrics{}
+}
+
+func TestCheckService(t *testing.T) {
+	type args struct {
+		queryArgs map[string]string
+		checker   *testChecker
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		wantCode int
+		wantResp map[string]interface{}
+	}{
+		{
+			name: "ok",
+			args: args{
+				queryArgs: map[string]string{"id": "1"},
+				checker:   &testChecker{result: &models.CheckResult{Status: models.CheckSuccess, Attempts: 1}},
+			},
+			wantCode: http.StatusOK,
+			wantResp: map[string]interface{}{
+				"status":  true,
+				"message": "service checked",
+			},
+		},
+		{
+			name:     "id not number",
+			args:     args{queryArgs: map[string]string{"id": "test"}, checker: &testChecker{}},
+			wantCode: http.StatusBadRequest,
+			wantResp: map[string]interface{}{
+				"status":  false,
+				"message": consts.IdIsNotNumberResp,
+			},
+		},
+		{
+			name:     "service not found",
+			args:     args{queryArgs: map[string]string{"id": "1"}, checker: &testChecker{err: sql.ErrNoRows}},
+			wantCode: http.StatusNotFound,
+			wantResp: map[string]interface{}{
+				"status":  false,
+				"message": consts.NotExistResp,
+			},
+		},
+		{
+			name:     "check error",
+			args:     args{queryArgs: map[string]string{"id": "1"}, checker: &testChecker{err: errors.New("check error")}},
+			wantCode: http.StatusInternalServerError,
+			wantResp: map[string]interface{}{
+				"status":  false,
+				"message": consts.SmtWhenWrongResp,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodPost, consts.UrlServiceCheckV1, nil)
+			if err != nil {
+				t.Fatalf("New Request error: %v", err)
+			}
+
+			request = mux.SetURLVars(request, tt.args.queryArgs)
+			recorder := httptest.NewRecorder()
+
+			handler := CheckService(tt.args.checker)
+			handler.ServeHTTP(recorder, request)
+
+			body, err := ioutil.ReadAll(recorder.Body)
+			if err != nil {
+				t.Errorf("Read response error:%v", err)
+			}
+
+			if recorder.Code != tt.wantCode {
+				t.Errorf("response code got %v wantResp %v", recorder.Code, tt.wantCode)
+			}
+
+			var gotResp = map[string]interface{}{}
+
+			err = json.Unmarshal(body, &gotResp)
+			if err != nil {
+				t.Errorf("Unmarshal response body error:%v", err)
+			}
+
+			for wantKey, wantValue := range tt.wantResp {
+				if !reflect.DeepEqual(wantValue, gotResp[wantKey]) {
+					t.Errorf("CheckService() key `%v`, got `%+v`, want `%+v`", wantKey, gotResp[wantKey], wantValue)
+				}
+			}
+
+			if tt.wantCode == http.StatusOK {
+				result, ok := gotResp["result"].(map[string]interface{})
+				if !ok || result["service_id"] != float64(1) {
+					t.Errorf("CheckService() result got `%+v`", gotResp["result"])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateService(t *testing.T) {
+	var cfg = &config.Config{}
+	cfg.HealthCheck.Timeout = 30
+
+	tests := []struct {
+		name        string
+		body        string
+		wantCode    int
+		wantUpdated map[string]bool // update fields of saved service
+	}{
+		{
+			name:     "timeout not less than frequency",
+			body:     `{"timeout": 60}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "too many retries",
+			body:     fmt.Sprintf(`{"retries": %d}`, models.MaxRetries+1),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "default timeout not less than frequency",
+			body:     `{"timeout": 0, "frequency": 20}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:        "reset to defaults",
+			body:        `{"timeout": 0, "retries": 0, "retry_backoff": 0, "cert_warn_days": 0}`,
+			wantCode:    http.StatusOK,
+			wantUpdated: map[string]bool{"timeout": true, "retries": true, "retry_backoff": true, "cert_warn_days": true},
+		},
+		{
+			name:     "invalid status",
+			body:     `{"status": 3}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHelper(t)
+			defer h.ctrl.Finish()
+
+			var current = &models.Service{
+				ID: 1, Name: "service", Link: "http://127.0.0.1/health", Token: "token",
+				Frequency: 60, Status: models.Warning, Timeout: 10, Retries: 2, RetryBackoff: 5, CertWarnDays: 7,
+			}
+			h.mockProvider.GetByID(&models.Service{}, int64(1)).Return(current, nil).AnyTimes()
+
+			var updated *models.Service
+			if tt.wantCode == http.StatusOK {
+				h.mockProvider.Update(gomock.Any(), 1).DoAndReturn(func(model models.Model, id int) error {
+					updated = model.(*models.Service)
+					return nil
+				})
+			}
+
+			request, err := http.NewRequest(http.MethodPut, consts.UrlServiceV1+"/1", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("New Request error: %v", err)
+			}
+			request = mux.SetURLVars(request, map[string]string{"id": "1"})
+			recorder := httptest.NewRecorder()
+
+			UpdateService(h.provider, cfg, make(chan string, 1)).ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("response code got %v want %v: %s", recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+
+			if tt.wantCode == http.StatusOK && !reflect.DeepEqual(updated.UpdateFields, tt.wantUpdated) {
+				t.Errorf("UpdateService() update fields got %v want %v", updated.UpdateFields, tt.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestGetService_HidesChannelSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "service")
+	if err != nil {
+		t.Fatalf("temp dir error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(dir, "test.sqlite"))
+	if err != nil {
+		t.Fatalf("open db error: %v", err)
+	}
+	defer sqlDB.Close()
+
+	if err = db.InitTables(sqlDB); err != nil {
+		t.Fatalf("init tables error: %v", err)
+	}
+
+	var channel = &models.Channel{ServiceID: 1, Type: models.ChannelWebhook, URL: "http://127.0.0.1/hook", Secret: "hmac-key"}
+	if err = channel.Save(sqlDB); err != nil {
+		t.Fatalf("channel Save() error: %v", err)
+	}
+
+	h := NewHelper(t)
+	defer h.ctrl.Finish()
+
+	h.mockProvider.GetByID(&models.Service{}, int64(1)).Return(&models.Service{ID: 1, Name: "service"}, nil)
+	h.mockProvider.GetDB().Return(sqlDB)
+
+	request, err := http.NewRequest(http.MethodGet, consts.UrlServiceV1+"/1", nil)
+	if err != nil {
+		t.Fatalf("New Request error: %v", err)
+	}
+	request = mux.SetURLVars(request, map[string]string{"id": "1"})
+	request = request.WithContext(models.ContextWithToken(request.Context(), &models.Token{UserId: 1, Role: models.Viewer}))
+	recorder := httptest.NewRecorder()
+
+	middleware.Permit(models.ResourceService, models.ActionRead, GetService(h.provider)).ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("response code got %v want %v: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var resp struct {
+		Service models.Service `json:"service"`
+	}
+	if err = json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response error: %v", err)
+	}
+	if len(resp.Service.Channels) != 1 || resp.Service.Channels[0].URL != channel.URL {
+		t.Fatalf("GetService() channels got %+v", resp.Service.Channels)
+	}
+	if strings.Contains(recorder.Body.String(), channel.Secret) || strings.Contains(recorder.Body.String(), `"secret"`) {
+		t.Errorf("GetService() response contains channel secret: %s", recorder.Body.String())
+	}
+}
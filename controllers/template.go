@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"projectionist/apps/notifier"
+	"projectionist/consts"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+)
+
+func NewTemplate(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tmpl, ok := decodeTemplate(w, r)
+		if !ok {
+			return
+		}
+
+		err, exist := dbProvider.IsExistByName(tmpl)
+		if exist && err == nil {
+			w.WriteHeader(http.StatusForbidden)
+			utils.JsonRespond(w, utils.Message(false, "A template of the same channel and event already exists."))
+			return
+		}
+
+		if err != nil {
+			log.Printf("new template create error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = dbProvider.Save(tmpl)
+		if err != nil {
+			log.Printf("new template save error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		respond := utils.Message(true, "New template created")
+		respond["templateID"] = tmpl.ID
+
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func GetTemplate(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iTemplate, err := dbProvider.GetByID(&models.NotificationTemplate{}, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.GetByID template error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		respond["template"] = iTemplate
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func GetTemplateList(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, count, ok := getPageAndCount(w, r)
+		if !ok {
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		if page <= 0 {
+			utils.JsonRespond(w, respond)
+			return
+		}
+
+		start, end := utils.Pagination(page, count)
+
+		total, err := dbProvider.Count(&models.NotificationTemplate{})
+		if err != nil {
+			log.Printf("GetTemplateList() count error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		if start > total {
+			utils.JsonRespond(w, respond)
+			return
+		}
+
+		if end > total {
+			end = total
+		}
+
+		templates, err := dbProvider.Pagination(&models.NotificationTemplate{}, start, end)
+		if err != nil {
+			log.Printf("GetTemplateList() pagination error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		respond[consts.KEY_TEMPLATES] = templates
+		respond["total"] = total
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func UpdateTemplate(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		tmpl, ok := decodeTemplate(w, r)
+		if !ok {
+			return
+		}
+
+		err := dbProvider.Update(tmpl, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.Update template error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		tmpl.ID = id
+		var respond = utils.Message(true, "template updated")
+		respond["template"] = tmpl
+		utils.JsonRespond(w, respond)
+	})
+}
+
+func DeleteTemplate(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		err := dbProvider.Delete(&models.NotificationTemplate{}, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("dbProvider.Delete template error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotDeletedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "template deleted"))
+	})
+}
+
+// PreviewTemplate - render template from request body with sample data of its event
+func PreviewTemplate() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tmpl, ok := decodeTemplate(w, r)
+		if !ok {
+			return
+		}
+
+		message, err := apps.Render(*tmpl, apps.SampleData(tmpl.Event))
+		if err != nil {
+			log.Printf("PreviewTemplate() render error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.TemplateRenderResp))
+			return
+		}
+
+		var respond = utils.Message(true, "")
+		respond["subject"] = message.Subject
+		respond["body"] = message.Body
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// decodeTemplate - valid template from request body, writes bad request respond if template is invalid
+func decodeTemplate(w http.ResponseWriter, r *http.Request) (*models.NotificationTemplate, bool) {
+	var tmpl = &models.NotificationTemplate{}
+	err := json.NewDecoder(r.Body).Decode(tmpl)
+	if err != nil {
+		log.Printf("template decode request body error: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		utils.JsonRespond(w, utils.Message(false, consts.BadInputDataResp))
+		return nil, false
+	}
+
+	err = tmpl.Validate()
+	if err != nil {
+		log.Printf("template validate error: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+		return nil, false
+	}
+
+	return tmpl, true
+}
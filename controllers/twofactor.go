@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"projectionist/consts"
+	"projectionist/forms"
+	"projectionist/models"
+	"projectionist/provider"
+	"projectionist/utils"
+	"projectionist/validate"
+)
+
+// EnrollTwoFactor - new authenticator of request user, provisioning uri is shown to scan it by authenticator app
+func EnrollTwoFactor(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := sessionToken(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		var user = &models.User{}
+		err := user.GetByID(iDB, int64(token.UserId))
+		if err != nil {
+			log.Printf("user GetByID() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		uri, err := validate.EnrollTwoFactor(iDB, user, time.Now())
+		if err != nil {
+			if err == validate.ErrTwoFactorEnabled {
+				w.WriteHeader(http.StatusConflict)
+				utils.JsonRespond(w, utils.Message(false, consts.TwoFactorEnabledResp))
+				return
+			}
+			log.Printf("validate.EnrollTwoFactor() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotSavedResp))
+			return
+		}
+
+		respond := utils.Message(true, "Confirm two-factor authentication by authenticator code")
+		respond["uri"] = uri
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// ConfirmTwoFactor - enable enrolled authenticator of request user by its code, recovery codes are shown once
+func ConfirmTwoFactor(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := sessionToken(w, r)
+		if !ok {
+			return
+		}
+
+		var form = forms.TwoFactorCodeForm{}
+		var err = json.NewDecoder(r.Body).Decode(&form)
+		if err == nil {
+			err = form.Validate()
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.InputDataInvalidResp))
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		codes, err := validate.ConfirmTwoFactor(iDB, int(token.UserId), form.Code, time.Now())
+		switch err {
+		case nil:
+		case validate.ErrTwoFactorCode:
+			w.WriteHeader(http.StatusBadRequest)
+			utils.JsonRespond(w, utils.Message(false, consts.TwoFactorCodeResp))
+			return
+		case validate.ErrTwoFactorNotEnrolled:
+			w.WriteHeader(http.StatusNotFound)
+			utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+			return
+		case validate.ErrTwoFactorEnabled:
+			w.WriteHeader(http.StatusConflict)
+			utils.JsonRespond(w, utils.Message(false, consts.TwoFactorEnabledResp))
+			return
+		default:
+			log.Printf("validate.ConfirmTwoFactor() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		respond := utils.Message(true, "Two-factor authentication enabled")
+		respond["recovery_codes"] = codes
+		utils.JsonRespond(w, respond)
+	})
+}
+
+// ResetTwoFactor - remove authenticator of user, e.g. when device is lost
+func ResetTwoFactor(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		var user = &models.User{}
+		err := user.GetByID(iDB, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("user GetByID() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = validate.ResetTwoFactor(iDB, user, requestUserID(r), time.Now())
+		if err != nil {
+			log.Printf("validate.ResetTwoFactor() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotDeletedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "Two-factor authentication reset"))
+	})
+}
@@ -5,14 +5,17 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"projectionist/config"
 	"projectionist/consts"
 	"projectionist/models"
 	"projectionist/provider"
 	"projectionist/utils"
+	"projectionist/validate"
 	"strings"
+	"time"
 )
 
-func NewUser(dbProvider provider.IDBProvider) http.HandlerFunc {
+func NewUser(dbProvider provider.IDBProvider, cfg *config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var user = models.User{}
 
@@ -32,6 +35,10 @@ func NewUser(dbProvider provider.IDBProvider) http.HandlerFunc {
 			return
 		}
 
+		if !checkPasswordPolicy(w, cfg, user.Username, user.Password) {
+			return
+		}
+
 		err, exist := dbProvider.IsExistByName(&user)
 		if exist && err == nil {
 			w.WriteHeader(http.StatusForbidden)
@@ -258,3 +265,42 @@ func DeleteUser(dbProvider provider.IDBProvider) http.HandlerFunc {
 		return
 	})
 }
+
+// UnlockUser - forget failed logins of user locked out after too many of them
+func UnlockUser(dbProvider provider.IDBProvider) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := getRequestID(w, r)
+		if !ok {
+			return
+		}
+
+		iDB, ok := requestDB(w, dbProvider)
+		if !ok {
+			return
+		}
+
+		var user = &models.User{}
+		err := user.GetByID(iDB, int64(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				w.WriteHeader(http.StatusNotFound)
+				utils.JsonRespond(w, utils.Message(false, consts.NotExistResp))
+				return
+			}
+			log.Printf("user GetByID() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.SmtWhenWrongResp))
+			return
+		}
+
+		err = validate.UnlockUser(iDB, user, requestUserID(r), time.Now())
+		if err != nil {
+			log.Printf("validate.UnlockUser() error: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			utils.JsonRespond(w, utils.Message(false, consts.NotUpdatedResp))
+			return
+		}
+
+		utils.JsonRespond(w, utils.Message(true, "User unlocked"))
+	})
+}
@@ -2,8 +2,27 @@ package db
 
 import (
 	"database/sql"
+	"strings"
 )
 
+var migrations = []string{
+	ALTER_TBL_SERVICE_TIMEOUT,
+	ALTER_TBL_SERVICE_RETRIES,
+	ALTER_TBL_SERVICE_RETRY_BACKOFF,
+	ALTER_TBL_SERVICE_CERT_WARN,
+	ALTER_TBL_SERVICE_CERT_EXPIRES,
+	ALTER_TBL_SERVICE_PAUSED,
+	ALTER_TBL_SERVICE_PUBLIC,
+	ALTER_TBL_SERVICE_ESCALATION,
+	ALTER_TBL_INCIDENT_ESCALATION,
+	ALTER_TBL_INCIDENT_ESCALATED,
+	ALTER_TBL_DELIVERY_DIGEST,
+	ALTER_TBL_USER_OIDC_SUBJECT,
+	INDEX_USER_OIDC_SUBJECT,
+	ALTER_TBL_USER_LDAP_DN,
+	ALTER_TBL_USER_PASSWORD_CHANGE,
+}
+
 // createTableUsers create table users if not exist
 func createTableUsers(sqlDB *sql.DB) error {
 	_, err := sqlDB.Exec(CREATE_TBL_USERS)
@@ -21,6 +40,108 @@ func createTableEmails(sqlDB *sql.DB) error {
 	return err
 }
 
+func createTableChannels(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_CHANNELS)
+	return err
+}
+
+func createTableServiceDependencies(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_SERVICE_DEPENDENCIES)
+	return err
+}
+
+func createTableMaintenanceWindows(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_MAINTENANCE_WINDOWS)
+	return err
+}
+
+func createTableServiceStatusChanges(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_SERVICE_STATUS_CHANGES)
+	return err
+}
+
+func createTableIncidents(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_INCIDENTS)
+	return err
+}
+
+func createTableIncidentNotes(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_INCIDENT_NOTES)
+	return err
+}
+
+func createTableEscalationPolicies(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_ESCALATION_POLICIES)
+	return err
+}
+
+func createTableEscalationLevels(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_ESCALATION_LEVELS)
+	return err
+}
+
+func createTableNotificationTemplates(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_NOTIFICATION_TEMPLATES)
+	return err
+}
+
+func createTableDeliveries(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_DELIVERIES)
+	return err
+}
+
+func createTableRecipientSettings(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_RECIPIENT_SETTINGS)
+	return err
+}
+
+func createTableSessions(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_SESSIONS)
+	return err
+}
+
+func createTableAPIKeys(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_API_KEYS)
+	return err
+}
+
+func createTableLoginThrottles(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_LOGIN_THROTTLES)
+	return err
+}
+
+func createTableAuditLog(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_AUDIT_LOG)
+	return err
+}
+
+func createTableTwoFactors(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_TWO_FACTORS)
+	return err
+}
+
+func createTableRecoveryCodes(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_RECOVERY_CODES)
+	return err
+}
+
+func createTableSigningKeys(sqlDB *sql.DB) error {
+	_, err := sqlDB.Exec(CREATE_TBL_SIGNING_KEYS)
+	return err
+}
+
+// migrate add new columns to tables created by previous versions
+func migrate(sqlDB *sql.DB) error {
+	for _, query := range migrations {
+		_, err := sqlDB.Exec(query)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func InitTables(sqlDB *sql.DB) error {
 	var err error
 	if err = createTableServices(sqlDB); err != nil {
@@ -35,5 +156,81 @@ func InitTables(sqlDB *sql.DB) error {
 		return err
 	}
 
+	if err = createTableChannels(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableServiceDependencies(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableMaintenanceWindows(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableServiceStatusChanges(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableIncidents(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableIncidentNotes(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableEscalationPolicies(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableEscalationLevels(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableNotificationTemplates(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableDeliveries(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableRecipientSettings(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableSessions(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableAPIKeys(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableLoginThrottles(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableAuditLog(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableTwoFactors(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableRecoveryCodes(sqlDB); err != nil {
+		return err
+	}
+
+	if err = createTableSigningKeys(sqlDB); err != nil {
+		return err
+	}
+
+	if err = migrate(sqlDB); err != nil {
+		return err
+	}
+
 	return nil
 }
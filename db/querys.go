@@ -27,7 +27,15 @@ create table if not exists services
 	Token   TEXT(1000) not null,
 	frequency int default 100,
 	status  int default 1,
-	deleted int default 0
+	deleted int default 0,
+	timeout int default 0,
+	retries int default 0,
+	retry_backoff int default 0,
+	cert_warn_days int default 0,
+	cert_expires_at datetime,
+	paused int default 0,
+	public int default 0,
+	escalation_policy_id int default 0
 );
 
 create unique index if not exists services_name_uindex
@@ -44,4 +52,311 @@ create table if not exists emails
 	email TEXT(500) not null
 );
 `
+
+	CREATE_TBL_CHANNELS = `
+create table if not exists channels
+(
+	id INTEGER
+		constraint channels_pk
+			primary key autoincrement,
+	service_id INTEGER not null,
+	type       TEXT(50) not null,
+	url        TEXT(1000) not null,
+	secret     TEXT(500) default '',
+	chat_id    TEXT(255) default ''
+);
+
+create index if not exists channels_service_id_index
+    on channels (service_id);
+`
+
+	CREATE_TBL_SERVICE_DEPENDENCIES = `
+create table if not exists service_dependencies
+(
+	service_id INTEGER not null,
+	depends_on INTEGER not null
+);
+
+create unique index if not exists service_dependencies_uindex
+    on service_dependencies (service_id, depends_on);
+`
+
+	CREATE_TBL_MAINTENANCE_WINDOWS = `
+create table if not exists maintenance_windows
+(
+	id INTEGER
+		constraint maintenance_windows_pk
+			primary key autoincrement,
+	service_id INTEGER not null,
+	name       TEXT(255) default '',
+	mode       int default 1,
+	starts_at  datetime,
+	ends_at    datetime,
+	cron       TEXT(255),
+	duration   int default 0
+);
+
+create index if not exists maintenance_windows_service_id_index
+    on maintenance_windows (service_id);
+`
+
+	CREATE_TBL_SERVICE_STATUS_CHANGES = `
+create table if not exists service_status_changes
+(
+	id INTEGER
+		constraint service_status_changes_pk
+			primary key autoincrement,
+	service_id INTEGER not null,
+	status     int not null,
+	changed_at datetime not null
+);
+
+create index if not exists service_status_changes_service_id_index
+    on service_status_changes (service_id, changed_at);
+`
+
+	CREATE_TBL_INCIDENTS = `
+create table if not exists incidents
+(
+	id INTEGER
+		constraint incidents_pk
+			primary key autoincrement,
+	service_id      INTEGER not null,
+	reason          TEXT(1000) default '',
+	started_at      datetime not null,
+	ended_at        datetime,
+	acknowledged_at datetime,
+	acknowledged_by INTEGER,
+	escalation_level int default 0,
+	escalated_at    datetime
+);
+
+create index if not exists incidents_service_id_index
+    on incidents (service_id, ended_at);
+`
+
+	CREATE_TBL_INCIDENT_NOTES = `
+create table if not exists incident_notes
+(
+	id INTEGER
+		constraint incident_notes_pk
+			primary key autoincrement,
+	incident_id INTEGER not null,
+	user_id     INTEGER,
+	text        TEXT(5000) not null,
+	created_at  datetime not null
+);
+
+create index if not exists incident_notes_incident_id_index
+    on incident_notes (incident_id);
+`
+
+	CREATE_TBL_ESCALATION_POLICIES = `
+create table if not exists escalation_policies
+(
+	id INTEGER
+		constraint escalation_policies_pk
+			primary key autoincrement,
+	name            TEXT(255) not null,
+	repeat_interval int default 0
+);
+
+create unique index if not exists escalation_policies_name_uindex
+    on escalation_policies (name);
+`
+
+	CREATE_TBL_ESCALATION_LEVELS = `
+create table if not exists escalation_levels
+(
+	policy_id INTEGER not null,
+	position  int not null,
+	delay     int default 0,
+	emails    TEXT(5000) not null
+);
+
+create index if not exists escalation_levels_policy_id_index
+    on escalation_levels (policy_id);
+`
+
+	CREATE_TBL_NOTIFICATION_TEMPLATES = `
+create table if not exists notification_templates
+(
+	id INTEGER
+		constraint notification_templates_pk
+			primary key autoincrement,
+	channel TEXT(50) not null,
+	event   TEXT(50) not null,
+	subject TEXT(1000) default '',
+	body    TEXT(10000) not null
+);
+
+create unique index if not exists notification_templates_uindex
+    on notification_templates (channel, event);
+`
+
+	CREATE_TBL_DELIVERIES = `
+create table if not exists deliveries
+(
+	id INTEGER
+		constraint deliveries_pk
+			primary key autoincrement,
+	message_id      TEXT(255) not null,
+	recipient       TEXT(500) not null,
+	subject         TEXT(1000) default '',
+	body            TEXT not null,
+	status          int default 1,
+	attempts        int default 0,
+	last_error      TEXT(1000) default '',
+	created_at      datetime not null,
+	next_attempt_at datetime not null,
+	sent_at         datetime,
+	digest_of       int default 0
+);
+
+create index if not exists deliveries_status_index
+    on deliveries (status, next_attempt_at);
+`
+
+	CREATE_TBL_RECIPIENT_SETTINGS = `
+create table if not exists recipient_settings
+(
+	id INTEGER
+		constraint recipient_settings_pk
+			primary key autoincrement,
+	email         TEXT(500) not null,
+	digest_window int,
+	rate_limit    int
+);
+
+create unique index if not exists recipient_settings_email_uindex
+    on recipient_settings (email);
+`
+
+	CREATE_TBL_SESSIONS = `
+create table if not exists sessions
+(
+	id INTEGER
+		constraint sessions_pk
+			primary key autoincrement,
+	user_id    int not null,
+	token_hash TEXT(64) not null,
+	created_at datetime not null,
+	expires_at datetime not null,
+	revoked_at datetime
+);
+
+create unique index if not exists sessions_token_hash_uindex
+    on sessions (token_hash);
+`
+
+	CREATE_TBL_API_KEYS = `
+create table if not exists api_keys
+(
+	id INTEGER
+		constraint api_keys_pk
+			primary key autoincrement,
+	user_id      int not null,
+	name         TEXT(255) not null,
+	prefix       TEXT(20) not null,
+	key_hash     TEXT(64) not null,
+	scopes       TEXT(1000) not null,
+	created_at   datetime not null,
+	expires_at   datetime,
+	last_used_at datetime,
+	revoked_at   datetime
+);
+
+create unique index if not exists api_keys_key_hash_uindex
+    on api_keys (key_hash);
+`
+
+	CREATE_TBL_LOGIN_THROTTLES = `
+create table if not exists login_throttles
+(
+	key             TEXT(500) not null
+		constraint login_throttles_pk
+			primary key,
+	failures        int default 0,
+	last_failure_at datetime not null,
+	locked_until    datetime
+);
+`
+
+	CREATE_TBL_TWO_FACTORS = `
+create table if not exists two_factors
+(
+	user_id      int not null
+		constraint two_factors_pk
+			primary key,
+	secret       TEXT(100) not null,
+	created_at   datetime not null,
+	confirmed_at datetime,
+	last_counter int default 0
+);
+`
+
+	CREATE_TBL_RECOVERY_CODES = `
+create table if not exists recovery_codes
+(
+	id INTEGER
+		constraint recovery_codes_pk
+			primary key autoincrement,
+	user_id   int not null,
+	code_hash TEXT(64) not null,
+	used_at   datetime
+);
+
+create index if not exists recovery_codes_user_index
+    on recovery_codes (user_id);
+`
+
+	CREATE_TBL_AUDIT_LOG = `
+create table if not exists audit_log
+(
+	id INTEGER
+		constraint audit_log_pk
+			primary key autoincrement,
+	event      TEXT(50) not null,
+	username   TEXT(255) default '',
+	ip         TEXT(100) default '',
+	user_id    int default 0,
+	details    TEXT(1000) default '',
+	created_at datetime not null
+);
+`
+
+	CREATE_TBL_SIGNING_KEYS = `
+create table if not exists signing_keys
+(
+	id INTEGER
+		constraint signing_keys_pk
+			primary key autoincrement,
+	kid         TEXT(64) not null,
+	algorithm   TEXT(10) not null,
+	private_key TEXT not null,
+	created_at  datetime not null,
+	retired_at  datetime
+);
+
+create unique index if not exists signing_keys_kid_uindex
+    on signing_keys (kid);
+`
+
+	// migrations - columns added to already existing tables,
+	// "duplicate column name" error means the column is already exist
+	ALTER_TBL_SERVICE_TIMEOUT       = `alter table services add column timeout int default 0;`
+	ALTER_TBL_SERVICE_RETRIES       = `alter table services add column retries int default 0;`
+	ALTER_TBL_SERVICE_RETRY_BACKOFF = `alter table services add column retry_backoff int default 0;`
+	ALTER_TBL_SERVICE_CERT_WARN     = `alter table services add column cert_warn_days int default 0;`
+	ALTER_TBL_SERVICE_CERT_EXPIRES  = `alter table services add column cert_expires_at datetime;`
+	ALTER_TBL_SERVICE_PAUSED        = `alter table services add column paused int default 0;`
+	ALTER_TBL_SERVICE_PUBLIC        = `alter table services add column public int default 0;`
+	ALTER_TBL_SERVICE_ESCALATION    = `alter table services add column escalation_policy_id int default 0;`
+	ALTER_TBL_INCIDENT_ESCALATION   = `alter table incidents add column escalation_level int default 0;`
+	ALTER_TBL_INCIDENT_ESCALATED    = `alter table incidents add column escalated_at datetime;`
+	ALTER_TBL_DELIVERY_DIGEST       = `alter table deliveries add column digest_of int default 0;`
+	ALTER_TBL_USER_OIDC_SUBJECT     = `alter table users add column oidc_subject TEXT(255);`
+	INDEX_USER_OIDC_SUBJECT         = `create unique index if not exists users_oidc_subject_uindex on users (oidc_subject);`
+	ALTER_TBL_USER_LDAP_DN          = `alter table users add column ldap_dn TEXT(500);`
+	ALTER_TBL_USER_PASSWORD_CHANGE  = `alter table users add column password_change_required int default 0;`
 )
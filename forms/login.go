@@ -18,3 +18,83 @@ func (f *LoginForm) Validate() error {
 
 	return nil
 }
+
+type RefreshForm struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (f *RefreshForm) Validate() error {
+	if f.RefreshToken == "" {
+		return fmt.Errorf("Refresh token is required")
+	}
+
+	return nil
+}
+
+type SetupForm struct {
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (f *SetupForm) Validate() error {
+	if f.Token == "" {
+		return fmt.Errorf("Setup token is required")
+	}
+
+	return (&LoginForm{Username: f.Username, Password: f.Password}).Validate()
+}
+
+type TwoFactorLoginForm struct {
+	Token string `json:"token"` // two-factor token of login response
+	Code  string `json:"code"`  // authenticator code or recovery code
+}
+
+func (f *TwoFactorLoginForm) Validate() error {
+	if f.Token == "" {
+		return fmt.Errorf("Token is required")
+	}
+
+	return (&TwoFactorCodeForm{Code: f.Code}).Validate()
+}
+
+type TwoFactorCodeForm struct {
+	Code string `json:"code"`
+}
+
+func (f *TwoFactorCodeForm) Validate() error {
+	if f.Code == "" {
+		return fmt.Errorf("Code is required")
+	}
+
+	return nil
+}
+
+type PasswordChangeForm struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (f *PasswordChangeForm) Validate() error {
+	if f.CurrentPassword == "" {
+		return fmt.Errorf("Current password is required")
+	}
+
+	if f.NewPassword == "" {
+		return fmt.Errorf("New password is required")
+	}
+
+	return nil
+}
+
+type PasswordResetForm struct {
+	Password string `json:"password"` // temporary password, user must change it on next login
+}
+
+func (f *PasswordResetForm) Validate() error {
+	if f.Password == "" {
+		return fmt.Errorf("Password is required")
+	}
+
+	return nil
+}
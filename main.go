@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"database/sql"
 	"flag"
+	"fmt"
 	"github.com/dgraph-io/badger/v2"
 	"os"
 	"os/signal"
@@ -11,11 +14,22 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/grpclog"
 
 	"projectionist/apps"
 	"projectionist/apps/healtchecker"
 	"projectionist/config"
+	"projectionist/db"
+	"projectionist/models"
+	projPB "projectionist/proto"
+	"projectionist/validate"
+)
+
+const (
+	sqlitePath = "./db.sqlite"
+	// createUserCmd - subcommand creating user in database, e.g. the first SuperAdmin of fresh database
+	createUserCmd = "create-user"
 )
 
 func init() {
@@ -32,8 +46,19 @@ func main() {
 		}
 	}()
 
-	var checker bool
+	if len(os.Args) > 1 && os.Args[1] == createUserCmd {
+		err := runCreateUser(os.Args[2:])
+		if err != nil {
+			grpclog.Fatalf("%s error: %v", createUserCmd, err)
+		}
+		return
+	}
+
+	var checker, agent bool
+	var agentName string
 	flag.BoolVar(&checker, "checker", true, "Enable or disable health check")
+	flag.BoolVar(&agent, "agent", false, "Run only remote health check agent, reporting to main server")
+	flag.StringVar(&agentName, "agent-name", "", "Unique name of agent location, hostname by default")
 	flag.Parse()
 
 	grpclog.Infof("Health check mode: %v\n", checker)
@@ -42,13 +67,24 @@ func main() {
 		grpclog.Fatalln(err)
 	}
 
+	models.SetPasswordCost(cfg.Auth.Password.BcryptCost)
+
 	grpclog.Infof("<<<<<Projectionst>>>>>")
 	grpclog.Infof("Configuration:%+v", cfg)
 
 	done := make(chan os.Signal, 1)                                    // for graceful down
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM) // for graceful down
 
-	sqlDB, err := sql.Open("sqlite3", "./db.sqlite")
+	if agent {
+		runAgent(cfg, agentName, done)
+		return
+	}
+
+	if err = cfg.CheckSecurity(); err != nil {
+		grpclog.Fatalln(err)
+	}
+
+	sqlDB, err := sql.Open("sqlite3", sqlitePath)
 	if err != nil {
 		grpclog.Fatalln(err)
 	}
@@ -63,31 +99,146 @@ func main() {
 	syncChan := make(chan string, 300)
 
 	health := healtchecker.NewHealthCkeck(cfg, sqlDB, syncChan)
+
+	// tables are initialized by api, so health checker is started after it
+	restApi, err := apps.NewApp(cfg, sqlDB, badgerDB, syncChan, health)
+	if err != nil {
+		grpclog.Fatalf("projectionist-api: error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	health.RunNotifications(ctx)
+
 	if checker {
 		go func(hc *healtchecker.HealthCheck) {
-			err := health.Run()
+			err := hc.Run(ctx)
 			if err != nil {
 				grpclog.Fatalln(err)
 			}
 		}(health)
 	}
 
-	restApi, err := apps.NewApp(cfg, sqlDB, badgerDB, syncChan)
+	go restApi.Run()
+
+	go apps.RunGRPC(cfg, sqlDB, badgerDB, health, health, syncChan)
+
+	go apps.RunGrpcApi(cfg)
+
+	<-done // graceful down
+
+	cancel()
+	health.Stop()
+
+	grpclog.Infoln("Projectionist is stopped")
+}
+
+// runAgent - run remote health check agent until done signal
+func runAgent(cfg *config.Config, name string, done chan os.Signal) {
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			grpclog.Fatalf("agent name is empty, hostname error: %v", err)
+		}
+		name = hostname
+	}
+
+	dialOption, err := apps.AgentDialOption(cfg)
 	if err != nil {
-		grpclog.Fatalf("projectionist-api: error: %v", err)
+		grpclog.Fatalf("agent tls error: %v", err)
 	}
 
-	go restApi.Run()
+	conn, err := grpc.Dial(cfg.Agents.Server, dialOption)
+	if err != nil {
+		grpclog.Fatalf("dial %s error: %v", cfg.Agents.Server, err)
+	}
+	defer conn.Close()
 
-	go apps.RunGRPC(cfg, sqlDB, badgerDB)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	go apps.RunGrpcApi(cfg)
+	agent := healtchecker.NewAgent(cfg, name, projPB.NewProjectionistServiceClient(conn))
+	go func() {
+		err := agent.Run(ctx)
+		if err != nil {
+			grpclog.Fatalln(err)
+		}
+	}()
 
 	<-done // graceful down
 
-	if checker {
-		health.Stop()
+	cancel()
+	agent.Stop()
+
+	grpclog.Infoln("Projectionist agent is stopped")
+}
+
+// runCreateUser - create user by command line arguments, password is read from stdin if it is not set:
+// projectionist create-user -username admin -role superadmin
+func runCreateUser(args []string) error {
+	var username, password, roleName string
+	flags := flag.NewFlagSet(createUserCmd, flag.ExitOnError)
+	flags.StringVar(&username, "username", "", "Name of new user")
+	flags.StringVar(&password, "password", "", "Password of new user, read from stdin if empty")
+	flags.StringVar(&roleName, "role", models.SuperAdmin.String(), "Role of new user: superadmin, admin or viewer")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
 	}
 
-	grpclog.Infoln("Projectionist is stopped")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		scanner := bufio.NewScanner(os.Stdin)
+		if !scanner.Scan() {
+			return fmt.Errorf("read password: %v", scanner.Err())
+		}
+		password = scanner.Text()
+	}
+
+	var user = &models.User{Username: username, Password: password, Role: models.ParseRole(roleName)}
+	err = user.Validate()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+
+	err = validate.CheckPasswordPolicy(&cfg.Auth.Password, username, password)
+	if err != nil {
+		return err
+	}
+	models.SetPasswordCost(cfg.Auth.Password.BcryptCost)
+
+	sqlDB, err := sql.Open("sqlite3", sqlitePath)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	err = db.InitTables(sqlDB)
+	if err != nil {
+		return err
+	}
+
+	err, exist := user.IsExistByName(sqlDB)
+	if err != nil {
+		return err
+	}
+
+	if exist {
+		return fmt.Errorf("user %s already exists", username)
+	}
+
+	err = user.Save(sqlDB)
+	if err != nil {
+		return err
+	}
+
+	grpclog.Infof("User %s with role %s is created", user.Username, user.Role)
+
+	return nil
 }
@@ -2,41 +2,116 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"database/sql"
 	"fmt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"projectionist/config"
 	"projectionist/consts"
+	"projectionist/models"
 	"projectionist/validate"
 	"strings"
 	"time"
 )
 
-func authorize(ctx context.Context, tokenSecretKey string) error {
+const (
+	servicePrefix     = "/projectionist.ProjectionistService/"
+	loginMethod       = servicePrefix + "Login"
+	loginTwoFAMethod  = servicePrefix + "LoginTwoFactor"
+	refreshMethod     = servicePrefix + "Refresh"
+	logoutMethod      = servicePrefix + "Logout"
+	passwordMethod    = servicePrefix + "ChangePassword"
+	agentMethodPrefix = servicePrefix + "Agent"
+)
+
+type permission struct {
+	resource models.Resource
+	action   models.Action
+}
+
+// methodPermissions - permission required by user method, method without permission is denied
+var methodPermissions = map[string]permission{
+	servicePrefix + "NewUser":             {models.ResourceUser, models.ActionWrite},
+	servicePrefix + "NewMaintenance":      {models.ResourceMaintenance, models.ActionWrite},
+	servicePrefix + "GetMaintenanceList":  {models.ResourceMaintenance, models.ActionRead},
+	servicePrefix + "DeleteMaintenance":   {models.ResourceMaintenance, models.ActionDelete},
+	servicePrefix + "CheckService":        {models.ResourceService, models.ActionWrite},
+	servicePrefix + "PauseService":        {models.ResourceService, models.ActionWrite},
+	servicePrefix + "ResumeService":       {models.ResourceService, models.ActionWrite},
+	servicePrefix + "GetIncidentList":     {models.ResourceIncident, models.ActionRead},
+	servicePrefix + "GetIncident":         {models.ResourceIncident, models.ActionRead},
+	servicePrefix + "AcknowledgeIncident": {models.ResourceIncident, models.ActionWrite},
+	servicePrefix + "AddIncidentNote":     {models.ResourceIncident, models.ActionWrite},
+}
+
+func authHeader(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return fmt.Errorf("retrieving metadata is failed")
+		return "", fmt.Errorf("retrieving metadata is failed")
 	}
 
 	authHeader, ok := md[strings.ToLower(consts.AuthorizationHeader)]
 	if !ok {
-		return fmt.Errorf("authorization token is not supplied")
+		return "", fmt.Errorf("authorization token is not supplied")
 	}
 
 	if len(authHeader) == 0 {
-		return fmt.Errorf("authorization token is empty")
+		return "", fmt.Errorf("authorization token is empty")
+	}
+
+	return authHeader[0], nil
+}
+
+// authorize - context with claims of authorized user of active session
+func authorize(ctx context.Context, db *sql.DB, tokenSecretKey string) (context.Context, error) {
+	token, err := authHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenM, err := validate.Authenticate(db, token, tokenSecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("authorize error: %v", err)
+	}
+
+	return models.ContextWithToken(ctx, tokenM), nil
+}
+
+// permit - check role of authorized user by permission of method
+func permit(ctx context.Context, method string) error {
+	token := models.TokenFromContext(ctx)
+	required, ok := methodPermissions[method]
+	if token == nil || !ok || !token.Can(required.resource, required.action) {
+		return status.Error(codes.PermissionDenied, consts.PermissionDeniedResp)
 	}
 
-	err := validate.ValidateToken(authHeader[0], tokenSecretKey)
+	return nil
+}
+
+// authorizeAgent - check shared agents token in "Bearer <token>" authorization header
+func authorizeAgent(ctx context.Context, agentsToken string) error {
+	if agentsToken == "" {
+		return fmt.Errorf("agents are disabled")
+	}
+
+	token, err := authHeader(ctx)
 	if err != nil {
-		return fmt.Errorf("authorize error: %v", err)
+		return err
+	}
+
+	var splitted = strings.Split(token, " ")
+	if len(splitted) != 2 || subtle.ConstantTimeCompare([]byte(splitted[1]), []byte(agentsToken)) != 1 {
+		return fmt.Errorf("authorize error: invalid agent token")
 	}
 
 	return nil
 }
 
-func ServerInterceptor(cfg *config.Config) grpc.UnaryServerInterceptor {
+func ServerInterceptor(cfg *config.Config, db *sql.DB) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -44,8 +119,29 @@ func ServerInterceptor(cfg *config.Config) grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler) (
 		resp interface{}, err error) {
 		start := time.Now()
-		if info.FullMethod != "/projectionist.ProjectionistService/Login" {
-			err := authorize(ctx, cfg.TokenSecretKey)
+		switch {
+		case info.FullMethod == loginMethod, info.FullMethod == loginTwoFAMethod, info.FullMethod == refreshMethod:
+		case strings.HasPrefix(info.FullMethod, agentMethodPrefix):
+			err := authorizeAgent(ctx, cfg.Agents.Token)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			ctx, err = authorize(ctx, db, cfg.TokenSecretKey)
+			if err != nil {
+				return nil, err
+			}
+
+			if info.FullMethod == logoutMethod || info.FullMethod == passwordMethod {
+				break
+			}
+
+			// user with password reset by admin may only change password and log out
+			if token := models.TokenFromContext(ctx); token != nil && token.PasswordChange {
+				return nil, status.Error(codes.PermissionDenied, consts.PasswordChangeRequiredResp)
+			}
+
+			err = permit(ctx, info.FullMethod)
 			if err != nil {
 				return nil, err
 			}
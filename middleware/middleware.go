@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"database/sql"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gorilla/mux"
@@ -10,13 +13,36 @@ import (
 	"projectionist/consts"
 	"projectionist/models"
 	"projectionist/utils"
+	"projectionist/validate"
 )
 
-var JwtAuthentication = func(tokenSecretKey string) mux.MiddlewareFunc {
+// publicPaths - api paths available without authorization
+var publicPaths = map[string]bool{
+	consts.UrlApiLoginV1:          true,
+	consts.UrlApiLoginTwoFactorV1: true,
+	consts.UrlApiRefreshV1:        true,
+	consts.UrlApiSetupV1:          true,
+	consts.UrlApiOIDCLoginV1:      true,
+	consts.UrlApiOIDCCallbackV1:   true,
+	consts.UrlStatusV1:            true,
+	consts.UrlJWKS:                true,
+}
+
+// passwordChangePaths - api paths available to user which must change password reset by admin
+var passwordChangePaths = map[string]bool{
+	consts.UrlPasswordV1:  true,
+	consts.UrlApiLogoutV1: true,
+}
+
+// JwtAuthentication - accept requests with valid access token of active session or api key,
+// login, two-factor login step, single sign-on, refresh, first-run setup, swagger and status page are public,
+// user with password reset by admin may only change password and log out
+var JwtAuthentication = func(tokenSecretKey string, db *sql.DB) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			var requestPath = r.URL.Path
-			if requestPath == consts.UrlApiLoginV1 || strings.Contains(requestPath, "swagger") {
+			if publicPaths[requestPath] || strings.Contains(requestPath, "swagger") ||
+				strings.HasPrefix(requestPath, consts.UrlStatusSite) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -41,11 +67,29 @@ var JwtAuthentication = func(tokenSecretKey string) mux.MiddlewareFunc {
 			}
 
 			var tokenPart = splitted[1]
+			if models.IsAPIKey(tokenPart) {
+				tokenM, err := validate.AuthenticateAPIKey(db, tokenPart, time.Now())
+				if err != nil {
+					if err != validate.ErrAPIKeyInvalid {
+						log.Printf("JwtAuthentication() validate.AuthenticateAPIKey() error: %v", err)
+					}
+					response = utils.Message(false, consts.APIKeyInvalidResp)
+					w.WriteHeader(http.StatusUnauthorized)
+					utils.JsonRespond(w, response)
+					return
+				}
+
+				if denyPasswordChange(w, tokenM, requestPath) {
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(models.ContextWithToken(r.Context(), tokenM)))
+				return
+			}
+
 			var tokenM = &models.Token{}
 
-			token, err := jwt.ParseWithClaims(tokenPart, tokenM, func(token *jwt.Token) (i interface{}, e error) {
-				return []byte(tokenSecretKey), nil
-			})
+			token, err := jwt.ParseWithClaims(tokenPart, tokenM, validate.TokenKeyfunc(tokenSecretKey))
 			if err != nil {
 				response = utils.Message(false, "Malformed authentication token")
 				w.WriteHeader(http.StatusForbidden)
@@ -60,7 +104,48 @@ var JwtAuthentication = func(tokenSecretKey string) mux.MiddlewareFunc {
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			err = validate.CheckSession(db, tokenM, time.Now())
+			if err != nil {
+				if err != validate.ErrSessionInvalid {
+					log.Printf("JwtAuthentication() validate.CheckSession() error: %v", err)
+				}
+				response = utils.Message(false, consts.SessionInvalidResp)
+				w.WriteHeader(http.StatusUnauthorized)
+				utils.JsonRespond(w, response)
+				return
+			}
+
+			if denyPasswordChange(w, tokenM, requestPath) {
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(models.ContextWithToken(r.Context(), tokenM)))
 		})
 	}
 }
+
+// denyPasswordChange - respond forbidden if user with password reset by admin requests other path
+// than password change or logout, by access token or api key alike
+func denyPasswordChange(w http.ResponseWriter, token *models.Token, requestPath string) bool {
+	if !token.PasswordChange || passwordChangePaths[requestPath] {
+		return false
+	}
+
+	w.WriteHeader(http.StatusForbidden)
+	utils.JsonRespond(w, utils.Message(false, consts.PasswordChangeRequiredResp))
+	return true
+}
+
+// Permit - allow request only if role of authorized user permits action on resource
+func Permit(resource models.Resource, action models.Action, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := models.TokenFromContext(r.Context())
+		if token == nil || !token.Can(resource, action) {
+			w.WriteHeader(http.StatusForbidden)
+			utils.JsonRespond(w, utils.Message(false, consts.PermissionDeniedResp))
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"projectionist/consts"
+	"projectionist/db"
+	"projectionist/models"
+)
+
+func TestPermit(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    *models.Token
+		wantCode int
+	}{
+		{name: "not authorized", wantCode: http.StatusForbidden},
+		{name: "denied", token: &models.Token{UserId: 1, Role: models.Viewer}, wantCode: http.StatusForbidden},
+		{name: "allowed", token: &models.Token{UserId: 1, Role: models.Admin}, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Permit(models.ResourceService, models.ActionDelete, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodDelete, "/v1/api/service/1", nil)
+			if tt.token != nil {
+				request = request.WithContext(models.ContextWithToken(request.Context(), tt.token))
+			}
+
+			recorder := httptest.NewRecorder()
+			handler(recorder, request)
+			if recorder.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", recorder.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestPermitMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		role    models.Role
+		wantErr bool
+	}{
+		{name: "viewer reads incidents", method: servicePrefix + "GetIncidentList", role: models.Viewer},
+		{name: "viewer pauses service", method: servicePrefix + "PauseService", role: models.Viewer, wantErr: true},
+		{name: "admin creates user", method: servicePrefix + "NewUser", role: models.Admin, wantErr: true},
+		{name: "super admin creates user", method: servicePrefix + "NewUser", role: models.SuperAdmin},
+		{name: "unknown method", method: servicePrefix + "Unknown", role: models.SuperAdmin, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := models.ContextWithToken(context.Background(), &models.Token{UserId: 1, Role: tt.role})
+			err := permit(ctx, tt.method)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("permit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("permit() code = %v, want PermissionDenied", status.Code(err))
+			}
+		})
+	}
+}
+
+func TestJwtAuthentication_APIKeyPasswordChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "middleware")
+	if err != nil {
+		t.Fatalf("temp dir error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(dir, "test.sqlite"))
+	if err != nil {
+		t.Fatalf("open db error: %v", err)
+	}
+	defer sqlDB.Close()
+
+	if err = db.InitTables(sqlDB); err != nil {
+		t.Fatalf("init tables error: %v", err)
+	}
+
+	var user = &models.User{Username: "ci", Password: "password", Role: models.Admin}
+	if err = user.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+	// password is reset by admin
+	if err = user.SetPassword(sqlDB, "temporary", true); err != nil {
+		t.Fatalf("SetPassword() error: %v", err)
+	}
+
+	key, err := models.NewAPIKeyValue()
+	if err != nil {
+		t.Fatalf("NewAPIKeyValue() error: %v", err)
+	}
+	var apiKey = &models.APIKey{
+		UserID:    user.ID,
+		Name:      "ci",
+		Scopes:    []models.Scope{{Resource: models.ResourceService, Action: models.ActionRead}},
+		CreatedAt: time.Now(),
+	}
+	apiKey.SetKey(key)
+	if err = apiKey.Save(sqlDB); err != nil {
+		t.Fatalf("api key Save() error: %v", err)
+	}
+
+	handler := JwtAuthentication("secret", sqlDB)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{path: consts.UrlServiceV1 + "/1", wantCode: http.StatusForbidden},
+		{path: consts.UrlPasswordV1, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		request.Header.Set(consts.AuthorizationHeader, "Bearer "+key)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != tt.wantCode {
+			t.Errorf("%s code = %d, want %d", tt.path, recorder.Code, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix - prefix of api keys, distinguishes them from access tokens in authorization header
+const APIKeyPrefix = "prj_"
+
+// apiKeyShownLen - length of key beginning stored in clear to recognize key in list
+const apiKeyShownLen = len(APIKeyPrefix) + 8
+
+// Scope - action on resource granted to api key, it is allowed only if role of key owner allows it too
+type Scope struct {
+	Resource Resource
+	Action   Action
+}
+
+func (s Scope) String() string {
+	return string(s.Resource) + ":" + string(s.Action)
+}
+
+// ParseScope - scope by "resource:action" name
+func ParseScope(name string) (Scope, error) {
+	var parts = strings.Split(name, ":")
+	if len(parts) != 2 {
+		return Scope{}, fmt.Errorf("invalid scope: %s", name)
+	}
+
+	var scope = Scope{Resource: Resource(parts[0]), Action: Action(parts[1])}
+	if !scope.Resource.IsValid() || !scope.Action.IsValid() {
+		return Scope{}, fmt.Errorf("unknown scope: %s", name)
+	}
+
+	return scope, nil
+}
+
+func (s Scope) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Scope) UnmarshalText(text []byte) error {
+	scope, err := ParseScope(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = scope
+	return nil
+}
+
+// APIKey - personal key of user for automation, key itself is shown once at creation and stored hashed
+type APIKey struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"` // beginning of key
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []Scope    `json:"scopes" db:"scopes"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"` // nil - key does not expire
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+const apiKeyFields = "id, user_id, name, prefix, key_hash, scopes, created_at, expires_at, last_used_at, revoked_at"
+
+// NewAPIKeyValue - random api key
+func NewAPIKeyValue() (string, error) {
+	var buf = make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return APIKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// IsAPIKey - true if authorization token is api key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// SetKey - store hash and prefix of key
+func (k *APIKey) SetKey(key string) {
+	k.KeyHash = HashToken(key)
+	k.Prefix = key
+	if len(key) > apiKeyShownLen {
+		k.Prefix = key[:apiKeyShownLen]
+	}
+}
+
+func (k *APIKey) Validate() error {
+	if k.Name == "" || len(k.Name) > 255 {
+		return fmt.Errorf("invalid name")
+	}
+
+	if len(k.Scopes) == 0 {
+		return fmt.Errorf("scopes are empty")
+	}
+
+	for _, scope := range k.Scopes {
+		if !scope.Resource.IsValid() || !scope.Action.IsValid() {
+			return fmt.Errorf("unknown scope: %s", scope)
+		}
+	}
+
+	return nil
+}
+
+// IsActive - key is not revoked and not expired at now
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+func (k *APIKey) Save(db *sql.DB) error {
+	var expiresAt *time.Time
+	if k.ExpiresAt != nil {
+		utc := k.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	result, err := db.Exec(
+		"INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, created_at, expires_at) VALUES (?,?,?,?,?,?,?)",
+		k.UserID,
+		k.Name,
+		k.Prefix,
+		k.KeyHash,
+		joinScopes(k.Scopes),
+		k.CreatedAt.UTC(),
+		expiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	k.ID = int(lastInsertID)
+
+	return nil
+}
+
+// Revoke - key is not accepted anymore
+func (k *APIKey) Revoke(db *sql.DB, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec("UPDATE api_keys SET revoked_at=? WHERE id=? AND revoked_at IS NULL", at, k.ID)
+	if err != nil {
+		return err
+	}
+
+	if k.RevokedAt == nil {
+		k.RevokedAt = &at
+	}
+
+	return nil
+}
+
+// MarkUsed - update last used time of key
+func (k *APIKey) MarkUsed(db *sql.DB, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec("UPDATE api_keys SET last_used_at=? WHERE id=?", at, k.ID)
+	if err != nil {
+		return err
+	}
+
+	k.LastUsedAt = &at
+
+	return nil
+}
+
+func (k *APIKey) scan(row scanner) error {
+	var scopes string
+	err := row.Scan(
+		&k.ID,
+		&k.UserID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		&scopes,
+		&k.CreatedAt,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.RevokedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	k.Scopes, err = splitScopes(scopes)
+	return err
+}
+
+// GetAPIKey - api key by id
+func GetAPIKey(db *sql.DB, id int) (*APIKey, error) {
+	var key = &APIKey{}
+	err := key.scan(db.QueryRow("SELECT "+apiKeyFields+" FROM api_keys WHERE id=?", id))
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByKey - api key by its value
+func GetAPIKeyByKey(db *sql.DB, key string) (*APIKey, error) {
+	var apiKey = &APIKey{}
+	err := apiKey.scan(db.QueryRow("SELECT "+apiKeyFields+" FROM api_keys WHERE key_hash=?", HashToken(key)))
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+// GetUserAPIKeys - api keys of user, newest first
+func GetUserAPIKeys(db *sql.DB, userID int) ([]*APIKey, error) {
+	rows, err := db.Query("SELECT "+apiKeyFields+" FROM api_keys WHERE user_id=? ORDER BY id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys = []*APIKey{}
+	for rows.Next() {
+		var key = &APIKey{}
+		if err = key.scan(rows); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func joinScopes(scopes []Scope) string {
+	var names = make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		names = append(names, scope.String())
+	}
+
+	return strings.Join(names, ",")
+}
+
+func splitScopes(names string) ([]Scope, error) {
+	var scopes = []Scope{}
+	if names == "" {
+		return scopes, nil
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		scope, err := ParseScope(name)
+		if err != nil {
+			return nil, err
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
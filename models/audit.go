@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AuditEvent - kind of security relevant event
+type AuditEvent string
+
+const (
+	AuditLoginFailed    AuditEvent = "login_failed"
+	AuditLoginLocked    AuditEvent = "login_locked" // failures of username or address reached lockout limit
+	AuditLoginUnlocked  AuditEvent = "login_unlocked"
+	AuditTwoFactorReset AuditEvent = "two_factor_reset"
+	// AuditUserProvisioned - user created on first single sign-on login
+	AuditUserProvisioned AuditEvent = "user_provisioned"
+	AuditPasswordChanged AuditEvent = "password_changed"
+	AuditPasswordReset   AuditEvent = "password_reset" // user must change password set by admin
+)
+
+// AuditEntry - record of audit log
+type AuditEntry struct {
+	ID        int        `json:"id" db:"id"`
+	Event     AuditEvent `json:"event" db:"event"`
+	Username  string     `json:"username,omitempty" db:"username"`
+	IP        string     `json:"ip,omitempty" db:"ip"`
+	UserID    int        `json:"user_id,omitempty" db:"user_id"` // user made action, e.g. admin unlocked account
+	Details   string     `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+const auditFields = "id, event, username, ip, user_id, details, created_at"
+
+func (e *AuditEntry) Save(db *sql.DB) error {
+	var details = e.Details
+	if len(details) > 1000 {
+		details = details[:1000]
+	}
+
+	result, err := db.Exec(
+		"INSERT INTO audit_log (event, username, ip, user_id, details, created_at) VALUES (?,?,?,?,?,?)",
+		e.Event, e.Username, e.IP, e.UserID, details, e.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	e.ID = int(lastInsertID)
+
+	return nil
+}
+
+// GetAuditEntries - audit log, newest first
+func GetAuditEntries(db *sql.DB, offset, limit int) ([]*AuditEntry, error) {
+	rows, err := db.Query("SELECT "+auditFields+" FROM audit_log ORDER BY id DESC LIMIT ?, ?", offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries = []*AuditEntry{}
+	for rows.Next() {
+		var entry = &AuditEntry{}
+		err = rows.Scan(
+			&entry.ID,
+			&entry.Event,
+			&entry.Username,
+			&entry.IP,
+			&entry.UserID,
+			&entry.Details,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CountAuditEntries - count of audit log records
+func CountAuditEntries(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT count(id) FROM audit_log").Scan(&count)
+	return count, err
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+)
+
+// ChannelType - kind of notification channel
+type ChannelType string
+
+const (
+	// ChannelWebhook - generic http webhook, request body is signed by HMAC-SHA256 with channel secret
+	ChannelWebhook ChannelType = "webhook"
+	// ChannelSlack - Slack-compatible incoming webhook
+	ChannelSlack ChannelType = "slack"
+	// ChannelTelegram - Telegram-compatible bot api, secret is bot token
+	ChannelTelegram ChannelType = "telegram"
+)
+
+// IsValid - true if channel type is known
+func (t ChannelType) IsValid() bool {
+	switch t {
+	case ChannelWebhook, ChannelSlack, ChannelTelegram:
+		return true
+	}
+
+	return false
+}
+
+// Channel - notification channel of service, notified alongside service emails
+type Channel struct {
+	ID        int         `json:"id" db:"id"`
+	ServiceID int         `json:"service_id" db:"service_id"`
+	Type      ChannelType `json:"type" db:"type"`
+	URL       string      `json:"url" db:"url"`                 // webhook url or bot api base url
+	Secret    string      `json:"secret,omitempty" db:"secret"` // webhook signing key or bot token, write-only
+	ChatID    string      `json:"chat_id,omitempty" db:"chat_id"`
+}
+
+func (c *Channel) Validate() error {
+	if !c.Type.IsValid() {
+		return fmt.Errorf("invalid channel type: %s", c.Type)
+	}
+
+	link, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid %s channel url: %v", c.Type, err)
+	}
+
+	if (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+		return fmt.Errorf("invalid %s channel url: %s", c.Type, c.URL)
+	}
+
+	if c.Type == ChannelTelegram && (c.Secret == "" || c.ChatID == "") {
+		return fmt.Errorf("telegram channel requires bot token and chat id")
+	}
+
+	return nil
+}
+
+func (c *Channel) Save(db *sql.DB) error {
+	result, err := db.Exec(
+		"INSERT INTO channels (service_id, type, url, secret, chat_id) VALUES (?,?,?,?,?)",
+		c.ServiceID,
+		c.Type,
+		c.URL,
+		c.Secret,
+		c.ChatID,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	c.ID = int(lastInsertID)
+
+	return nil
+}
+
+// GetChannels - notification channels of service
+func (s *Service) GetChannels(db *sql.DB) ([]Channel, error) {
+	rows, err := db.Query("SELECT id, service_id, type, url, secret, chat_id FROM channels WHERE service_id=?", s.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels = []Channel{}
+	for rows.Next() {
+		var channel = Channel{}
+		err = rows.Scan(
+			&channel.ID,
+			&channel.ServiceID,
+			&channel.Type,
+			&channel.URL,
+			&channel.Secret,
+			&channel.ChatID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		channels = append(channels, channel)
+	}
+
+	return channels, rows.Err()
+}
+
+// HideChannelSecrets - clear channel secrets before service is sent to client, secrets are write-only
+func (s *Service) HideChannelSecrets() {
+	for i := range s.Channels {
+		s.Channels[i].Secret = ""
+	}
+}
+
+// saveChannels - replace notification channels of service
+func (s *Service) saveChannels(db *sql.DB, serviceID int) error {
+	_, err := db.Exec("DELETE FROM channels WHERE service_id=?", serviceID)
+	if err != nil {
+		return err
+	}
+
+	for i := range s.Channels {
+		s.Channels[i].ServiceID = serviceID
+		err = s.Channels[i].Save(db)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
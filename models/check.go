@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+type CheckStatus int
+
+const (
+	_ CheckStatus = iota
+	CheckSuccess
+	CheckFailure
+	CheckTimeout
+)
+
+func (c CheckStatus) String() string {
+	switch c {
+	case CheckSuccess:
+		return "success"
+	case CheckFailure:
+		return "failure"
+	case CheckTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// CheckResult - result of one service health check
+type CheckResult struct {
+	ServiceID int           `json:"service_id"`
+	Status    CheckStatus   `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Attempts  int           `json:"attempts"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checked_at"`
+	// CertExpiresAt - earliest expiry in peer certificate chain, nil for not https links
+	CertExpiresAt *time.Time `json:"cert_expires_at,omitempty"`
+}
+
+// Success - true if service responded with success status
+func (r *CheckResult) Success() bool {
+	return r.Status == CheckSuccess
+}
+
+// CheckerMetrics - state of health checker probe worker pool
+type CheckerMetrics struct {
+	Checked    int64 `json:"checked"` // count of finished planned checks
+	Skipped    int64 `json:"skipped"` // planned checks skipped because previous check of service still queued or running
+	Dropped    int64 `json:"dropped"` // planned checks dropped because queue is full
+	Running    int64 `json:"running"`
+	Workers    int   `json:"workers"`
+	QueueDepth int   `json:"queue_depth"`
+	QueueSize  int   `json:"queue_size"`
+	Planned    int   `json:"planned"` // count of planned services
+}
@@ -0,0 +1,384 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeliveryStatus - state of email in outbound notification queue
+type DeliveryStatus int
+
+const (
+	_ DeliveryStatus = iota
+	DeliveryPending
+	DeliverySent
+	DeliveryFailed // attempts are exhausted or rejected permanently
+)
+
+func (s DeliveryStatus) String() string {
+	switch s {
+	case DeliveryPending:
+		return "pending"
+	case DeliverySent:
+		return "sent"
+	case DeliveryFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseDeliveryStatus - status by name, 0 if name is unknown
+func ParseDeliveryStatus(name string) DeliveryStatus {
+	for _, status := range []DeliveryStatus{DeliveryPending, DeliverySent, DeliveryFailed} {
+		if strings.EqualFold(status.String(), name) {
+			return status
+		}
+	}
+
+	return 0
+}
+
+// Delivery - email notification of one recipient in outbound queue
+type Delivery struct {
+	ID            int            `json:"id" db:"id"`
+	MessageID     string         `json:"message_id" db:"message_id"` // kept between attempts
+	Recipient     string         `json:"recipient" db:"recipient"`
+	Subject       string         `json:"subject" db:"subject"`
+	Body          string         `json:"-" db:"body"`
+	Status        DeliveryStatus `json:"status" db:"status"`
+	Attempts      int            `json:"attempts" db:"attempts"`
+	LastError     string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	NextAttemptAt time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+	SentAt        *time.Time     `json:"sent_at,omitempty" db:"sent_at"`
+	DigestOf      int            `json:"digest_of,omitempty" db:"digest_of"` // id of delivery sent as digest with this one
+}
+
+// DeliveryFilter - filter of delivery list, zero status means any
+type DeliveryFilter struct {
+	Status DeliveryStatus
+}
+
+// deliveryFields - columns of deliveries table in scan order, see Delivery.scan
+const deliveryFields = "id, message_id, recipient, subject, body, status, attempts, last_error, created_at, " +
+	"next_attempt_at, sent_at, digest_of"
+
+func (d *Delivery) Validate() error {
+	if !RegexEmail.MatchString(d.Recipient) {
+		return fmt.Errorf("is not email: %s", d.Recipient)
+	}
+
+	return nil
+}
+
+func (d *Delivery) IsExistByName(db *sql.DB) (error, bool) { return nil, false }
+
+func (d *Delivery) Count(db *sql.DB) (int, error) {
+	return CountDeliveries(db, DeliveryFilter{})
+}
+
+func (d *Delivery) Save(db *sql.DB) error {
+	if d.Status == 0 {
+		d.Status = DeliveryPending
+	}
+
+	result, err := db.Exec(
+		"INSERT INTO deliveries (message_id, recipient, subject, body, status, attempts, last_error, created_at, "+
+			"next_attempt_at) VALUES (?,?,?,?,?,?,?,?,?)",
+		d.MessageID,
+		d.Recipient,
+		d.Subject,
+		d.Body,
+		d.Status,
+		d.Attempts,
+		d.LastError,
+		d.CreatedAt.UTC(),
+		d.NextAttemptAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	d.ID = int(lastInsertID)
+
+	return nil
+}
+
+func (d *Delivery) GetByName(db *sql.DB, name string) error { return nil }
+
+func (d *Delivery) GetByID(db *sql.DB, id int64) error {
+	return d.scan(db.QueryRow("SELECT "+deliveryFields+" FROM deliveries WHERE id=?", id))
+}
+
+func (d *Delivery) Pagination(db *sql.DB, start int, end int) ([]Model, error) {
+	deliveries, err := GetDeliveries(db, DeliveryFilter{}, start, end-start)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]Model, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, delivery)
+	}
+
+	return result, nil
+}
+
+func (d *Delivery) Update(db *sql.DB, id int) error { return nil }
+func (d *Delivery) Delete(db *sql.DB, id int) error { return nil }
+func (d *Delivery) GetID() int                      { return d.ID }
+func (d *Delivery) SetID(id int)                    { d.ID = id }
+func (d *Delivery) GetName() string                 { return d.MessageID }
+func (d *Delivery) SetName(name string)             {}
+func (d *Delivery) SetDeleted()                     {}
+func (d *Delivery) IsDeleted() bool                 { return false }
+
+// MarkSent - delivery is accepted by smtp server
+func (d *Delivery) MarkSent(db *sql.DB, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec(
+		"UPDATE deliveries SET status=?, attempts=attempts+1, last_error='', sent_at=? WHERE id=?",
+		DeliverySent, at, d.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	d.Status = DeliverySent
+	d.Attempts++
+	d.LastError = ""
+	d.SentAt = &at
+
+	return nil
+}
+
+// MarkDigested - delivery is sent inside digest of delivery digestID
+func (d *Delivery) MarkDigested(db *sql.DB, digestID int, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec(
+		"UPDATE deliveries SET status=?, attempts=attempts+1, last_error='', sent_at=?, digest_of=? WHERE id=?",
+		DeliverySent, at, digestID, d.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	d.Status = DeliverySent
+	d.Attempts++
+	d.LastError = ""
+	d.SentAt = &at
+	d.DigestOf = digestID
+
+	return nil
+}
+
+// Postpone - move next attempt without counting it as attempt
+func (d *Delivery) Postpone(db *sql.DB, nextAttemptAt time.Time) error {
+	nextAttemptAt = nextAttemptAt.UTC()
+	_, err := db.Exec("UPDATE deliveries SET next_attempt_at=? WHERE id=?", nextAttemptAt, d.ID)
+	if err != nil {
+		return err
+	}
+
+	d.NextAttemptAt = nextAttemptAt
+
+	return nil
+}
+
+// MarkRetry - delivery attempt failed, next attempt is planned at nextAttemptAt
+func (d *Delivery) MarkRetry(db *sql.DB, deliveryErr error, nextAttemptAt time.Time) error {
+	return d.markAttempt(db, DeliveryPending, deliveryErr, nextAttemptAt)
+}
+
+// MarkFailed - delivery attempt failed and delivery is not retried anymore
+func (d *Delivery) MarkFailed(db *sql.DB, deliveryErr error) error {
+	return d.markAttempt(db, DeliveryFailed, deliveryErr, d.NextAttemptAt)
+}
+
+func (d *Delivery) markAttempt(db *sql.DB, status DeliveryStatus, deliveryErr error, nextAttemptAt time.Time) error {
+	var lastError = deliveryErr.Error()
+	if len(lastError) > 1000 {
+		lastError = lastError[:1000]
+	}
+
+	nextAttemptAt = nextAttemptAt.UTC()
+	_, err := db.Exec(
+		"UPDATE deliveries SET status=?, attempts=attempts+1, last_error=?, next_attempt_at=? WHERE id=?",
+		status, lastError, nextAttemptAt, d.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	d.Status = status
+	d.Attempts++
+	d.LastError = lastError
+	d.NextAttemptAt = nextAttemptAt
+
+	return nil
+}
+
+func (d *Delivery) scan(row scanner) error {
+	return row.Scan(
+		&d.ID,
+		&d.MessageID,
+		&d.Recipient,
+		&d.Subject,
+		&d.Body,
+		&d.Status,
+		&d.Attempts,
+		&d.LastError,
+		&d.CreatedAt,
+		&d.NextAttemptAt,
+		&d.SentAt,
+		&d.DigestOf,
+	)
+}
+
+// GetDueDeliveries - pending deliveries with passed next attempt time, oldest first
+func GetDueDeliveries(db *sql.DB, now time.Time, limit int) ([]*Delivery, error) {
+	return queryDeliveries(
+		db,
+		"SELECT "+deliveryFields+" FROM deliveries WHERE status=? AND next_attempt_at<=? ORDER BY next_attempt_at, id LIMIT ?",
+		DeliveryPending, now.UTC(), limit,
+	)
+}
+
+// NextDigestAttempt - time of pending not attempted delivery to recipient, nil if recipient has no such delivery,
+// new digest delivery joins it
+func NextDigestAttempt(db *sql.DB, recipient string) (*time.Time, error) {
+	var next nullTime
+	err := db.QueryRow(
+		"SELECT MIN(next_attempt_at) FROM deliveries WHERE recipient=? AND status=? AND attempts=0",
+		recipient, DeliveryPending,
+	).Scan(&next)
+	if err != nil {
+		return nil, err
+	}
+
+	return next.ptr(), nil
+}
+
+// CountSentDeliveries - count of emails sent to recipient since time and time of the oldest of them,
+// digest is counted as one email
+func CountSentDeliveries(db *sql.DB, recipient string, since time.Time) (int, *time.Time, error) {
+	var count int
+	var oldest nullTime
+	err := db.QueryRow(
+		"SELECT count(id), MIN(sent_at) FROM deliveries WHERE recipient=? AND status=? AND digest_of=0 AND sent_at>=?",
+		recipient, DeliverySent, since.UTC(),
+	).Scan(&count, &oldest)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return count, oldest.ptr(), nil
+}
+
+// GetDeliveries - deliveries by filter, newest first
+func GetDeliveries(db *sql.DB, filter DeliveryFilter, offset, limit int) ([]*Delivery, error) {
+	where, args := filter.where()
+	args = append(args, offset, limit)
+
+	return queryDeliveries(db, "SELECT "+deliveryFields+" FROM deliveries"+where+" ORDER BY id DESC LIMIT ?, ?", args...)
+}
+
+// CountDeliveries - count of deliveries by filter
+func CountDeliveries(db *sql.DB, filter DeliveryFilter) (int, error) {
+	where, args := filter.where()
+
+	var count int
+	err := db.QueryRow("SELECT count(id) FROM deliveries"+where, args...).Scan(&count)
+
+	return count, err
+}
+
+func (f DeliveryFilter) where() (string, []interface{}) {
+	if f.Status == 0 {
+		return "", nil
+	}
+
+	return " WHERE status=?", []interface{}{f.Status}
+}
+
+func queryDeliveries(db *sql.DB, query string, args ...interface{}) ([]*Delivery, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*Delivery
+	for rows.Next() {
+		var delivery = &Delivery{}
+		err = delivery.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// timestampFormats - layouts of datetime columns returned by sqlite as text, e.g. by aggregate functions
+var timestampFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// nullTime - nullable datetime scanned from time or text value
+type nullTime struct {
+	time  time.Time
+	valid bool
+}
+
+func (t *nullTime) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		t.valid = false
+		return nil
+	case time.Time:
+		t.time, t.valid = v, true
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("unsupported datetime value %T", value)
+	}
+
+	text = strings.TrimSuffix(text, "Z")
+	for _, format := range timestampFormats {
+		parsed, err := time.ParseInLocation(format, text, time.UTC)
+		if err == nil {
+			t.time, t.valid = parsed, true
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid datetime: %s", text)
+}
+
+func (t nullTime) ptr() *time.Time {
+	if !t.valid {
+		return nil
+	}
+
+	return &t.time
+}
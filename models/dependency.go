@@ -0,0 +1,201 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ServiceNode - service in dependency graph
+type ServiceNode struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+}
+
+// ServiceEdge - dependency of service ServiceID on service DependsOn
+type ServiceEdge struct {
+	ServiceID int `json:"service_id"`
+	DependsOn int `json:"depends_on"`
+}
+
+// ServiceGraph - not deleted services with dependencies between them
+type ServiceGraph struct {
+	Nodes []ServiceNode `json:"nodes"`
+	Edges []ServiceEdge `json:"edges"`
+}
+
+// GetDependencies - ids of services on which service depends
+func (s *Service) GetDependencies(db *sql.DB) ([]int, error) {
+	s.Dependencies = []int{}
+	rows, err := db.Query("SELECT depends_on FROM service_dependencies WHERE service_id=? ORDER BY depends_on", s.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dependencyID int
+		err = rows.Scan(&dependencyID)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Dependencies = append(s.Dependencies, dependencyID)
+	}
+
+	return s.Dependencies, rows.Err()
+}
+
+// IsDependencyDown - true if one of service dependencies is dead or degraded
+func (s *Service) IsDependencyDown(db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRow(`
+SELECT count(s.id) FROM service_dependencies d
+JOIN services s ON s.id = d.depends_on
+WHERE d.service_id=? AND s.deleted=0 AND s.status IN (?,?)`,
+		s.ID, Dead, Degraded,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// saveDependencies - replace dependencies of service with id
+func (s *Service) saveDependencies(db *sql.DB, id int) error {
+	_, err := db.Exec("DELETE FROM service_dependencies WHERE service_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	for _, dependencyID := range s.Dependencies {
+		_, err = db.Exec("INSERT INTO service_dependencies (service_id, depends_on) VALUES (?,?)", id, dependencyID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkDependencies - dependencies of service with id must exist and must not make a cycle
+func checkDependencies(db *sql.DB, id int, dependencies []int) error {
+	for _, dependencyID := range dependencies {
+		if dependencyID == id {
+			return fmt.Errorf("service %d can not depend on itself", id)
+		}
+
+		var exist int
+		err := db.QueryRow("SELECT count(id) FROM services WHERE id=? AND deleted=0", dependencyID).Scan(&exist)
+		if err != nil {
+			return err
+		}
+
+		if exist == 0 {
+			return fmt.Errorf("dependency service %d not exist", dependencyID)
+		}
+	}
+
+	// new service has no dependents, so it can not be a part of cycle
+	if id == 0 {
+		return nil
+	}
+
+	edges, err := getServiceEdges(db)
+	if err != nil {
+		return err
+	}
+
+	var graph = make(map[int][]int)
+	for _, edge := range edges {
+		if edge.ServiceID == id {
+			continue
+		}
+		graph[edge.ServiceID] = append(graph[edge.ServiceID], edge.DependsOn)
+	}
+	graph[id] = dependencies
+
+	var visited = make(map[int]bool)
+	var stack = append([]int{}, dependencies...)
+	for len(stack) > 0 {
+		var current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == id {
+			return fmt.Errorf("dependencies of service %d make a cycle", id)
+		}
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		stack = append(stack, graph[current]...)
+	}
+
+	return nil
+}
+
+// GetServiceGraph - dependency graph of not deleted services with current statuses
+func GetServiceGraph(db *sql.DB) (*ServiceGraph, error) {
+	var graph = &ServiceGraph{
+		Nodes: []ServiceNode{},
+		Edges: []ServiceEdge{},
+	}
+
+	rows, err := db.Query("SELECT id, name, status FROM services WHERE deleted=0 ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exist = make(map[int]bool)
+	for rows.Next() {
+		var node ServiceNode
+		err = rows.Scan(&node.ID, &node.Name, &node.Status)
+		if err != nil {
+			return nil, err
+		}
+
+		exist[node.ID] = true
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	edges, err := getServiceEdges(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edge := range edges {
+		if exist[edge.ServiceID] && exist[edge.DependsOn] {
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	return graph, nil
+}
+
+func getServiceEdges(db *sql.DB) ([]ServiceEdge, error) {
+	var edges []ServiceEdge
+	rows, err := db.Query("SELECT service_id, depends_on FROM service_dependencies ORDER BY service_id, depends_on")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edge ServiceEdge
+		err = rows.Scan(&edge.ServiceID, &edge.DependsOn)
+		if err != nil {
+			return nil, err
+		}
+
+		edges = append(edges, edge)
+	}
+
+	return edges, rows.Err()
+}
@@ -0,0 +1,349 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MinEscalationRepeat - min interval in seconds between repeated notifications of last escalation level
+const MinEscalationRepeat = 60
+
+// EscalationPolicy - ordered notification levels of unacknowledged incident
+type EscalationPolicy struct {
+	ID     int    `json:"id" db:"id"`
+	Name   string `json:"name" db:"name"`
+	Repeat int    `json:"repeat" db:"repeat_interval"` // seconds between repeats of last level until acknowledged, 0 - no repeat
+	// Levels - ordered by delay, level is notified when incident is unacknowledged longer than level delay
+	Levels []EscalationLevel `json:"levels"`
+}
+
+// EscalationLevel - recipients notified after delay from incident start
+type EscalationLevel struct {
+	Delay  int      `json:"delay"` // seconds from incident start
+	Emails []string `json:"emails"`
+}
+
+func (p *EscalationPolicy) Validate() error {
+	if p.Name == "" || len(p.Name) > 255 {
+		return fmt.Errorf("invalid escalation policy name")
+	}
+
+	if p.Repeat != 0 && p.Repeat < MinEscalationRepeat {
+		return fmt.Errorf("repeat interval must be 0 or at least %d seconds", MinEscalationRepeat)
+	}
+
+	if len(p.Levels) == 0 {
+		return fmt.Errorf("escalation policy without levels")
+	}
+
+	for i, level := range p.Levels {
+		if level.Delay < 0 {
+			return fmt.Errorf("level %d delay must be not negative", i+1)
+		}
+
+		if len(level.Emails) == 0 {
+			return fmt.Errorf("level %d without emails", i+1)
+		}
+
+		for _, email := range level.Emails {
+			if !RegexEmail.MatchString(email) || strings.Contains(email, ",") {
+				return fmt.Errorf("level %d: is not email: %s", i+1, email)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Escalate - levels of policy to notify about incident at now: levels with passed delay which are not notified yet,
+// or the last level again when repeat interval is passed since last notification
+func (p *EscalationPolicy) Escalate(incident *Incident, now time.Time) []EscalationLevel {
+	var levels []EscalationLevel
+	var elapsed = now.Sub(incident.StartedAt)
+	for i := incident.EscalationLevel; i < len(p.Levels); i++ {
+		if elapsed < time.Duration(p.Levels[i].Delay)*time.Second {
+			break
+		}
+		levels = append(levels, p.Levels[i])
+	}
+
+	if len(levels) > 0 || p.Repeat <= 0 || incident.EscalationLevel < len(p.Levels) || incident.EscalatedAt == nil {
+		return levels
+	}
+
+	if now.Sub(*incident.EscalatedAt) >= time.Duration(p.Repeat)*time.Second {
+		levels = append(levels, p.Levels[len(p.Levels)-1])
+	}
+
+	return levels
+}
+
+func (p *EscalationPolicy) IsExistByName(db *sql.DB) (error, bool) {
+	var id int
+	err := db.QueryRow("SELECT id FROM escalation_policies WHERE name=?", p.Name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, false
+	}
+
+	if err != nil {
+		return err, false
+	}
+
+	return nil, true
+}
+
+func (p *EscalationPolicy) Count(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT count(id) FROM escalation_policies").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *EscalationPolicy) Save(db *sql.DB) error {
+	result, err := db.Exec(
+		"INSERT INTO escalation_policies (name, repeat_interval) VALUES (?,?)",
+		p.Name,
+		p.Repeat,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	p.ID = int(lastInsertID)
+
+	return p.saveLevels(db, p.ID)
+}
+
+func (p *EscalationPolicy) GetByName(db *sql.DB, name string) error {
+	err := db.QueryRow("SELECT id, name, repeat_interval FROM escalation_policies WHERE name=?", name).
+		Scan(&p.ID, &p.Name, &p.Repeat)
+	if err != nil {
+		return err
+	}
+
+	p.Levels, err = p.getLevels(db)
+
+	return err
+}
+
+func (p *EscalationPolicy) GetByID(db *sql.DB, id int64) error {
+	err := db.QueryRow("SELECT id, name, repeat_interval FROM escalation_policies WHERE id=?", id).
+		Scan(&p.ID, &p.Name, &p.Repeat)
+	if err != nil {
+		return err
+	}
+
+	p.Levels, err = p.getLevels(db)
+
+	return err
+}
+
+func (p *EscalationPolicy) Pagination(db *sql.DB, start int, end int) ([]Model, error) {
+	rows, err := db.Query(
+		"SELECT id, name, repeat_interval FROM escalation_policies ORDER BY id ASC LIMIT ?, ?",
+		start, end-start,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*EscalationPolicy
+	for rows.Next() {
+		var policy = &EscalationPolicy{}
+		err = rows.Scan(&policy.ID, &policy.Name, &policy.Repeat)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, policy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var result = make([]Model, 0, len(policies))
+	for _, policy := range policies {
+		policy.Levels, err = policy.getLevels(db)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, policy)
+	}
+
+	return result, nil
+}
+
+// Update - replace name, repeat interval and levels of policy
+func (p *EscalationPolicy) Update(db *sql.DB, id int) error {
+	res, err := db.Exec(
+		"UPDATE escalation_policies SET name=?, repeat_interval=? WHERE id=?",
+		p.Name,
+		p.Repeat,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return p.saveLevels(db, id)
+}
+
+// Delete - delete policy and detach it from services
+func (p *EscalationPolicy) Delete(db *sql.DB, id int) error {
+	res, err := db.Exec("DELETE FROM escalation_policies WHERE id=?", id)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = db.Exec("DELETE FROM escalation_levels WHERE policy_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("UPDATE services SET escalation_policy_id=0 WHERE escalation_policy_id=?", id)
+
+	return err
+}
+
+func (p *EscalationPolicy) GetID() int          { return p.ID }
+func (p *EscalationPolicy) SetID(id int)        { p.ID = id }
+func (p *EscalationPolicy) GetName() string     { return p.Name }
+func (p *EscalationPolicy) SetName(name string) { p.Name = name }
+func (p *EscalationPolicy) SetDeleted()         {}
+func (p *EscalationPolicy) IsDeleted() bool     { return false }
+
+// saveLevels - replace levels of policy, levels are ordered by delay
+func (p *EscalationPolicy) saveLevels(db *sql.DB, policyID int) error {
+	sort.SliceStable(p.Levels, func(i, j int) bool {
+		return p.Levels[i].Delay < p.Levels[j].Delay
+	})
+
+	_, err := db.Exec("DELETE FROM escalation_levels WHERE policy_id=?", policyID)
+	if err != nil {
+		return err
+	}
+
+	for i, level := range p.Levels {
+		_, err = db.Exec(
+			"INSERT INTO escalation_levels (policy_id, position, delay, emails) VALUES (?,?,?,?)",
+			policyID,
+			i,
+			level.Delay,
+			strings.Join(level.Emails, ","),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *EscalationPolicy) getLevels(db *sql.DB) ([]EscalationLevel, error) {
+	rows, err := db.Query("SELECT delay, emails FROM escalation_levels WHERE policy_id=? ORDER BY position", p.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels = []EscalationLevel{}
+	for rows.Next() {
+		var level = EscalationLevel{}
+		var emails string
+		err = rows.Scan(&level.Delay, &emails)
+		if err != nil {
+			return nil, err
+		}
+
+		level.Emails = strings.Split(emails, ",")
+		levels = append(levels, level)
+	}
+
+	return levels, rows.Err()
+}
+
+// checkEscalationPolicy - policy attached to service must exist, 0 means service without policy
+func checkEscalationPolicy(db *sql.DB, policyID *int) error {
+	if policyID == nil || *policyID == 0 {
+		return nil
+	}
+
+	var id int
+	err := db.QueryRow("SELECT id FROM escalation_policies WHERE id=?", *policyID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("escalation policy %d not exist", *policyID)
+	}
+
+	return err
+}
+
+// SetEscalation - save count of notified levels and time of last escalation notification
+func (i *Incident) SetEscalation(db *sql.DB, level int, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec("UPDATE incidents SET escalation_level=?, escalated_at=? WHERE id=?", level, at, i.ID)
+	if err != nil {
+		return err
+	}
+
+	i.EscalationLevel = level
+	i.EscalatedAt = &at
+
+	return nil
+}
+
+// GetEscalatingIncidents - open not acknowledged incidents of services with escalation policy
+func GetEscalatingIncidents(db *sql.DB) ([]*Incident, error) {
+	rows, err := db.Query(
+		"SELECT " + incidentFields + " FROM incidents WHERE ended_at IS NULL AND acknowledged_at IS NULL AND " +
+			"service_id IN (SELECT id FROM services WHERE deleted=0 AND escalation_policy_id>0) ORDER BY id",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []*Incident
+	for rows.Next() {
+		var incident = &Incident{}
+		err = incident.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEscalationPolicy_Escalate(t *testing.T) {
+	var started = time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	var onCall = EscalationLevel{Delay: 0, Emails: []string{"oncall@example.com"}}
+	var managers = EscalationLevel{Delay: 900, Emails: []string{"managers@example.com"}}
+	var policy = &EscalationPolicy{Name: "default", Repeat: 600, Levels: []EscalationLevel{onCall, managers}}
+	var escalatedAt = started.Add(15 * time.Minute)
+
+	tests := []struct {
+		name     string
+		incident Incident
+		now      time.Time
+		want     []EscalationLevel
+	}{
+		{
+			name:     "first level immediately",
+			incident: Incident{StartedAt: started},
+			now:      started.Add(time.Second),
+			want:     []EscalationLevel{onCall},
+		},
+		{
+			name:     "second level is waiting for delay",
+			incident: Incident{StartedAt: started, EscalationLevel: 1},
+			now:      started.Add(14 * time.Minute),
+		},
+		{
+			name:     "second level after delay",
+			incident: Incident{StartedAt: started, EscalationLevel: 1},
+			now:      started.Add(15 * time.Minute),
+			want:     []EscalationLevel{managers},
+		},
+		{
+			name:     "all passed levels at once",
+			incident: Incident{StartedAt: started},
+			now:      started.Add(time.Hour),
+			want:     []EscalationLevel{onCall, managers},
+		},
+		{
+			name:     "repeat is waiting for interval",
+			incident: Incident{StartedAt: started, EscalationLevel: 2, EscalatedAt: &escalatedAt},
+			now:      escalatedAt.Add(5 * time.Minute),
+		},
+		{
+			name:     "repeat last level",
+			incident: Incident{StartedAt: started, EscalationLevel: 2, EscalatedAt: &escalatedAt},
+			now:      escalatedAt.Add(10 * time.Minute),
+			want:     []EscalationLevel{managers},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Escalate(&tt.incident, tt.now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Escalate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscalationPolicy_Validate(t *testing.T) {
+	var level = EscalationLevel{Emails: []string{"oncall@example.com"}}
+
+	tests := []struct {
+		name    string
+		policy  EscalationPolicy
+		wantErr bool
+	}{
+		{name: "valid", policy: EscalationPolicy{Name: "p", Levels: []EscalationLevel{level}}},
+		{name: "without name", policy: EscalationPolicy{Levels: []EscalationLevel{level}}, wantErr: true},
+		{name: "without levels", policy: EscalationPolicy{Name: "p"}, wantErr: true},
+		{name: "too short repeat", policy: EscalationPolicy{Name: "p", Repeat: 10, Levels: []EscalationLevel{level}}, wantErr: true},
+		{
+			name:    "negative delay",
+			policy:  EscalationPolicy{Name: "p", Levels: []EscalationLevel{{Delay: -1, Emails: level.Emails}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid email",
+			policy:  EscalationPolicy{Name: "p", Levels: []EscalationLevel{{Emails: []string{"oncall"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Incident list states
+const (
+	IncidentOpen   = "open"
+	IncidentClosed = "closed"
+)
+
+// ErrIncidentAcknowledged - incident is already acknowledged
+var ErrIncidentAcknowledged = errors.New("incident already acknowledged")
+
+// incidentFields - columns of incidents table in scan order, see Incident.scan
+const incidentFields = "id, service_id, reason, started_at, ended_at, acknowledged_at, acknowledged_by, " +
+	"escalation_level, escalated_at"
+
+// Incident - period when service was dead, opened and closed by health checker
+type Incident struct {
+	ID             int        `json:"id" db:"id"`
+	ServiceID      int        `json:"service_id" db:"service_id"`
+	Reason         string     `json:"reason" db:"reason"` // failure reason of check opened incident
+	StartedAt      time.Time  `json:"started_at" db:"started_at"`
+	EndedAt        *time.Time `json:"ended_at,omitempty" db:"ended_at"` // recovery time, nil for open incident
+	Duration       int        `json:"duration"`                         // seconds from start to recovery or to now for open incident
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty" db:"acknowledged_at"`
+	AcknowledgedBy int        `json:"acknowledged_by,omitempty" db:"acknowledged_by"` // id of user
+	// EscalationLevel - count of notified levels of service escalation policy
+	EscalationLevel int            `json:"escalation_level" db:"escalation_level"`
+	EscalatedAt     *time.Time     `json:"escalated_at,omitempty" db:"escalated_at"` // time of last escalation notification
+	Notes           []IncidentNote `json:"notes,omitempty"`
+}
+
+// IncidentNote - comment of user to incident
+type IncidentNote struct {
+	ID         int       `json:"id" db:"id"`
+	IncidentID int       `json:"incident_id" db:"incident_id"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	Text       string    `json:"text" db:"text"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// IncidentFilter - filter of incidents list
+type IncidentFilter struct {
+	State     string // open, closed or empty for all incidents
+	ServiceID int    // 0 for incidents of all services
+}
+
+func (i *Incident) Validate() error {
+	if i.ServiceID <= 0 {
+		return fmt.Errorf("invalid incident service id")
+	}
+
+	if i.StartedAt.IsZero() {
+		return fmt.Errorf("incident start time is empty")
+	}
+
+	return nil
+}
+
+// IsOpen - true if service is not recovered yet
+func (i *Incident) IsOpen() bool {
+	return i.EndedAt == nil
+}
+
+func (i *Incident) IsExistByName(db *sql.DB) (error, bool) { return nil, false }
+
+func (i *Incident) Count(db *sql.DB) (int, error) {
+	return CountIncidents(db, IncidentFilter{})
+}
+
+func (i *Incident) Save(db *sql.DB) error {
+	if len(i.Reason) > 1000 {
+		i.Reason = i.Reason[:1000]
+	}
+
+	result, err := db.Exec(
+		"INSERT INTO incidents (service_id, reason, started_at) VALUES (?,?,?)",
+		i.ServiceID,
+		i.Reason,
+		i.StartedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	i.ID = int(lastInsertID)
+
+	return nil
+}
+
+func (i *Incident) GetByName(db *sql.DB, name string) error { return nil }
+
+// GetByID - incident with notes
+func (i *Incident) GetByID(db *sql.DB, id int64) error {
+	err := i.scan(db.QueryRow("SELECT "+incidentFields+" FROM incidents WHERE id=?", id))
+	if err != nil {
+		return err
+	}
+
+	i.Notes, err = i.GetNotes(db)
+
+	return err
+}
+
+func (i *Incident) Pagination(db *sql.DB, start int, end int) ([]Model, error) {
+	incidents, err := GetIncidents(db, IncidentFilter{}, start, end-start)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]Model, 0, len(incidents))
+	for _, incident := range incidents {
+		result = append(result, incident)
+	}
+
+	return result, nil
+}
+
+func (i *Incident) Update(db *sql.DB, id int) error { return nil }
+func (i *Incident) Delete(db *sql.DB, id int) error { return nil }
+
+func (i *Incident) GetID() int          { return i.ID }
+func (i *Incident) SetID(id int)        { i.ID = id }
+func (i *Incident) GetName() string     { return fmt.Sprintf("incident #%d", i.ID) }
+func (i *Incident) SetName(name string) {}
+func (i *Incident) SetDeleted()         {}
+func (i *Incident) IsDeleted() bool     { return false }
+
+func (i *Incident) scan(row scanner) error {
+	var acknowledgedBy, escalationLevel sql.NullInt64
+	err := row.Scan(
+		&i.ID,
+		&i.ServiceID,
+		&i.Reason,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.AcknowledgedAt,
+		&acknowledgedBy,
+		&escalationLevel,
+		&i.EscalatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	i.AcknowledgedBy = int(acknowledgedBy.Int64)
+	i.EscalationLevel = int(escalationLevel.Int64)
+
+	var end = time.Now()
+	if i.EndedAt != nil {
+		end = *i.EndedAt
+	}
+	i.Duration = int(end.Sub(i.StartedAt) / time.Second)
+
+	return nil
+}
+
+// Close - close incident on service recovery
+func (i *Incident) Close(db *sql.DB, endedAt time.Time) error {
+	endedAt = endedAt.UTC()
+	res, err := db.Exec("UPDATE incidents SET ended_at=? WHERE id=? AND ended_at IS NULL", endedAt, i.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return fmt.Errorf("incident with id %d not closed", i.ID)
+	}
+
+	i.EndedAt = &endedAt
+	i.Duration = int(endedAt.Sub(i.StartedAt) / time.Second)
+
+	return nil
+}
+
+// Acknowledge - mark incident as taken by user
+func (i *Incident) Acknowledge(db *sql.DB, userID int, at time.Time) error {
+	at = at.UTC()
+	res, err := db.Exec(
+		"UPDATE incidents SET acknowledged_at=?, acknowledged_by=? WHERE id=? AND acknowledged_at IS NULL",
+		at,
+		userID,
+		i.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return ErrIncidentAcknowledged
+	}
+
+	i.AcknowledgedAt = &at
+	i.AcknowledgedBy = userID
+
+	return nil
+}
+
+// AddNote - save note of incident
+func (i *Incident) AddNote(db *sql.DB, note *IncidentNote) error {
+	note.Text = strings.TrimSpace(note.Text)
+	if note.Text == "" || len(note.Text) > 5000 {
+		return fmt.Errorf("invalid incident note text")
+	}
+
+	note.IncidentID = i.ID
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = time.Now()
+	}
+
+	result, err := db.Exec(
+		"INSERT INTO incident_notes (incident_id, user_id, text, created_at) VALUES (?,?,?,?)",
+		note.IncidentID,
+		note.UserID,
+		note.Text,
+		note.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	note.ID = int(lastInsertID)
+	i.Notes = append(i.Notes, *note)
+
+	return nil
+}
+
+// GetNotes - notes of incident ordered by creation time
+func (i *Incident) GetNotes(db *sql.DB) ([]IncidentNote, error) {
+	rows, err := db.Query(
+		"SELECT id, incident_id, user_id, text, created_at FROM incident_notes WHERE incident_id=? ORDER BY id",
+		i.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes = []IncidentNote{}
+	for rows.Next() {
+		var note = IncidentNote{}
+		err = rows.Scan(&note.ID, &note.IncidentID, &note.UserID, &note.Text, &note.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+// OpenIncident - open incident of dead service, already open incident of service is returned if exist
+func OpenIncident(db *sql.DB, serviceID int, reason string, startedAt time.Time) (*Incident, error) {
+	incident, err := (&Service{ID: serviceID}).GetOpenIncident(db)
+	if err != nil || incident != nil {
+		return incident, err
+	}
+
+	incident = &Incident{ServiceID: serviceID, Reason: reason, StartedAt: startedAt}
+	err = incident.Save(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// GetOpenIncident - open incident of service, nil if service has no open incident
+func (s *Service) GetOpenIncident(db *sql.DB) (*Incident, error) {
+	var incident = &Incident{}
+	err := incident.scan(db.QueryRow(
+		"SELECT "+incidentFields+" FROM incidents WHERE service_id=? AND ended_at IS NULL ORDER BY id DESC LIMIT 1",
+		s.ID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// GetIncidents - incidents by filter, newest first
+func GetIncidents(db *sql.DB, filter IncidentFilter, offset, limit int) ([]*Incident, error) {
+	where, args := filter.where()
+	args = append(args, offset, limit)
+
+	rows, err := db.Query(
+		"SELECT "+incidentFields+" FROM incidents"+where+" ORDER BY id DESC LIMIT ?, ?",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents = []*Incident{}
+	for rows.Next() {
+		var incident = &Incident{}
+		err = incident.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+// CountIncidents - count of incidents by filter
+func CountIncidents(db *sql.DB, filter IncidentFilter) (int, error) {
+	where, args := filter.where()
+
+	var count int
+	err := db.QueryRow("SELECT count(id) FROM incidents"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Validate - check filter state
+func (f IncidentFilter) Validate() error {
+	if f.State != "" && f.State != IncidentOpen && f.State != IncidentClosed {
+		return fmt.Errorf("invalid incident state: %s", f.State)
+	}
+
+	if f.ServiceID < 0 {
+		return fmt.Errorf("invalid service id: %d", f.ServiceID)
+	}
+
+	return nil
+}
+
+func (f IncidentFilter) where() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	switch f.State {
+	case IncidentOpen:
+		conditions = append(conditions, "ended_at IS NULL")
+	case IncidentClosed:
+		conditions = append(conditions, "ended_at IS NOT NULL")
+	}
+
+	if f.ServiceID > 0 {
+		conditions = append(conditions, "service_id=?")
+		args = append(args, f.ServiceID)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
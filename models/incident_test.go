@@ -0,0 +1,54 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIncidentFilter_where(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    IncidentFilter
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "all",
+			filter:    IncidentFilter{},
+			wantWhere: "",
+		},
+		{
+			name:      "open",
+			filter:    IncidentFilter{State: IncidentOpen},
+			wantWhere: " WHERE ended_at IS NULL",
+		},
+		{
+			name:      "closed of service",
+			filter:    IncidentFilter{State: IncidentClosed, ServiceID: 3},
+			wantWhere: " WHERE ended_at IS NOT NULL AND service_id=?",
+			wantArgs:  []interface{}{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.filter.where()
+			if where != tt.wantWhere {
+				t.Errorf("where() = %q, want %q", where, tt.wantWhere)
+			}
+
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("where() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestIncidentFilter_Validate(t *testing.T) {
+	if err := (IncidentFilter{State: "unknown"}).Validate(); err == nil {
+		t.Errorf("Validate() of unknown state error is nil")
+	}
+
+	if err := (IncidentFilter{State: IncidentOpen, ServiceID: 1}).Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
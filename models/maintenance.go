@@ -0,0 +1,289 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type MaintenanceMode int
+
+const (
+	_                   MaintenanceMode = iota
+	MaintenancePause                    // health checks are not sent
+	MaintenanceSuppress                 // health checks are sent, but service status is not changed and nobody notified
+)
+
+const maintenanceFields = "id, service_id, name, mode, starts_at, ends_at, cron, duration"
+
+// MaintenanceWindow - one-off (StartsAt - EndsAt) or recurring (Cron and Duration) service maintenance
+type MaintenanceWindow struct {
+	ID        int             `json:"id"`
+	ServiceID int             `json:"service_id"`
+	Name      string          `json:"name"`
+	Mode      MaintenanceMode `json:"mode"`
+	StartsAt  *time.Time      `json:"starts_at,omitempty"`
+	EndsAt    *time.Time      `json:"ends_at,omitempty"`
+	Cron      string          `json:"cron,omitempty"`     // start of recurring window, standard cron expression
+	Duration  int             `json:"duration,omitempty"` // duration of recurring window in seconds
+}
+
+func (m *MaintenanceWindow) Validate() error {
+	if m.ServiceID == 0 {
+		return fmt.Errorf("maintenance service id is empty")
+	}
+
+	if len(m.Name) > 255 {
+		return fmt.Errorf("invalid maintenance name")
+	}
+
+	if m.Mode != MaintenancePause && m.Mode != MaintenanceSuppress {
+		return fmt.Errorf("invalid maintenance mode: %v", m.Mode)
+	}
+
+	if m.Cron == "" {
+		if m.StartsAt == nil || m.EndsAt == nil {
+			return fmt.Errorf("maintenance must have start and end or cron and duration")
+		}
+
+		if !m.EndsAt.After(*m.StartsAt) {
+			return fmt.Errorf("maintenance end must be after start")
+		}
+
+		return nil
+	}
+
+	if m.StartsAt != nil || m.EndsAt != nil {
+		return fmt.Errorf("recurring maintenance must not have start and end")
+	}
+
+	if _, err := cron.ParseStandard(m.Cron); err != nil {
+		return fmt.Errorf("maintenance cron %s error: %v", m.Cron, err)
+	}
+
+	if m.Duration <= 0 {
+		return fmt.Errorf("recurring maintenance duration must be positive")
+	}
+
+	return nil
+}
+
+// IsActive - true if t is inside of maintenance window
+func (m *MaintenanceWindow) IsActive(t time.Time) bool {
+	if m.Cron == "" {
+		return m.StartsAt != nil && m.EndsAt != nil && !t.Before(*m.StartsAt) && t.Before(*m.EndsAt)
+	}
+
+	schedule, err := cron.ParseStandard(m.Cron)
+	if err != nil {
+		return false
+	}
+
+	// window is active if it started no later than duration ago
+	var duration = time.Duration(m.Duration) * time.Second
+	return !schedule.Next(t.Add(-duration)).After(t)
+}
+
+// IsExistByName - service of window has window with the same name
+func (m *MaintenanceWindow) IsExistByName(db *sql.DB) (error, bool) {
+	var id int
+	err := db.QueryRow(
+		"SELECT id FROM maintenance_windows WHERE service_id=? AND name=?", m.ServiceID, m.Name,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, false
+	}
+
+	if err != nil {
+		return err, false
+	}
+
+	return nil, true
+}
+
+func (m *MaintenanceWindow) Count(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT count(id) FROM maintenance_windows").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (m *MaintenanceWindow) Save(db *sql.DB) error {
+	result, err := db.Exec(
+		"INSERT INTO maintenance_windows (service_id, name, mode, starts_at, ends_at, cron, duration) VALUES (?,?,?,?,?,?,?)",
+		m.ServiceID,
+		m.Name,
+		m.Mode,
+		m.StartsAt,
+		m.EndsAt,
+		m.Cron,
+		m.Duration,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	m.ID = int(lastInsertID)
+
+	return nil
+}
+
+// GetByName - window of service m.ServiceID by name, names are unique only inside of service
+func (m *MaintenanceWindow) GetByName(db *sql.DB, name string) error {
+	return m.scan(db.QueryRow(
+		"SELECT "+maintenanceFields+" FROM maintenance_windows WHERE service_id=? AND name=? ORDER BY id LIMIT 1",
+		m.ServiceID, name,
+	))
+}
+
+func (m *MaintenanceWindow) GetByID(db *sql.DB, id int64) error {
+	return m.scan(db.QueryRow("SELECT "+maintenanceFields+" FROM maintenance_windows WHERE id=?", id))
+}
+
+func (m *MaintenanceWindow) Pagination(db *sql.DB, start, end int) ([]Model, error) {
+	rows, err := db.Query(
+		"SELECT "+maintenanceFields+" FROM maintenance_windows ORDER BY id ASC LIMIT ?, ?",
+		start, end,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Model
+	for rows.Next() {
+		var window = &MaintenanceWindow{}
+		err = window.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, window)
+	}
+
+	return result, rows.Err()
+}
+
+// Update - replace settings of window, window stays in its service
+func (m *MaintenanceWindow) Update(db *sql.DB, id int) error {
+	res, err := db.Exec(
+		"UPDATE maintenance_windows SET name=?, mode=?, starts_at=?, ends_at=?, cron=?, duration=? WHERE id=?",
+		m.Name,
+		m.Mode,
+		m.StartsAt,
+		m.EndsAt,
+		m.Cron,
+		m.Duration,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (m *MaintenanceWindow) Delete(db *sql.DB, id int) error {
+	res, err := db.Exec("DELETE FROM maintenance_windows WHERE id=?", id)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return fmt.Errorf("maintenance with id %d not deleted", id)
+	}
+
+	return nil
+}
+
+func (m *MaintenanceWindow) GetID() int          { return m.ID }
+func (m *MaintenanceWindow) SetID(id int)        { m.ID = id }
+func (m *MaintenanceWindow) GetName() string     { return m.Name }
+func (m *MaintenanceWindow) SetName(name string) { m.Name = name }
+func (m *MaintenanceWindow) SetDeleted()         {}
+func (m *MaintenanceWindow) IsDeleted() bool     { return false }
+
+func (m *MaintenanceWindow) scan(row scanner) error {
+	var cronExpr sql.NullString
+	var duration sql.NullInt64
+	err := row.Scan(
+		&m.ID,
+		&m.ServiceID,
+		&m.Name,
+		&m.Mode,
+		&m.StartsAt,
+		&m.EndsAt,
+		&cronExpr,
+		&duration,
+	)
+	if err != nil {
+		return err
+	}
+
+	m.Cron = cronExpr.String
+	m.Duration = int(duration.Int64)
+
+	return nil
+}
+
+// GetMaintenanceWindows - maintenance windows of service
+func (s *Service) GetMaintenanceWindows(db *sql.DB) ([]MaintenanceWindow, error) {
+	s.Maintenance = []MaintenanceWindow{}
+	rows, err := db.Query("SELECT "+maintenanceFields+" FROM maintenance_windows WHERE service_id=? ORDER BY id", s.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var window = MaintenanceWindow{}
+		err = window.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Maintenance = append(s.Maintenance, window)
+	}
+
+	return s.Maintenance, rows.Err()
+}
+
+// ActiveMaintenance - maintenance window of service active at t, nil if no one
+func (s *Service) ActiveMaintenance(db *sql.DB, t time.Time) (*MaintenanceWindow, error) {
+	windows, err := s.GetMaintenanceWindows(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range windows {
+		if windows[i].IsActive(t) {
+			return &windows[i], nil
+		}
+	}
+
+	return nil, nil
+}
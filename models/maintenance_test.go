@@ -0,0 +1,103 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindow_IsActive(t *testing.T) {
+	var now = time.Date(2020, 1, 15, 3, 30, 0, 0, time.Local)
+	var hourAgo = now.Add(-time.Hour)
+	var hourLater = now.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		want   bool
+	}{
+		{
+			name:   "one-off active",
+			window: MaintenanceWindow{StartsAt: &hourAgo, EndsAt: &hourLater},
+			want:   true,
+		},
+		{
+			name:   "one-off finished",
+			window: MaintenanceWindow{StartsAt: &hourAgo, EndsAt: &now},
+			want:   false,
+		},
+		{
+			name:   "one-off not started",
+			window: MaintenanceWindow{StartsAt: &hourLater, EndsAt: &hourLater},
+			want:   false,
+		},
+		{
+			name:   "recurring every night, active",
+			window: MaintenanceWindow{Cron: "0 3 * * *", Duration: 3600},
+			want:   true,
+		},
+		{
+			name:   "recurring every night, finished",
+			window: MaintenanceWindow{Cron: "0 3 * * *", Duration: 1200},
+			want:   false,
+		},
+		{
+			name:   "recurring on sunday",
+			window: MaintenanceWindow{Cron: "0 3 * * 0", Duration: 3600},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.IsActive(now); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_Validate(t *testing.T) {
+	var now = time.Now()
+	var later = now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		wantErr bool
+	}{
+		{
+			name:   "one-off",
+			window: MaintenanceWindow{ServiceID: 1, Mode: MaintenancePause, StartsAt: &now, EndsAt: &later},
+		},
+		{
+			name:   "recurring",
+			window: MaintenanceWindow{ServiceID: 1, Mode: MaintenanceSuppress, Cron: "0 3 * * *", Duration: 60},
+		},
+		{
+			name:    "end before start",
+			window:  MaintenanceWindow{ServiceID: 1, Mode: MaintenancePause, StartsAt: &later, EndsAt: &now},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cron",
+			window:  MaintenanceWindow{ServiceID: 1, Mode: MaintenancePause, Cron: "every night", Duration: 60},
+			wantErr: true,
+		},
+		{
+			name:    "recurring without duration",
+			window:  MaintenanceWindow{ServiceID: 1, Mode: MaintenancePause, Cron: "0 3 * * *"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode",
+			window:  MaintenanceWindow{ServiceID: 1, StartsAt: &now, EndsAt: &later},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.window.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+// Resource - kind of objects protected by role permissions
+type Resource string
+
+const (
+	ResourceUser         Resource = "user"
+	ResourceConfig       Resource = "config"
+	ResourceService      Resource = "service"
+	ResourceMaintenance  Resource = "maintenance"
+	ResourceHealthCheck  Resource = "health_check"
+	ResourceIncident     Resource = "incident"
+	ResourceEscalation   Resource = "escalation"
+	ResourceTemplate     Resource = "template"
+	ResourceNotification Resource = "notification" // outbound queue and recipient settings
+	ResourceAudit        Resource = "audit"        // audit log of security events
+)
+
+// Action - operation on resource
+type Action string
+
+const (
+	ActionRead   Action = "read"
+	ActionWrite  Action = "write" // create, update or operate, e.g. pause service or acknowledge incident
+	ActionDelete Action = "delete"
+)
+
+var (
+	allActions   = []Action{ActionRead, ActionWrite, ActionDelete}
+	readOnly     = []Action{ActionRead}
+	allResources = []Resource{
+		ResourceUser, ResourceConfig, ResourceService, ResourceMaintenance, ResourceHealthCheck,
+		ResourceIncident, ResourceEscalation, ResourceTemplate, ResourceNotification, ResourceAudit,
+	}
+)
+
+// IsValid - true if resource is known
+func (r Resource) IsValid() bool {
+	for _, resource := range allResources {
+		if r == resource {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValid - true if action is known
+func (a Action) IsValid() bool {
+	for _, action := range allActions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// permissions - actions allowed to role per resource, SuperAdmin is allowed everything
+var permissions = map[Role]map[Resource][]Action{
+	Admin: {
+		ResourceUser:         readOnly,
+		ResourceConfig:       readOnly,
+		ResourceService:      allActions,
+		ResourceMaintenance:  allActions,
+		ResourceHealthCheck:  readOnly,
+		ResourceIncident:     allActions,
+		ResourceEscalation:   allActions,
+		ResourceTemplate:     allActions,
+		ResourceNotification: allActions,
+		ResourceAudit:        readOnly,
+	},
+	Viewer: {
+		ResourceService:     readOnly,
+		ResourceMaintenance: readOnly,
+		ResourceHealthCheck: readOnly,
+		ResourceIncident:    readOnly,
+	},
+}
+
+// IsValid - true if role is known
+func (r Role) IsValid() bool {
+	switch r {
+	case Admin, SuperAdmin, Viewer:
+		return true
+	}
+
+	return false
+}
+
+// Can - true if role is allowed action on resource
+func (r Role) Can(resource Resource, action Action) bool {
+	if r == SuperAdmin {
+		return true
+	}
+
+	for _, allowed := range permissions[r][resource] {
+		if allowed == action {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,30 @@
+package models
+
+import "testing"
+
+func TestRole_Can(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     Role
+		resource Resource
+		action   Action
+		want     bool
+	}{
+		{name: "super admin manages users", role: SuperAdmin, resource: ResourceUser, action: ActionDelete, want: true},
+		{name: "admin reads users", role: Admin, resource: ResourceUser, action: ActionRead, want: true},
+		{name: "admin does not create users", role: Admin, resource: ResourceUser, action: ActionWrite},
+		{name: "admin does not change configs", role: Admin, resource: ResourceConfig, action: ActionWrite},
+		{name: "admin deletes services", role: Admin, resource: ResourceService, action: ActionDelete, want: true},
+		{name: "viewer reads incidents", role: Viewer, resource: ResourceIncident, action: ActionRead, want: true},
+		{name: "viewer does not acknowledge incidents", role: Viewer, resource: ResourceIncident, action: ActionWrite},
+		{name: "viewer does not read users", role: Viewer, resource: ResourceUser, action: ActionRead},
+		{name: "unknown role", role: Role(0), resource: ResourceService, action: ActionRead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.role.Can(tt.resource, tt.action); got != tt.want {
+				t.Errorf("Can() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// RecipientSettings - per-recipient overrides of notifier digest and rate limit settings,
+// nil field means value from notifier config
+type RecipientSettings struct {
+	ID    int    `json:"id" db:"id"`
+	Email string `json:"email" db:"email"`
+	// DigestWindow - seconds during which emails of recipient are batched to one digest, 0 - digest is disabled
+	DigestWindow *int `json:"digest_window,omitempty" db:"digest_window"`
+	// RateLimit - max count of emails to recipient per notifier rate window, 0 - unlimited
+	RateLimit *int `json:"rate_limit,omitempty" db:"rate_limit"`
+}
+
+func (r *RecipientSettings) Validate() error {
+	if !RegexEmail.MatchString(r.Email) {
+		return fmt.Errorf("is not email: %s", r.Email)
+	}
+
+	if r.DigestWindow != nil && *r.DigestWindow < 0 {
+		return fmt.Errorf("digest window must be not negative")
+	}
+
+	if r.RateLimit != nil && *r.RateLimit < 0 {
+		return fmt.Errorf("rate limit must be not negative")
+	}
+
+	return nil
+}
+
+func (r *RecipientSettings) IsExistByName(db *sql.DB) (error, bool) {
+	var id int
+	err := db.QueryRow("SELECT id FROM recipient_settings WHERE email=?", r.Email).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, false
+	}
+
+	if err != nil {
+		return err, false
+	}
+
+	return nil, true
+}
+
+func (r *RecipientSettings) Count(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT count(id) FROM recipient_settings").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *RecipientSettings) Save(db *sql.DB) error {
+	result, err := db.Exec(
+		"INSERT INTO recipient_settings (email, digest_window, rate_limit) VALUES (?,?,?)",
+		r.Email,
+		r.DigestWindow,
+		r.RateLimit,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	r.ID = int(lastInsertID)
+
+	return nil
+}
+
+// GetByName - settings of recipient email
+func (r *RecipientSettings) GetByName(db *sql.DB, email string) error {
+	return db.QueryRow("SELECT id, email, digest_window, rate_limit FROM recipient_settings WHERE email=?", email).
+		Scan(&r.ID, &r.Email, &r.DigestWindow, &r.RateLimit)
+}
+
+func (r *RecipientSettings) GetByID(db *sql.DB, id int64) error {
+	return db.QueryRow("SELECT id, email, digest_window, rate_limit FROM recipient_settings WHERE id=?", id).
+		Scan(&r.ID, &r.Email, &r.DigestWindow, &r.RateLimit)
+}
+
+func (r *RecipientSettings) Pagination(db *sql.DB, start int, end int) ([]Model, error) {
+	rows, err := db.Query(
+		"SELECT id, email, digest_window, rate_limit FROM recipient_settings ORDER BY id ASC LIMIT ?, ?",
+		start, end-start,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Model
+	for rows.Next() {
+		var settings = &RecipientSettings{}
+		err = rows.Scan(&settings.ID, &settings.Email, &settings.DigestWindow, &settings.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, settings)
+	}
+
+	return result, rows.Err()
+}
+
+// Update - replace settings of recipient, nil field resets it to notifier config value
+func (r *RecipientSettings) Update(db *sql.DB, id int) error {
+	res, err := db.Exec(
+		"UPDATE recipient_settings SET email=?, digest_window=?, rate_limit=? WHERE id=?",
+		r.Email,
+		r.DigestWindow,
+		r.RateLimit,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *RecipientSettings) Delete(db *sql.DB, id int) error {
+	res, err := db.Exec("DELETE FROM recipient_settings WHERE id=?", id)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *RecipientSettings) GetID() int          { return r.ID }
+func (r *RecipientSettings) SetID(id int)        { r.ID = id }
+func (r *RecipientSettings) GetName() string     { return r.Email }
+func (r *RecipientSettings) SetName(name string) { r.Email = name }
+func (r *RecipientSettings) SetDeleted()         {}
+func (r *RecipientSettings) IsDeleted() bool     { return false }
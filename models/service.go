@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type Status int
@@ -13,20 +14,97 @@ const (
 	_ = iota
 	Alive
 	Dead
+	Warning  // service alive, but certificate of service link expires soon
+	Degraded // service not responding, because one of its dependencies is down
 )
 
+// IsValid - true if status is one of known statuses
+func (s Status) IsValid() bool {
+	return s == Alive || s == Dead || s == Warning || s == Degraded
+}
+
+// IsDown - true if service not responding
+func (s Status) IsDown() bool {
+	return s == Dead || s == Degraded
+}
+
+func (s Status) String() string {
+	switch s {
+	case Alive:
+		return "alive"
+	case Dead:
+		return "dead"
+	case Warning:
+		return "warning"
+	case Degraded:
+		return "degraded"
+	default:
+		return "unknown"
+	}
+}
+
+// MaxRetries - upper limit of probe retries for one health check
+const MaxRetries = 10
+
+// serviceFields - columns of services table in scan order, see Service.scan
+const serviceFields = "id, name, link, Token, frequency, status, deleted, timeout, retries, retry_backoff, " +
+	"cert_warn_days, cert_expires_at, paused, public, escalation_policy_id"
+
 type Service struct {
-	ID        int     `json:"id";db:"id"`
-	Name      string  `json:"name";db:"name"`
-	Link      string  `json:"link";db:"link"`
-	Token     string  `json:"token";db:"token"`
-	Frequency int     `json:"frequency";db:"frequency"`
-	Status    Status  `json:"status";db:"status"`
-	Deleted   int     `json:"deleted";db:"deleted"`
-	Emails    []Email `json:"emails"`
+	ID           int    `json:"id" db:"id"`
+	Name         string `json:"name" db:"name"`
+	Link         string `json:"link" db:"link"`
+	Token        string `json:"token" db:"token"`
+	Frequency    int    `json:"frequency" db:"frequency"`
+	Status       Status `json:"status" db:"status"`
+	Deleted      int    `json:"deleted" db:"deleted"`
+	Timeout      int    `json:"timeout" db:"timeout"`             // probe request timeout in seconds, 0 - default from config
+	Retries      int    `json:"retries" db:"retries"`             // count of probe retries before service marked as dead
+	RetryBackoff int    `json:"retry_backoff" db:"retry_backoff"` // delay in seconds before first retry, doubled on each next retry
+	// CertWarnDays - service status is Warning when link certificate expires in less days, 0 - default from config
+	CertWarnDays  int        `json:"cert_warn_days" db:"cert_warn_days"`
+	CertExpiresAt *time.Time `json:"cert_expires_at,omitempty" db:"cert_expires_at"` // earliest expiry in link certificate chain
+	Paused        bool       `json:"paused" db:"paused"`                             // health checks of paused service are not planned
+	Public        *bool      `json:"public,omitempty" db:"public"`                   // public service is shown on status page
+	// EscalationPolicyID - policy notified about unacknowledged incidents of service, 0 - without policy
+	EscalationPolicyID *int                `json:"escalation_policy_id,omitempty" db:"escalation_policy_id"`
+	Emails             []Email             `json:"emails"`
+	Channels           []Channel           `json:"channels"`
+	Dependencies       []int               `json:"dependencies"` // ids of services on which this service depends
+	Maintenance        []MaintenanceWindow `json:"maintenance,omitempty"`
+	// UpdateFields - json fields of update request, Update saves zero values of them too
+	UpdateFields map[string]bool `json:"-" db:"-"`
 }
 
 func (s *Service) Validate() error {
+	return s.validate(true)
+}
+
+// ValidateUpdate - validate service merged with update request,
+// status set by health checker is validated only if it is updated
+func (s *Service) ValidateUpdate() error {
+	return s.validate(s.UpdateFields["status"])
+}
+
+// ValidateTimeout - timeout of probe with config default resolved must be less than frequency
+func (s *Service) ValidateTimeout(defaultTimeout int) error {
+	if s.EffectiveTimeout(defaultTimeout) >= s.Frequency {
+		return fmt.Errorf("timeout must be less than frequency")
+	}
+
+	return nil
+}
+
+// EffectiveTimeout - probe timeout of service in seconds, defaultTimeout if service has no own timeout
+func (s *Service) EffectiveTimeout(defaultTimeout int) int {
+	if s.Timeout <= 0 {
+		return defaultTimeout
+	}
+
+	return s.Timeout
+}
+
+func (s *Service) validate(status bool) error {
 	if s.Name == "" || len(s.Name) > 255 {
 		return fmt.Errorf("invalid service name")
 	}
@@ -52,10 +130,30 @@ func (s *Service) Validate() error {
 		return fmt.Errorf("frequency must be not 0")
 	}
 
-	if s.Status != Alive && s.Status != Dead {
+	if status && s.Status != Alive && s.Status != Dead {
 		return fmt.Errorf("invalid service status: %v", s.Status)
 	}
 
+	if s.Timeout < 0 {
+		return fmt.Errorf("timeout must be not negative")
+	}
+
+	if s.Retries < 0 || s.Retries > MaxRetries {
+		return fmt.Errorf("retries must be between 0 and %d", MaxRetries)
+	}
+
+	if s.RetryBackoff < 0 {
+		return fmt.Errorf("retry backoff must be not negative")
+	}
+
+	if s.CertWarnDays < 0 {
+		return fmt.Errorf("cert warn days must be not negative")
+	}
+
+	if s.GetEscalationPolicyID() < 0 {
+		return fmt.Errorf("invalid escalation policy id: %d", *s.EscalationPolicyID)
+	}
+
 	for _, email := range s.Emails {
 		err = email.Validate()
 		if err != nil {
@@ -63,6 +161,26 @@ func (s *Service) Validate() error {
 		}
 	}
 
+	for _, channel := range s.Channels {
+		err = channel.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	var dependencies = make(map[int]bool, len(s.Dependencies))
+	for _, dependencyID := range s.Dependencies {
+		if dependencyID <= 0 {
+			return fmt.Errorf("invalid dependency id: %d", dependencyID)
+		}
+
+		if dependencies[dependencyID] {
+			return fmt.Errorf("duplicate dependency id: %d", dependencyID)
+		}
+
+		dependencies[dependencyID] = true
+	}
+
 	return nil
 }
 
@@ -90,13 +208,30 @@ func (s *Service) Count(db *sql.DB) (int, error) {
 	return count, nil
 }
 func (s *Service) Save(db *sql.DB) error {
+	err := checkDependencies(db, 0, s.Dependencies)
+	if err != nil {
+		return err
+	}
+
+	err = checkEscalationPolicy(db, s.EscalationPolicyID)
+	if err != nil {
+		return err
+	}
+
 	result, err := db.Exec(
-		"INSERT INTO services (name, link, Token, frequency, status) VALUES (?,?,?,?,?)",
+		"INSERT INTO services (name, link, Token, frequency, status, timeout, retries, retry_backoff, cert_warn_days, public, "+
+			"escalation_policy_id) VALUES (?,?,?,?,?,?,?,?,?,?,?)",
 		s.Name,
 		s.Link,
 		s.Token,
 		s.Frequency,
 		s.Status,
+		s.Timeout,
+		s.Retries,
+		s.RetryBackoff,
+		s.CertWarnDays,
+		s.IsPublic(),
+		s.GetEscalationPolicyID(),
 	)
 	if err != nil {
 		return err
@@ -109,56 +244,42 @@ func (s *Service) Save(db *sql.DB) error {
 
 	s.ID = int(lastInsertID)
 
-	return nil
+	// initial status is start of service uptime history
+	err = SaveStatusChange(db, s.ID, s.Status, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = s.saveChannels(db, s.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.saveDependencies(db, s.ID)
 }
 
 func (s *Service) GetByName(db *sql.DB, name string) error {
-	return db.QueryRow(
-		"SELECT id, name, link, Token, frequency, status, deleted FROM services WHERE name=?", name).Scan(
-		&s.ID,
-		&s.Name,
-		&s.Link,
-		&s.Token,
-		&s.Frequency,
-		&s.Status,
-		&s.Deleted,
-	)
+	return s.scan(db.QueryRow("SELECT "+serviceFields+" FROM services WHERE name=?", name))
 }
 
 func (s *Service) GetByID(db *sql.DB, id int64) error {
-	return db.QueryRow(
-		"SELECT id, name, link, Token, frequency, status, deleted FROM services WHERE id=?", id).Scan(
-		&s.ID,
-		&s.Name,
-		&s.Link,
-		&s.Token,
-		&s.Frequency,
-		&s.Status,
-		&s.Deleted,
-	)
+	return s.scan(db.QueryRow("SELECT "+serviceFields+" FROM services WHERE id=?", id))
 }
 
 func (s *Service) Pagination(db *sql.DB, start, end int) ([]Model, error) {
 	var result []Model
 
 	raws, err := db.Query(
-		"SELECT id, name, link, Token, frequency, status, deleted FROM services ORDER BY id ASC limit ?, ?",
+		"SELECT "+serviceFields+" FROM services ORDER BY id ASC limit ?, ?",
 		start, end)
 	if err != nil {
 		return nil, err
 	}
+	defer raws.Close()
 
 	for raws.Next() {
 		var service = &Service{}
-		err = raws.Scan(
-			&service.ID,
-			&service.Name,
-			&service.Link,
-			&service.Token,
-			&service.Frequency,
-			&service.Status,
-			&service.Deleted,
-		)
+		err = service.scan(raws)
 		if err != nil {
 			return nil, err
 		}
@@ -168,6 +289,16 @@ func (s *Service) Pagination(db *sql.DB, start, end int) ([]Model, error) {
 			return nil, err
 		}
 
+		service.Channels, err = service.GetChannels(db)
+		if err != nil {
+			return nil, err
+		}
+
+		service.Dependencies, err = service.GetDependencies(db)
+		if err != nil {
+			return nil, err
+		}
+
 		result = append(result, service)
 	}
 
@@ -175,6 +306,11 @@ func (s *Service) Pagination(db *sql.DB, start, end int) ([]Model, error) {
 }
 
 func (s *Service) Update(db *sql.DB, id int) error {
+	err := checkEscalationPolicy(db, s.EscalationPolicyID)
+	if err != nil {
+		return err
+	}
+
 	var query, args = s.buildServiceUpdateQuery(id)
 
 	tx, err := db.Begin()
@@ -222,6 +358,36 @@ func (s *Service) Update(db *sql.DB, id int) error {
 		}
 	}
 
+	if s.Channels != nil {
+		for _, channel := range s.Channels {
+			err = channel.Validate()
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		err = s.saveChannels(db, id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if s.Dependencies != nil {
+		err = checkDependencies(db, id, s.Dependencies)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = s.saveDependencies(db, id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	return tx.Commit()
 }
 
@@ -260,6 +426,18 @@ func (s *Service) Delete(db *sql.DB, id int) error {
 		return err
 	}
 
+	_, err = db.Exec("DELETE FROM service_dependencies WHERE service_id=? OR depends_on=?", id, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = db.Exec("DELETE FROM maintenance_windows WHERE service_id=?", id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -339,11 +517,46 @@ func (s *Service) buildServiceUpdateQuery(id int) (string, []interface{}) {
 		args = append(args, s.Frequency)
 	}
 
-	if s.Status == Alive || s.Status == Dead {
+	if s.Status.IsValid() {
 		queryBuild.WriteString(`status=?, `)
 		args = append(args, s.Status)
 	}
 
+	if s.Timeout > 0 || s.UpdateFields["timeout"] {
+		queryBuild.WriteString(`timeout=?, `)
+		args = append(args, s.Timeout)
+	}
+
+	if s.Retries > 0 || s.UpdateFields["retries"] {
+		queryBuild.WriteString(`retries=?, `)
+		args = append(args, s.Retries)
+	}
+
+	if s.RetryBackoff > 0 || s.UpdateFields["retry_backoff"] {
+		queryBuild.WriteString(`retry_backoff=?, `)
+		args = append(args, s.RetryBackoff)
+	}
+
+	if s.CertWarnDays > 0 || s.UpdateFields["cert_warn_days"] {
+		queryBuild.WriteString(`cert_warn_days=?, `)
+		args = append(args, s.CertWarnDays)
+	}
+
+	if s.CertExpiresAt != nil {
+		queryBuild.WriteString(`cert_expires_at=?, `)
+		args = append(args, *s.CertExpiresAt)
+	}
+
+	if s.Public != nil {
+		queryBuild.WriteString(`public=?, `)
+		args = append(args, *s.Public)
+	}
+
+	if s.EscalationPolicyID != nil {
+		queryBuild.WriteString(`escalation_policy_id=?, `)
+		args = append(args, *s.EscalationPolicyID)
+	}
+
 	query := strings.TrimRight(queryBuild.String(), ", ")
 
 	queryBuild.Reset()
@@ -354,3 +567,64 @@ func (s *Service) buildServiceUpdateQuery(id int) (string, []interface{}) {
 
 	return queryBuild.String(), args
 }
+
+// scanner - common interface of sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan - fill service from row selected by serviceFields
+func (s *Service) scan(row scanner) error {
+	return row.Scan(
+		&s.ID,
+		&s.Name,
+		&s.Link,
+		&s.Token,
+		&s.Frequency,
+		&s.Status,
+		&s.Deleted,
+		&s.Timeout,
+		&s.Retries,
+		&s.RetryBackoff,
+		&s.CertWarnDays,
+		&s.CertExpiresAt,
+		&s.Paused,
+		&s.Public,
+		&s.EscalationPolicyID,
+	)
+}
+
+// IsPublic - true if service is shown on status page
+func (s *Service) IsPublic() bool {
+	return s.Public != nil && *s.Public
+}
+
+// GetEscalationPolicyID - id of service escalation policy, 0 - service without policy
+func (s *Service) GetEscalationPolicyID() int {
+	if s.EscalationPolicyID == nil {
+		return 0
+	}
+
+	return *s.EscalationPolicyID
+}
+
+// SetPaused - pause or resume health checks of service
+func (s *Service) SetPaused(db *sql.DB, paused bool) error {
+	res, err := db.Exec("UPDATE services SET paused=? WHERE id=? AND deleted=0", paused, s.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.Paused = paused
+
+	return nil
+}
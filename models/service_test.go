@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestService_buildServiceUpdateQuery(t *testing.T) {
+	var service = &Service{
+		Retries:      0,
+		Timeout:      5,
+		UpdateFields: map[string]bool{"retries": true, "cert_warn_days": true},
+	}
+
+	query, args := service.buildServiceUpdateQuery(1)
+
+	for _, column := range []string{"timeout=?", "retries=?", "cert_warn_days=?"} {
+		if !strings.Contains(query, column) {
+			t.Errorf("query %q has no %s", query, column)
+		}
+	}
+
+	if strings.Contains(query, "retry_backoff=?") {
+		t.Errorf("query %q updates field not present in request", query)
+	}
+
+	if len(args) != 4 {
+		t.Errorf("query args %v, want 4", args)
+	}
+}
+
+func TestService_ValidateTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		timeout        int
+		defaultTimeout int
+		wantErr        bool
+	}{
+		{name: "own timeout", timeout: 10, defaultTimeout: 60},
+		{name: "own timeout not less than frequency", timeout: 30, defaultTimeout: 10, wantErr: true},
+		{name: "default timeout", defaultTimeout: 10},
+		{name: "default timeout not less than frequency", defaultTimeout: 30, wantErr: true},
+	}
+	for _, tt := range tests {
+		var service = &Service{Frequency: 30, Timeout: tt.timeout}
+		if err := service.ValidateTimeout(tt.defaultTimeout); (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateTimeout() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
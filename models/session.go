@@ -0,0 +1,141 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"time"
+)
+
+// Session - login of user, identified by refresh token, access tokens issued for session carry its id,
+// they are rejected once session is revoked or expired
+type Session struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"` // sha256 of refresh token, token itself is not stored
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+const sessionFields = "id, user_id, token_hash, created_at, expires_at, revoked_at"
+
+// NewRefreshToken - random refresh token
+func NewRefreshToken() (string, error) {
+	var buf = make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// HashToken - stored form of refresh token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive - session is not revoked and not expired at now
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+func (s *Session) Save(db *sql.DB) error {
+	result, err := db.Exec(
+		"INSERT INTO sessions (user_id, token_hash, created_at, expires_at) VALUES (?,?,?,?)",
+		s.UserID,
+		s.TokenHash,
+		s.CreatedAt.UTC(),
+		s.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	s.ID = int(lastInsertID)
+
+	return nil
+}
+
+// Rotate - replace refresh token of session and prolong it, previous refresh token is not accepted anymore
+func (s *Session) Rotate(db *sql.DB, tokenHash string, expiresAt time.Time) error {
+	expiresAt = expiresAt.UTC()
+	result, err := db.Exec(
+		"UPDATE sessions SET token_hash=?, expires_at=? WHERE id=? AND token_hash=? AND revoked_at IS NULL",
+		tokenHash, expiresAt, s.ID, s.TokenHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	// concurrent refresh with the same token already rotated session
+	if count, err := result.RowsAffected(); err != nil || count == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.TokenHash = tokenHash
+	s.ExpiresAt = expiresAt
+
+	return nil
+}
+
+// Revoke - end session, its refresh token and access tokens are not accepted anymore
+func (s *Session) Revoke(db *sql.DB, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec("UPDATE sessions SET revoked_at=? WHERE id=? AND revoked_at IS NULL", at, s.ID)
+	if err != nil {
+		return err
+	}
+
+	if s.RevokedAt == nil {
+		s.RevokedAt = &at
+	}
+
+	return nil
+}
+
+func (s *Session) scan(row scanner) error {
+	return row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.TokenHash,
+		&s.CreatedAt,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+	)
+}
+
+// GetSession - session by id
+func GetSession(db *sql.DB, id int) (*Session, error) {
+	var session = &Session{}
+	err := session.scan(db.QueryRow("SELECT "+sessionFields+" FROM sessions WHERE id=?", id))
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetSessionByToken - session of refresh token
+func GetSessionByToken(db *sql.DB, token string) (*Session, error) {
+	var session = &Session{}
+	err := session.scan(db.QueryRow("SELECT "+sessionFields+" FROM sessions WHERE token_hash=?", HashToken(token)))
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// RevokeUserSessions - end all sessions of user
+func RevokeUserSessions(db *sql.DB, userID int, at time.Time) error {
+	_, err := db.Exec("UPDATE sessions SET revoked_at=? WHERE user_id=? AND revoked_at IS NULL", at.UTC(), userID)
+	return err
+}
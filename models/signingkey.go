@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SigningKey - private key signing access tokens, retired key only verifies tokens issued before rotation
+type SigningKey struct {
+	ID         int        `json:"id" db:"id"`
+	Kid        string     `json:"kid" db:"kid"`
+	Algorithm  string     `json:"algorithm" db:"algorithm"`
+	PrivateKey string     `json:"-" db:"private_key"` // PKCS #8 PEM
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at,omitempty" db:"retired_at"`
+}
+
+func (k *SigningKey) Save(db *sql.DB) error {
+	result, err := db.Exec(
+		"INSERT INTO signing_keys (kid, algorithm, private_key, created_at) VALUES (?,?,?,?)",
+		k.Kid, k.Algorithm, k.PrivateKey, k.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	k.ID = int(lastInsertID)
+
+	return nil
+}
+
+// Retire - stop signing by key, it still verifies tokens
+func (k *SigningKey) Retire(db *sql.DB, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec("UPDATE signing_keys SET retired_at=? WHERE id=? AND retired_at IS NULL", at, k.ID)
+	if err != nil {
+		return err
+	}
+
+	k.RetiredAt = &at
+
+	return nil
+}
+
+// GetSigningKeys - signing keys, newest first
+func GetSigningKeys(db *sql.DB) ([]*SigningKey, error) {
+	rows, err := db.Query(
+		"SELECT id, kid, algorithm, private_key, created_at, retired_at FROM signing_keys ORDER BY id DESC",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*SigningKey
+	for rows.Next() {
+		var key = &SigningKey{}
+		err = rows.Scan(&key.ID, &key.Kid, &key.Algorithm, &key.PrivateKey, &key.CreatedAt, &key.RetiredAt)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// DeleteSigningKeysRetiredBefore - remove keys retired before time, tokens signed by them are expired
+func DeleteSigningKeysRetiredBefore(db *sql.DB, before time.Time) error {
+	_, err := db.Exec("DELETE FROM signing_keys WHERE retired_at IS NOT NULL AND retired_at<?", before.UTC())
+	return err
+}
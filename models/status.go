@@ -0,0 +1,258 @@
+package models
+
+import (
+	"database/sql"
+	"math"
+	"time"
+)
+
+// StatusHistoryDays - count of days in uptime history of status page
+const StatusHistoryDays = 30
+
+// Overall statuses of status page
+const (
+	PageOperational = "operational"
+	PageDegraded    = "degraded"
+	PageOutage      = "outage"
+)
+
+// StatusChange - transition of service status, used for uptime history
+type StatusChange struct {
+	ServiceID int       `json:"service_id"`
+	Status    Status    `json:"status"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// StatusPage - public status of services
+type StatusPage struct {
+	Status    string            `json:"status"` // operational, degraded or outage
+	UpdatedAt time.Time         `json:"updated_at"`
+	Services  []*PublicService  `json:"services"`
+	Incidents []*PublicIncident `json:"incidents"`
+}
+
+// PublicService - service on status page, without link and token
+type PublicService struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Status     Status `json:"status"`
+	StatusName string `json:"status_name"`
+	// uptime percents, null if there is no status history for period
+	UptimeDay   *float64       `json:"uptime_day"`
+	UptimeWeek  *float64       `json:"uptime_week"`
+	UptimeMonth *float64       `json:"uptime_month"`
+	History     []*DailyUptime `json:"history"` // uptime by days, oldest first
+}
+
+// DailyUptime - service uptime percent in one day
+type DailyUptime struct {
+	Date   string   `json:"date"` // day in UTC, YYYY-MM-DD
+	Uptime *float64 `json:"uptime"`
+}
+
+// PublicIncident - public service which is down now, without failure reason
+type PublicIncident struct {
+	ID           int        `json:"id,omitempty"` // id of open incident, 0 if service is degraded without incident
+	Acknowledged bool       `json:"acknowledged"`
+	ServiceID    int        `json:"service_id"`
+	ServiceName  string     `json:"service_name"`
+	Status       Status     `json:"status"`
+	StatusName   string     `json:"status_name"`
+	Since        *time.Time `json:"since,omitempty"`
+}
+
+// SaveStatusChange - save transition of service status
+func SaveStatusChange(db *sql.DB, serviceID int, status Status, changedAt time.Time) error {
+	_, err := db.Exec(
+		"INSERT INTO service_status_changes (service_id, status, changed_at) VALUES (?,?,?)",
+		serviceID,
+		status,
+		changedAt.UTC(),
+	)
+	return err
+}
+
+// GetStatusChanges - status transitions of service since time, ordered by time,
+// the last transition before since is included as initial status of period
+func (s *Service) GetStatusChanges(db *sql.DB, since time.Time) ([]StatusChange, error) {
+	var changes []StatusChange
+
+	var initial = StatusChange{ServiceID: s.ID}
+	err := db.QueryRow(
+		"SELECT status, changed_at FROM service_status_changes "+
+			"WHERE service_id=? AND changed_at<? ORDER BY changed_at DESC, id DESC LIMIT 1",
+		s.ID,
+		since.UTC(),
+	).Scan(&initial.Status, &initial.ChangedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if err == nil {
+		changes = append(changes, initial)
+	}
+
+	rows, err := db.Query(
+		"SELECT status, changed_at FROM service_status_changes "+
+			"WHERE service_id=? AND changed_at>=? ORDER BY changed_at ASC, id ASC",
+		s.ID,
+		since.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change = StatusChange{ServiceID: s.ID}
+		err = rows.Scan(&change.Status, &change.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// Uptime - percent of time from `from` to `to` when service was alive,
+// time before first known status is not counted, false if there is no status for period
+func Uptime(changes []StatusChange, from, to time.Time) (float64, bool) {
+	var up, known time.Duration
+	for i, change := range changes {
+		var start, end = change.ChangedAt, to
+		if i+1 < len(changes) {
+			end = changes[i+1].ChangedAt
+		}
+
+		if start.Before(from) {
+			start = from
+		}
+
+		if end.After(to) {
+			end = to
+		}
+
+		if !end.After(start) {
+			continue
+		}
+
+		known += end.Sub(start)
+		if change.Status.IsValid() && !change.Status.IsDown() {
+			up += end.Sub(start)
+		}
+	}
+
+	if known == 0 {
+		return 0, false
+	}
+
+	return math.Round(10000*float64(up)/float64(known)) / 100, true
+}
+
+// GetStatusPage - status of public not deleted services with uptime history for now
+func GetStatusPage(db *sql.DB, now time.Time) (*StatusPage, error) {
+	rows, err := db.Query("SELECT id, name, status FROM services WHERE deleted=0 AND public=1 ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var page = &StatusPage{
+		Status:    PageOperational,
+		UpdatedAt: now,
+		Services:  []*PublicService{},
+		Incidents: []*PublicIncident{},
+	}
+
+	for rows.Next() {
+		var service = &PublicService{}
+		err = rows.Scan(&service.ID, &service.Name, &service.Status)
+		if err != nil {
+			return nil, err
+		}
+
+		service.StatusName = service.Status.String()
+		page.Services = append(page.Services, service)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var today = now.UTC().Truncate(24 * time.Hour)
+	var historyStart = today.AddDate(0, 0, -(StatusHistoryDays - 1))
+	var monthAgo = now.AddDate(0, 0, -StatusHistoryDays)
+	if monthAgo.Before(historyStart) {
+		historyStart = monthAgo
+	}
+
+	for _, service := range page.Services {
+		changes, err := (&Service{ID: service.ID}).GetStatusChanges(db, historyStart)
+		if err != nil {
+			return nil, err
+		}
+
+		service.UptimeDay = uptimePtr(changes, now.Add(-24*time.Hour), now)
+		service.UptimeWeek = uptimePtr(changes, now.AddDate(0, 0, -7), now)
+		service.UptimeMonth = uptimePtr(changes, monthAgo, now)
+
+		service.History = make([]*DailyUptime, 0, StatusHistoryDays)
+		for day := today.AddDate(0, 0, -(StatusHistoryDays - 1)); !day.After(today); day = day.AddDate(0, 0, 1) {
+			var dayEnd = day.Add(24 * time.Hour)
+			if dayEnd.After(now) {
+				dayEnd = now
+			}
+
+			service.History = append(service.History, &DailyUptime{
+				Date:   day.Format("2006-01-02"),
+				Uptime: uptimePtr(changes, day, dayEnd),
+			})
+		}
+
+		if !service.Status.IsDown() {
+			continue
+		}
+
+		var incident = &PublicIncident{
+			ServiceID:   service.ID,
+			ServiceName: service.Name,
+			Status:      service.Status,
+			StatusName:  service.StatusName,
+		}
+		if len(changes) > 0 && changes[len(changes)-1].Status == service.Status {
+			since := changes[len(changes)-1].ChangedAt
+			incident.Since = &since
+		}
+
+		open, err := (&Service{ID: service.ID}).GetOpenIncident(db)
+		if err != nil {
+			return nil, err
+		}
+
+		if open != nil {
+			incident.ID = open.ID
+			incident.Acknowledged = open.AcknowledgedAt != nil
+			incident.Since = &open.StartedAt
+		}
+		page.Incidents = append(page.Incidents, incident)
+
+		if service.Status == Dead {
+			page.Status = PageOutage
+		} else if page.Status != PageOutage {
+			page.Status = PageDegraded
+		}
+	}
+
+	return page, nil
+}
+
+func uptimePtr(changes []StatusChange, from, to time.Time) *float64 {
+	uptime, ok := Uptime(changes, from, to)
+	if !ok {
+		return nil
+	}
+
+	return &uptime
+}
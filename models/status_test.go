@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUptime(t *testing.T) {
+	var from = time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	var to = from.Add(10 * time.Hour)
+
+	tests := []struct {
+		name    string
+		changes []StatusChange
+		want    float64
+		wantOk  bool
+	}{
+		{
+			name:   "no history",
+			wantOk: false,
+		},
+		{
+			name:    "alive before period",
+			changes: []StatusChange{{Status: Alive, ChangedAt: from.Add(-time.Hour)}},
+			want:    100,
+			wantOk:  true,
+		},
+		{
+			name: "dead one hour",
+			changes: []StatusChange{
+				{Status: Alive, ChangedAt: from.Add(-time.Hour)},
+				{Status: Dead, ChangedAt: from.Add(2 * time.Hour)},
+				{Status: Alive, ChangedAt: from.Add(3 * time.Hour)},
+			},
+			want:   90,
+			wantOk: true,
+		},
+		{
+			name: "history started in period",
+			changes: []StatusChange{
+				{Status: Alive, ChangedAt: from.Add(5 * time.Hour)},
+				{Status: Degraded, ChangedAt: from.Add(9 * time.Hour)},
+			},
+			want:   80,
+			wantOk: true,
+		},
+		{
+			name: "warning is up",
+			changes: []StatusChange{
+				{Status: Warning, ChangedAt: from},
+			},
+			want:   100,
+			wantOk: true,
+		},
+		{
+			name:    "history started after period",
+			changes: []StatusChange{{Status: Dead, ChangedAt: to.Add(time.Hour)}},
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Uptime(tt.changes, from, to)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Uptime() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NotificationEvent - kind of event which service recipients are notified about
+type NotificationEvent string
+
+const (
+	EventDown         NotificationEvent = "down"
+	EventRecovered    NotificationEvent = "recovered"
+	EventCertExpiring NotificationEvent = "cert_expiring"
+	EventEscalation   NotificationEvent = "escalation" // incident is not acknowledged after escalation level delay
+)
+
+// IsValid - true if event is known
+func (e NotificationEvent) IsValid() bool {
+	switch e {
+	case EventDown, EventRecovered, EventCertExpiring, EventEscalation:
+		return true
+	}
+
+	return false
+}
+
+// ChannelEmail - email notifications, used only as channel of templates
+const ChannelEmail ChannelType = "email"
+
+// NotificationTemplate - subject and body templates of channel notifications about event,
+// email body is html/template, other templates are text/template with NotificationData
+type NotificationTemplate struct {
+	ID      int               `json:"id" db:"id"`
+	Channel ChannelType       `json:"channel" db:"channel"`
+	Event   NotificationEvent `json:"event" db:"event"`
+	Subject string            `json:"subject" db:"subject"`
+	Body    string            `json:"body" db:"body"`
+}
+
+// NotificationData - data of notification templates
+type NotificationData struct {
+	Event    NotificationEvent
+	Service  *Service
+	Incident *Incident    // nil if service has no incident
+	Result   *CheckResult // nil if notification is not caused by health check
+	Message  string       // default text of notification
+	Time     time.Time
+}
+
+func (t *NotificationTemplate) Validate() error {
+	if t.Channel != ChannelEmail && !t.Channel.IsValid() {
+		return fmt.Errorf("invalid template channel: %s", t.Channel)
+	}
+
+	if !t.Event.IsValid() {
+		return fmt.Errorf("invalid template event: %s", t.Event)
+	}
+
+	if t.Body == "" {
+		return fmt.Errorf("empty template body")
+	}
+
+	_, _, err := t.Parse()
+
+	return err
+}
+
+// Parse - parse subject and body templates
+func (t *NotificationTemplate) Parse() (*template.Template, Executor, error) {
+	subject, err := template.New("subject").Parse(t.Subject)
+	if err != nil {
+		return nil, nil, fmt.Errorf("subject template: %v", err)
+	}
+
+	var body Executor
+	if t.Channel == ChannelEmail {
+		body, err = htmltemplate.New("body").Parse(t.Body)
+	} else {
+		body, err = template.New("body").Parse(t.Body)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("body template: %v", err)
+	}
+
+	return subject, body, nil
+}
+
+// Executor - common interface of text and html templates
+type Executor interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+func (t *NotificationTemplate) IsExistByName(db *sql.DB) (error, bool) {
+	var id int
+	err := db.QueryRow(
+		"SELECT id FROM notification_templates WHERE channel=? AND event=?", t.Channel, t.Event,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, false
+	}
+
+	if err != nil {
+		return err, false
+	}
+
+	return nil, true
+}
+
+func (t *NotificationTemplate) Count(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT count(id) FROM notification_templates").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (t *NotificationTemplate) Save(db *sql.DB) error {
+	result, err := db.Exec(
+		"INSERT INTO notification_templates (channel, event, subject, body) VALUES (?,?,?,?)",
+		t.Channel,
+		t.Event,
+		t.Subject,
+		t.Body,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	t.ID = int(lastInsertID)
+
+	return nil
+}
+
+// GetByName - template by name in format channel/event, see GetName
+func (t *NotificationTemplate) GetByName(db *sql.DB, name string) error {
+	var channel, event string
+	if i := strings.Index(name, "/"); i >= 0 {
+		channel, event = name[:i], name[i+1:]
+	}
+
+	return GetNotificationTemplate(db, ChannelType(channel), NotificationEvent(event), t)
+}
+
+func (t *NotificationTemplate) GetByID(db *sql.DB, id int64) error {
+	return db.QueryRow(
+		"SELECT id, channel, event, subject, body FROM notification_templates WHERE id=?", id,
+	).Scan(&t.ID, &t.Channel, &t.Event, &t.Subject, &t.Body)
+}
+
+func (t *NotificationTemplate) Pagination(db *sql.DB, start int, end int) ([]Model, error) {
+	rows, err := db.Query(
+		"SELECT id, channel, event, subject, body FROM notification_templates ORDER BY id ASC LIMIT ?, ?",
+		start, end-start,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Model
+	for rows.Next() {
+		var tmpl = &NotificationTemplate{}
+		err = rows.Scan(&tmpl.ID, &tmpl.Channel, &tmpl.Event, &tmpl.Subject, &tmpl.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, tmpl)
+	}
+
+	return result, rows.Err()
+}
+
+func (t *NotificationTemplate) Update(db *sql.DB, id int) error {
+	res, err := db.Exec(
+		"UPDATE notification_templates SET channel=?, event=?, subject=?, body=? WHERE id=?",
+		t.Channel,
+		t.Event,
+		t.Subject,
+		t.Body,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (t *NotificationTemplate) Delete(db *sql.DB, id int) error {
+	res, err := db.Exec("DELETE FROM notification_templates WHERE id=?", id)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (t *NotificationTemplate) GetID() int          { return t.ID }
+func (t *NotificationTemplate) SetID(id int)        { t.ID = id }
+func (t *NotificationTemplate) GetName() string     { return string(t.Channel) + "/" + string(t.Event) }
+func (t *NotificationTemplate) SetName(name string) {}
+func (t *NotificationTemplate) SetDeleted()         {}
+func (t *NotificationTemplate) IsDeleted() bool     { return false }
+
+// GetNotificationTemplate - fill tmpl by template of channel notifications about event,
+// sql.ErrNoRows if template is not customized
+func GetNotificationTemplate(db *sql.DB, channel ChannelType, event NotificationEvent, tmpl *NotificationTemplate) error {
+	return db.QueryRow(
+		"SELECT id, channel, event, subject, body FROM notification_templates WHERE channel=? AND event=?",
+		channel, event,
+	).Scan(&tmpl.ID, &tmpl.Channel, &tmpl.Event, &tmpl.Subject, &tmpl.Body)
+}
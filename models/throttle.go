@@ -0,0 +1,97 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LoginThrottle - failed login attempts of one username or client address
+type LoginThrottle struct {
+	Key           string     `json:"key" db:"key"` // "user:<username>" or "ip:<address>"
+	Failures      int        `json:"failures" db:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at" db:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+}
+
+// UserThrottleKey - throttle key of username
+func UserThrottleKey(username string) string {
+	return "user:" + username
+}
+
+// IPThrottleKey - throttle key of client address
+func IPThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// GetLoginThrottle - failed attempts of key, empty throttle if key has no failures
+func GetLoginThrottle(db *sql.DB, key string) (*LoginThrottle, error) {
+	var throttle = &LoginThrottle{Key: key}
+	err := db.QueryRow(
+		"SELECT failures, last_failure_at, locked_until FROM login_throttles WHERE key=?", key,
+	).Scan(&throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+	if err == sql.ErrNoRows {
+		return throttle, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return throttle, nil
+}
+
+// AddLoginFailure - count failure of key at now in one statement, so concurrent failures are not lost,
+// failures before expired lockout are forgotten and key is locked until lockedUntil when failures reach limit.
+// Stored throttle is returned with locked true if this failure locked the key
+func AddLoginFailure(db *sql.DB, key string, limit int, now, lockedUntil time.Time) (*LoginThrottle, bool, error) {
+	now, lockedUntil = now.UTC(), lockedUntil.UTC()
+
+	// failure is counted and read in one transaction, other writers wait for commit
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// update expressions use values of row before update
+	_, err = tx.Exec(`
+INSERT INTO login_throttles (key, failures, last_failure_at, locked_until)
+VALUES (?1, 1, ?2, CASE WHEN 1 >= ?3 THEN ?4 END)
+ON CONFLICT(key) DO UPDATE SET
+	failures = CASE WHEN locked_until <= ?2 THEN 1 ELSE failures + 1 END,
+	locked_until = CASE
+		WHEN locked_until > ?2 THEN locked_until
+		WHEN locked_until <= ?2 THEN CASE WHEN 1 >= ?3 THEN ?4 END
+		WHEN failures + 1 >= ?3 THEN ?4
+	END,
+	last_failure_at = ?2`,
+		key, now, limit, lockedUntil,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var throttle = &LoginThrottle{Key: key}
+	err = tx.QueryRow(
+		"SELECT failures, last_failure_at, locked_until FROM login_throttles WHERE key=?", key,
+	).Scan(&throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	// key is locked by failure which reached limit, later failures only extend the count
+	if limit < 1 {
+		limit = 1
+	}
+
+	return throttle, throttle.LockedUntil != nil && throttle.Failures == limit, nil
+}
+
+// ResetLoginThrottle - forget failed attempts of key, e.g. after successful login or unlock by admin
+func ResetLoginThrottle(db *sql.DB, key string) error {
+	_, err := db.Exec("DELETE FROM login_throttles WHERE key=?", key)
+	return err
+}
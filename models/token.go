@@ -1,8 +1,52 @@
 package models
 
-import "github.com/dgrijalva/jwt-go"
+import (
+	"context"
+
+	"github.com/dgrijalva/jwt-go"
+)
 
 type Token struct {
 	UserId uint64
+	Role   Role // role of user at login, permissions of request are checked by it
+	// TwoFactor - token of accepted password waiting for two-factor code, it does not authorize requests
+	TwoFactor bool `json:",omitempty"`
+	// PasswordChange - password of user is reset by admin, token authorizes only password change and logout
+	PasswordChange bool `json:",omitempty"`
 	jwt.StandardClaims
+
+	APIKeyID int     `json:"-"` // api key authorized request, 0 if request is authorized by access token
+	Scopes   []Scope `json:"-"` // scopes of api key
+}
+
+// Can - true if role of user allows action on resource, request authorized by api key needs scope of it too
+func (t *Token) Can(resource Resource, action Action) bool {
+	if !t.Role.Can(resource, action) {
+		return false
+	}
+
+	if t.APIKeyID == 0 {
+		return true
+	}
+
+	for _, scope := range t.Scopes {
+		if scope.Resource == resource && scope.Action == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+type tokenCtxKey struct{}
+
+// ContextWithToken - context with claims of authorized user
+func ContextWithToken(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, tokenCtxKey{}, token)
+}
+
+// TokenFromContext - claims of authorized user, nil if request is not authorized
+func TokenFromContext(ctx context.Context) *Token {
+	token, _ := ctx.Value(tokenCtxKey{}).(*Token)
+	return token
 }
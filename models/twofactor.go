@@ -0,0 +1,130 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TwoFactor - TOTP authenticator of user, login needs its code once enrollment is confirmed
+type TwoFactor struct {
+	UserID      int        `json:"user_id" db:"user_id"`
+	Secret      string     `json:"-" db:"secret"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"` // nil until first valid code
+	LastCounter int64      `json:"-" db:"last_counter"`                      // time counter of last accepted code
+}
+
+// IsEnabled - enrollment is confirmed by code
+func (tf *TwoFactor) IsEnabled() bool {
+	return tf.ConfirmedAt != nil
+}
+
+// GetTwoFactor - authenticator of user, sql.ErrNoRows if user is not enrolled
+func GetTwoFactor(db *sql.DB, userID int) (*TwoFactor, error) {
+	var tf = &TwoFactor{}
+	err := db.QueryRow(
+		"SELECT user_id, secret, created_at, confirmed_at, last_counter FROM two_factors WHERE user_id=?", userID,
+	).Scan(&tf.UserID, &tf.Secret, &tf.CreatedAt, &tf.ConfirmedAt, &tf.LastCounter)
+	if err != nil {
+		return nil, err
+	}
+
+	return tf, nil
+}
+
+// Save - replace authenticator of user, new enrollment is not confirmed
+func (tf *TwoFactor) Save(db *sql.DB) error {
+	_, err := db.Exec(
+		"INSERT OR REPLACE INTO two_factors (user_id, secret, created_at, confirmed_at, last_counter) VALUES (?,?,?,NULL,0)",
+		tf.UserID, tf.Secret, tf.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	tf.ConfirmedAt = nil
+	tf.LastCounter = 0
+
+	return nil
+}
+
+// Confirm - enable authenticator after first valid code
+func (tf *TwoFactor) Confirm(db *sql.DB, counter int64, at time.Time) error {
+	at = at.UTC()
+	_, err := db.Exec(
+		"UPDATE two_factors SET confirmed_at=?, last_counter=? WHERE user_id=?", at, counter, tf.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	tf.ConfirmedAt = &at
+	tf.LastCounter = counter
+
+	return nil
+}
+
+// UseCounter - remember time counter of accepted code, the same code is not accepted again
+func (tf *TwoFactor) UseCounter(db *sql.DB, counter int64) error {
+	result, err := db.Exec(
+		"UPDATE two_factors SET last_counter=? WHERE user_id=? AND last_counter<?", counter, tf.UserID, counter,
+	)
+	if err != nil {
+		return err
+	}
+
+	// concurrent login already used code
+	if count, err := result.RowsAffected(); err != nil || count == 0 {
+		return sql.ErrNoRows
+	}
+
+	tf.LastCounter = counter
+
+	return nil
+}
+
+// DeleteTwoFactor - remove authenticator and recovery codes of user
+func DeleteTwoFactor(db *sql.DB, userID int) error {
+	_, err := db.Exec("DELETE FROM two_factors WHERE user_id=?", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("DELETE FROM recovery_codes WHERE user_id=?", userID)
+	return err
+}
+
+// SaveRecoveryCodes - replace recovery codes of user by hashes of new ones
+func SaveRecoveryCodes(db *sql.DB, userID int, codes []string) error {
+	_, err := db.Exec("DELETE FROM recovery_codes WHERE user_id=?", userID)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		_, err = db.Exec("INSERT INTO recovery_codes (user_id, code_hash) VALUES (?,?)", userID, HashToken(code))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode - true if code is unused recovery code of user, code is not accepted again
+func UseRecoveryCode(db *sql.DB, userID int, code string, at time.Time) (bool, error) {
+	result, err := db.Exec(
+		"UPDATE recovery_codes SET used_at=? WHERE user_id=? AND code_hash=? AND used_at IS NULL",
+		at.UTC(), userID, HashToken(code),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
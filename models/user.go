@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -14,15 +15,57 @@ const (
 	_ Role = iota
 	Admin
 	SuperAdmin
+	Viewer // read-only access to services and incidents
 )
 
+func (r Role) String() string {
+	switch r {
+	case Admin:
+		return "admin"
+	case SuperAdmin:
+		return "superadmin"
+	case Viewer:
+		return "viewer"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseRole - role by name, 0 if name is unknown
+func ParseRole(name string) Role {
+	for _, role := range []Role{Admin, SuperAdmin, Viewer} {
+		if strings.EqualFold(role.String(), name) {
+			return role
+		}
+	}
+
+	return 0
+}
+
 type User struct {
-	ID       int    `json:"id";db:"id"`
-	Username string `json:"username";db:"username"`
-	Role     Role   `json:"role";db:"role"`
+	ID       int    `json:"id" db:"id"`
+	Username string `json:"username" db:"username"`
+	Role     Role   `json:"role" db:"role"`
 	Password string `db:"password"`
 	Token    string `json:"token"`
-	Deleted  int    `json:"deleted";db:"deleted"`
+	Deleted  int    `json:"deleted" db:"deleted"`
+	// PasswordChangeRequired - password is reset by admin, user must change it before other requests
+	PasswordChangeRequired bool `json:"password_change_required" db:"password_change_required"`
+}
+
+// passwordCost - bcrypt cost of new password hashes
+var passwordCost = bcrypt.DefaultCost
+
+// SetPasswordCost - bcrypt cost of new password hashes, it is limited by bcrypt min and max cost
+func SetPasswordCost(cost int) {
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.MinCost
+	}
+	if cost > bcrypt.MaxCost {
+		cost = bcrypt.MaxCost
+	}
+
+	passwordCost = cost
 }
 
 func (u *User) Validate() error {
@@ -33,12 +76,7 @@ func (u *User) Validate() error {
 		return fmt.Errorf("invalid password")
 	}
 
-	switch u.Role {
-	case Admin:
-		break
-	case SuperAdmin:
-		break
-	default:
+	if !u.Role.IsValid() {
 		return fmt.Errorf("invalid role")
 	}
 
@@ -60,7 +98,7 @@ func (u *User) IsExistByName(db *sql.DB) (error, bool) {
 }
 
 func (u *User) Save(db *sql.DB) error {
-	passwd, err := bcrypt.GenerateFromPassword([]byte(u.Password), 8)
+	passwd, err := bcrypt.GenerateFromPassword([]byte(u.Password), passwordCost)
 	if err != nil {
 		return err
 	}
@@ -85,6 +123,43 @@ func (u *User) Save(db *sql.DB) error {
 	return nil
 }
 
+// SetPassword - replace password of user, user with required change may only change it
+func (u *User) SetPassword(db *sql.DB, password string, changeRequired bool) error {
+	passwd, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return err
+	}
+
+	res, err := db.Exec(
+		"UPDATE users SET password=?, password_change_required=? WHERE id=?",
+		string(passwd), changeRequired, u.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	u.Password = string(passwd)
+	u.PasswordChangeRequired = changeRequired
+
+	return nil
+}
+
+// IsExternal - true if password of user is checked by single sign-on provider or directory
+func (u *User) IsExternal(db *sql.DB) (bool, error) {
+	var subject, dn sql.NullString
+	err := db.QueryRow("SELECT oidc_subject, ldap_dn FROM users WHERE id=?", u.ID).Scan(&subject, &dn)
+	return subject.String != "" || dn.String != "", err
+}
+
 func (u *User) Count(db *sql.DB) (int, error) {
 	var count int
 	var err = db.QueryRow("SELECT count(id) FROM users").Scan(&count)
@@ -96,30 +171,76 @@ func (u *User) Count(db *sql.DB) (int, error) {
 }
 
 func (u *User) GetByName(db *sql.DB, username string) error {
-	return db.QueryRow("SELECT id, username, password, role, deleted FROM users WHERE username=?", username).Scan(
+	return db.QueryRow("SELECT id, username, password, role, deleted, password_change_required FROM users WHERE username=?", username).Scan(
 		&u.ID,
 		&u.Username,
 		&u.Password,
 		&u.Role,
 		&u.Deleted,
+		&u.PasswordChangeRequired,
 	)
 }
 
 func (u *User) GetByID(db *sql.DB, id int64) error {
-	return db.QueryRow("SELECT id, username, password, role, deleted FROM users WHERE id=?", id).Scan(
+	return db.QueryRow("SELECT id, username, password, role, deleted, password_change_required FROM users WHERE id=?", id).Scan(
 		&u.ID,
 		&u.Username,
 		&u.Password,
 		&u.Role,
 		&u.Deleted,
+		&u.PasswordChangeRequired,
 	)
 }
 
+// GetUserByOIDCSubject - user linked to subject of single sign-on provider
+func GetUserByOIDCSubject(db *sql.DB, subject string) (*User, error) {
+	var u = &User{}
+	err := db.QueryRow("SELECT id, username, password, role, deleted, password_change_required FROM users WHERE oidc_subject=?", subject).Scan(
+		&u.ID,
+		&u.Username,
+		&u.Password,
+		&u.Role,
+		&u.Deleted,
+		&u.PasswordChangeRequired,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// OIDCSubject - subject of single sign-on provider linked to user, empty for local user
+func (u *User) OIDCSubject(db *sql.DB) (string, error) {
+	var subject sql.NullString
+	err := db.QueryRow("SELECT oidc_subject FROM users WHERE id=?", u.ID).Scan(&subject)
+	return subject.String, err
+}
+
+// SetOIDCSubject - link user to subject of single sign-on provider
+func (u *User) SetOIDCSubject(db *sql.DB, subject string) error {
+	_, err := db.Exec("UPDATE users SET oidc_subject=? WHERE id=?", subject, u.ID)
+	return err
+}
+
+// LDAPDN - directory entry linked to user, empty for local user
+func (u *User) LDAPDN(db *sql.DB) (string, error) {
+	var dn sql.NullString
+	err := db.QueryRow("SELECT ldap_dn FROM users WHERE id=?", u.ID).Scan(&dn)
+	return dn.String, err
+}
+
+// SetLDAPDN - link user to directory entry, its password is checked by directory since then
+func (u *User) SetLDAPDN(db *sql.DB, dn string) error {
+	_, err := db.Exec("UPDATE users SET ldap_dn=? WHERE id=?", dn, u.ID)
+	return err
+}
+
 func (u *User) Pagination(db *sql.DB, start, end int) ([]Model, error) {
 	var result []Model
 
 	raws, err := db.Query(
-		"SELECT id, username, role, deleted FROM users ORDER BY id ASC limit ?, ?", start, end)
+		"SELECT id, username, role, deleted, password_change_required FROM users ORDER BY id ASC limit ?, ?", start, end)
 	if err != nil {
 		return result, err
 	}
@@ -127,7 +248,7 @@ func (u *User) Pagination(db *sql.DB, start, end int) ([]Model, error) {
 	for raws.Next() {
 		var user = &User{}
 
-		err = raws.Scan(&user.ID, &user.Username, &user.Role, &user.Deleted)
+		err = raws.Scan(&user.ID, &user.Username, &user.Role, &user.Deleted, &user.PasswordChangeRequired)
 		if err != nil {
 			return result, err
 		}
@@ -138,7 +259,17 @@ func (u *User) Pagination(db *sql.DB, start, end int) ([]Model, error) {
 	return result, nil
 }
 
+// Update - update username and role of user, sessions of user end if role is changed
+// because access tokens carry role of user
 func (u *User) Update(db *sql.DB, id int) error {
+	var role Role
+	if u.Role != Role(0) {
+		err := db.QueryRow("SELECT role FROM users WHERE id=?", id).Scan(&role)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+
 	query, args := u.buildUserUpdateQuery(id)
 	res, err := db.Exec(
 		query, args...,
@@ -156,6 +287,10 @@ func (u *User) Update(db *sql.DB, id int) error {
 		return fmt.Errorf("user with id %d not updated", id)
 	}
 
+	if u.Role != Role(0) && u.Role != role {
+		return RevokeUserSessions(db, id, time.Now())
+	}
+
 	return nil
 }
 
@@ -174,7 +309,12 @@ func (u *User) Delete(db *sql.DB, id int) error {
 		return fmt.Errorf("user with id %v not deleted", id)
 	}
 
-	return nil
+	err = RevokeUserSessions(db, id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return DeleteTwoFactor(db, id)
 }
 
 func (u *User) GetID() int {
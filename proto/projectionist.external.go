@@ -13,3 +13,35 @@ func (lr *LoginRequest) Validate() error {
 
 	return nil
 }
+
+func (rr *RefreshRequest) Validate() error {
+	if rr.RefreshToken == "" {
+		return fmt.Errorf("Refresh token is required")
+	}
+
+	return nil
+}
+
+func (r *ChangePasswordRequest) Validate() error {
+	if r.CurrentPassword == "" {
+		return fmt.Errorf("Current password is required")
+	}
+
+	if r.NewPassword == "" {
+		return fmt.Errorf("New password is required")
+	}
+
+	return nil
+}
+
+func (r *LoginTwoFactorRequest) Validate() error {
+	if r.Token == "" {
+		return fmt.Errorf("Token is required")
+	}
+
+	if r.Code == "" {
+		return fmt.Errorf("Code is required")
+	}
+
+	return nil
+}
@@ -31,18 +31,21 @@ const (
 	UserRole_Empty      UserRole = 0
 	UserRole_Admin      UserRole = 1
 	UserRole_SuperAdmin UserRole = 2
+	UserRole_Viewer     UserRole = 3
 )
 
 var UserRole_name = map[int32]string{
 	0: "Empty",
 	1: "Admin",
 	2: "SuperAdmin",
+	3: "Viewer",
 }
 
 var UserRole_value = map[string]int32{
 	"Empty":      0,
 	"Admin":      1,
 	"SuperAdmin": 2,
+	"Viewer":     3,
 }
 
 func (x UserRole) String() string {
@@ -81,6 +84,67 @@ func (Deleted) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_9cc8a487c9186292, []int{1}
 }
 
+// Maintenance
+type MaintenanceMode int32
+
+const (
+	MaintenanceMode_MaintenanceEmpty    MaintenanceMode = 0
+	MaintenanceMode_MaintenancePause    MaintenanceMode = 1
+	MaintenanceMode_MaintenanceSuppress MaintenanceMode = 2
+)
+
+var MaintenanceMode_name = map[int32]string{
+	0: "MaintenanceEmpty",
+	1: "MaintenancePause",
+	2: "MaintenanceSuppress",
+}
+
+var MaintenanceMode_value = map[string]int32{
+	"MaintenanceEmpty":    0,
+	"MaintenancePause":    1,
+	"MaintenanceSuppress": 2,
+}
+
+func (x MaintenanceMode) String() string {
+	return proto.EnumName(MaintenanceMode_name, int32(x))
+}
+
+func (MaintenanceMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{2}
+}
+
+// Service check
+type CheckStatus int32
+
+const (
+	CheckStatus_CheckEmpty   CheckStatus = 0
+	CheckStatus_CheckSuccess CheckStatus = 1
+	CheckStatus_CheckFailure CheckStatus = 2
+	CheckStatus_CheckTimeout CheckStatus = 3
+)
+
+var CheckStatus_name = map[int32]string{
+	0: "CheckEmpty",
+	1: "CheckSuccess",
+	2: "CheckFailure",
+	3: "CheckTimeout",
+}
+
+var CheckStatus_value = map[string]int32{
+	"CheckEmpty":   0,
+	"CheckSuccess": 1,
+	"CheckFailure": 2,
+	"CheckTimeout": 3,
+}
+
+func (x CheckStatus) String() string {
+	return proto.EnumName(CheckStatus_name, int32(x))
+}
+
+func (CheckStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{3}
+}
+
 // User
 type User struct {
 	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -320,11 +384,16 @@ func (m *LoginRequest) GetPassword() string {
 }
 
 type LoginResponse struct {
-	User                 *User            `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Meta                 *DefaultResponse `protobuf:"bytes,2,opt,name=meta,proto3" json:"meta,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	User                   *User            `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Meta                   *DefaultResponse `protobuf:"bytes,2,opt,name=meta,proto3" json:"meta,omitempty"`
+	RefreshToken           string           `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ExpiresAt              int64            `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	TwoFactorRequired      bool             `protobuf:"varint,5,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	TwoFactorToken         string           `protobuf:"bytes,6,opt,name=two_factor_token,json=twoFactorToken,proto3" json:"two_factor_token,omitempty"`
+	PasswordChangeRequired bool             `protobuf:"varint,7,opt,name=password_change_required,json=passwordChangeRequired,proto3" json:"password_change_required,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{}         `json:"-"`
+	XXX_unrecognized       []byte           `json:"-"`
+	XXX_sizecache          int32            `json:"-"`
 }
 
 func (m *LoginResponse) Reset()         { *m = LoginResponse{} }
@@ -366,228 +435,2401 @@ func (m *LoginResponse) GetMeta() *DefaultResponse {
 	return nil
 }
 
-// Default
-type DefaultResponse struct {
-	Status               bool     `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
-	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Code                 int32    `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+func (m *LoginResponse) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+func (m *LoginResponse) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *LoginResponse) GetTwoFactorRequired() bool {
+	if m != nil {
+		return m.TwoFactorRequired
+	}
+	return false
+}
+
+func (m *LoginResponse) GetTwoFactorToken() string {
+	if m != nil {
+		return m.TwoFactorToken
+	}
+	return ""
+}
+
+func (m *LoginResponse) GetPasswordChangeRequired() bool {
+	if m != nil {
+		return m.PasswordChangeRequired
+	}
+	return false
+}
+
+type LoginTwoFactorRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DefaultResponse) Reset()         { *m = DefaultResponse{} }
-func (m *DefaultResponse) String() string { return proto.CompactTextString(m) }
-func (*DefaultResponse) ProtoMessage()    {}
-func (*DefaultResponse) Descriptor() ([]byte, []int) {
+func (m *LoginTwoFactorRequest) Reset()         { *m = LoginTwoFactorRequest{} }
+func (m *LoginTwoFactorRequest) String() string { return proto.CompactTextString(m) }
+func (*LoginTwoFactorRequest) ProtoMessage()    {}
+func (*LoginTwoFactorRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_9cc8a487c9186292, []int{5}
 }
 
-func (m *DefaultResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DefaultResponse.Unmarshal(m, b)
+func (m *LoginTwoFactorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoginTwoFactorRequest.Unmarshal(m, b)
 }
-func (m *DefaultResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DefaultResponse.Marshal(b, m, deterministic)
+func (m *LoginTwoFactorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoginTwoFactorRequest.Marshal(b, m, deterministic)
 }
-func (m *DefaultResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DefaultResponse.Merge(m, src)
+func (m *LoginTwoFactorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoginTwoFactorRequest.Merge(m, src)
 }
-func (m *DefaultResponse) XXX_Size() int {
-	return xxx_messageInfo_DefaultResponse.Size(m)
+func (m *LoginTwoFactorRequest) XXX_Size() int {
+	return xxx_messageInfo_LoginTwoFactorRequest.Size(m)
 }
-func (m *DefaultResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_DefaultResponse.DiscardUnknown(m)
+func (m *LoginTwoFactorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoginTwoFactorRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DefaultResponse proto.InternalMessageInfo
+var xxx_messageInfo_LoginTwoFactorRequest proto.InternalMessageInfo
 
-func (m *DefaultResponse) GetStatus() bool {
+func (m *LoginTwoFactorRequest) GetToken() string {
 	if m != nil {
-		return m.Status
+		return m.Token
 	}
-	return false
+	return ""
 }
 
-func (m *DefaultResponse) GetMessage() string {
+func (m *LoginTwoFactorRequest) GetCode() string {
 	if m != nil {
-		return m.Message
+		return m.Code
 	}
 	return ""
 }
 
-func (m *DefaultResponse) GetCode() int32 {
+type RefreshRequest struct {
+	RefreshToken         string   `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefreshRequest) Reset()         { *m = RefreshRequest{} }
+func (m *RefreshRequest) String() string { return proto.CompactTextString(m) }
+func (*RefreshRequest) ProtoMessage()    {}
+func (*RefreshRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{6}
+}
+
+func (m *RefreshRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefreshRequest.Unmarshal(m, b)
+}
+func (m *RefreshRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefreshRequest.Marshal(b, m, deterministic)
+}
+func (m *RefreshRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefreshRequest.Merge(m, src)
+}
+func (m *RefreshRequest) XXX_Size() int {
+	return xxx_messageInfo_RefreshRequest.Size(m)
+}
+func (m *RefreshRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefreshRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefreshRequest proto.InternalMessageInfo
+
+func (m *RefreshRequest) GetRefreshToken() string {
 	if m != nil {
-		return m.Code
+		return m.RefreshToken
 	}
-	return 0
+	return ""
 }
 
-func init() {
-	proto.RegisterEnum("projectionist.UserRole", UserRole_name, UserRole_value)
-	proto.RegisterEnum("projectionist.Deleted", Deleted_name, Deleted_value)
-	proto.RegisterType((*User)(nil), "projectionist.User")
-	proto.RegisterType((*UserRequest)(nil), "projectionist.UserRequest")
-	proto.RegisterType((*UserResponse)(nil), "projectionist.UserResponse")
-	proto.RegisterType((*LoginRequest)(nil), "projectionist.LoginRequest")
-	proto.RegisterType((*LoginResponse)(nil), "projectionist.LoginResponse")
-	proto.RegisterType((*DefaultResponse)(nil), "projectionist.DefaultResponse")
+type LogoutRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("projectionist.proto", fileDescriptor_9cc8a487c9186292) }
-
-var fileDescriptor_9cc8a487c9186292 = []byte{
-	// 501 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x53, 0xcd, 0x6e, 0xd3, 0x4c,
-	0x14, 0xed, 0xb8, 0x76, 0x9c, 0xde, 0xfc, 0x7c, 0xf9, 0x6e, 0xab, 0xc6, 0x4a, 0x2b, 0x14, 0x79,
-	0x43, 0x14, 0x44, 0x53, 0x99, 0x5d, 0x77, 0x48, 0x80, 0x14, 0x09, 0x21, 0xe4, 0x0a, 0xb1, 0x28,
-	0x52, 0x64, 0xe2, 0x4b, 0x34, 0x60, 0x7b, 0x8c, 0x67, 0x9c, 0x8a, 0x6d, 0x5f, 0x81, 0x47, 0xe2,
-	0x09, 0x10, 0xaf, 0xc0, 0x83, 0x20, 0x8f, 0xc7, 0x55, 0x12, 0x22, 0xc4, 0x8e, 0x9d, 0x4f, 0xce,
-	0xc9, 0x99, 0x73, 0xee, 0x9d, 0x81, 0xe3, 0xbc, 0x10, 0x1f, 0x69, 0xa9, 0xb8, 0xc8, 0xb8, 0x54,
-	0x17, 0x79, 0x21, 0x94, 0xc0, 0xde, 0xd6, 0x8f, 0xa3, 0xf3, 0x95, 0x10, 0xab, 0x84, 0x66, 0x51,
-	0xce, 0x67, 0x51, 0x96, 0x09, 0x15, 0x55, 0x8c, 0xac, 0xc5, 0xfe, 0x37, 0x06, 0xf6, 0x1b, 0x49,
-	0x05, 0xf6, 0xc1, 0xe2, 0xb1, 0xc7, 0xc6, 0x6c, 0x72, 0x18, 0x5a, 0x3c, 0xc6, 0x11, 0xb4, 0x4b,
-	0x49, 0x45, 0x16, 0xa5, 0xe4, 0x59, 0x63, 0x36, 0x39, 0x0a, 0xef, 0x71, 0xc5, 0xe5, 0x91, 0x94,
-	0xb7, 0xa2, 0x88, 0xbd, 0xc3, 0x9a, 0x6b, 0x30, 0x3e, 0x02, 0xbb, 0x10, 0x09, 0x79, 0xf6, 0x98,
-	0x4d, 0xfa, 0xc1, 0xf0, 0x62, 0x3b, 0x61, 0x75, 0x54, 0x28, 0x12, 0x0a, 0xb5, 0x08, 0x4f, 0xc0,
-	0x51, 0xe2, 0x13, 0x65, 0x9e, 0xa3, 0x5d, 0x6a, 0x80, 0x97, 0xe0, 0xc6, 0x94, 0x90, 0xa2, 0xd8,
-	0x6b, 0x69, 0x97, 0xd3, 0x1d, 0x97, 0x67, 0x35, 0x1b, 0x36, 0x32, 0xff, 0x8e, 0x41, 0x47, 0x5b,
-	0xd3, 0xe7, 0x92, 0xa4, 0xfa, 0x27, 0x65, 0xfc, 0x1b, 0xe8, 0xd6, 0x19, 0x64, 0x2e, 0x32, 0x49,
-	0x18, 0x80, 0x9d, 0x92, 0x8a, 0x74, 0x8c, 0x4e, 0xf0, 0xe0, 0xb7, 0x0e, 0x1f, 0xa2, 0x32, 0x51,
-	0x8d, 0x3a, 0xd4, 0x5a, 0x1c, 0x82, 0x5b, 0x05, 0x5b, 0xf0, 0xd8, 0xe4, 0x6c, 0x55, 0x70, 0x1e,
-	0xfb, 0x2f, 0xa0, 0xfb, 0x52, 0xac, 0x78, 0xd6, 0x34, 0xdc, 0x6c, 0xc4, 0xfe, 0xd0, 0xc8, 0xda,
-	0x6e, 0xe4, 0x27, 0xd0, 0x33, 0x3e, 0x26, 0xe5, 0x43, 0xb0, 0xab, 0x3f, 0x9a, 0x94, 0xc7, 0xfb,
-	0x2a, 0x6a, 0xc1, 0x7d, 0x1d, 0xeb, 0xef, 0xeb, 0xf8, 0x6f, 0xe1, 0xbf, 0x1d, 0x02, 0x4f, 0xa1,
-	0x25, 0x55, 0xa4, 0x4a, 0xa9, 0x4f, 0x6c, 0x87, 0x06, 0xa1, 0x07, 0x6e, 0x4a, 0x52, 0x46, 0xab,
-	0x66, 0x43, 0x0d, 0x44, 0x04, 0x7b, 0x29, 0x62, 0xd2, 0xcb, 0x71, 0x42, 0xfd, 0x3d, 0xbd, 0x84,
-	0x76, 0x33, 0x7d, 0x3c, 0x02, 0xe7, 0x79, 0x9a, 0xab, 0x2f, 0x83, 0x83, 0xea, 0xf3, 0x69, 0x9c,
-	0xf2, 0x6c, 0xc0, 0xb0, 0x0f, 0x70, 0x5d, 0xe6, 0x54, 0xd4, 0xd8, 0x9a, 0x3e, 0x06, 0xd7, 0x5c,
-	0x1b, 0x74, 0x80, 0x2d, 0x06, 0x07, 0xd8, 0x01, 0x77, 0x2e, 0x17, 0x09, 0x5f, 0x53, 0x2d, 0x9f,
-	0xcb, 0x85, 0xb9, 0x4f, 0x03, 0x2b, 0xf8, 0xce, 0xe0, 0xe4, 0xf5, 0x66, 0xc3, 0x6b, 0x2a, 0xd6,
-	0x7c, 0x49, 0xf8, 0x0e, 0x1c, 0x3d, 0x40, 0x3c, 0xdb, 0x99, 0xc0, 0xe6, 0x7a, 0x46, 0xe7, 0xfb,
-	0xc9, 0x7a, 0x06, 0xbe, 0x77, 0xf7, 0xe3, 0xe7, 0x57, 0x0b, 0xfd, 0xde, 0x6c, 0x1d, 0xe8, 0x77,
-	0x99, 0x54, 0xf4, 0x15, 0x9b, 0xe2, 0x0d, 0xb8, 0xaf, 0xe8, 0x56, 0x3f, 0xc8, 0xd1, 0xbe, 0x55,
-	0x18, 0xfb, 0xb3, 0xbd, 0x9c, 0x71, 0x1f, 0x6a, 0xf7, 0xff, 0xfd, 0x6e, 0xe3, 0x5e, 0xad, 0xef,
-	0x8a, 0x4d, 0xdf, 0xb7, 0xf4, 0x93, 0x7f, 0xf2, 0x2b, 0x00, 0x00, 0xff, 0xff, 0x41, 0x88, 0xa8,
-	0xa1, 0x36, 0x04, 0x00, 0x00,
+func (m *LogoutRequest) Reset()         { *m = LogoutRequest{} }
+func (m *LogoutRequest) String() string { return proto.CompactTextString(m) }
+func (*LogoutRequest) ProtoMessage()    {}
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{7}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *LogoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogoutRequest.Unmarshal(m, b)
+}
+func (m *LogoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogoutRequest.Marshal(b, m, deterministic)
+}
+func (m *LogoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogoutRequest.Merge(m, src)
+}
+func (m *LogoutRequest) XXX_Size() int {
+	return xxx_messageInfo_LogoutRequest.Size(m)
+}
+func (m *LogoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogoutRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_LogoutRequest proto.InternalMessageInfo
 
-// ProjectionistServiceClient is the client API for ProjectionistService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ProjectionistServiceClient interface {
-	// auth
-	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
-	//---------
-	// user
-	NewUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error)
+type ChangePasswordRequest struct {
+	CurrentPassword      string   `protobuf:"bytes,1,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	NewPassword          string   `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type projectionistServiceClient struct {
-	cc *grpc.ClientConn
+func (m *ChangePasswordRequest) Reset()         { *m = ChangePasswordRequest{} }
+func (m *ChangePasswordRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangePasswordRequest) ProtoMessage()    {}
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{8}
 }
 
-func NewProjectionistServiceClient(cc *grpc.ClientConn) ProjectionistServiceClient {
-	return &projectionistServiceClient{cc}
+func (m *ChangePasswordRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangePasswordRequest.Unmarshal(m, b)
+}
+func (m *ChangePasswordRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangePasswordRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangePasswordRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangePasswordRequest.Merge(m, src)
+}
+func (m *ChangePasswordRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangePasswordRequest.Size(m)
+}
+func (m *ChangePasswordRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangePasswordRequest.DiscardUnknown(m)
 }
 
-func (c *projectionistServiceClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/Login", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ChangePasswordRequest proto.InternalMessageInfo
+
+func (m *ChangePasswordRequest) GetCurrentPassword() string {
+	if m != nil {
+		return m.CurrentPassword
 	}
-	return out, nil
+	return ""
 }
 
-func (c *projectionistServiceClient) NewUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
-	out := new(UserResponse)
-	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/NewUser", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChangePasswordRequest) GetNewPassword() string {
+	if m != nil {
+		return m.NewPassword
 	}
-	return out, nil
+	return ""
 }
 
-// ProjectionistServiceServer is the server API for ProjectionistService service.
-type ProjectionistServiceServer interface {
-	// auth
-	Login(context.Context, *LoginRequest) (*LoginResponse, error)
-	//---------
-	// user
-	NewUser(context.Context, *UserRequest) (*UserResponse, error)
+type Maintenance struct {
+	Id                   int64           `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceId            int64           `protobuf:"varint,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Name                 string          `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Mode                 MaintenanceMode `protobuf:"varint,4,opt,name=mode,proto3,enum=projectionist.MaintenanceMode" json:"mode,omitempty"`
+	StartsAt             int64           `protobuf:"varint,5,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt               int64           `protobuf:"varint,6,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	Cron                 string          `protobuf:"bytes,7,opt,name=cron,proto3" json:"cron,omitempty"`
+	Duration             int64           `protobuf:"varint,8,opt,name=duration,proto3" json:"duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-// UnimplementedProjectionistServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedProjectionistServiceServer struct {
+func (m *Maintenance) Reset()         { *m = Maintenance{} }
+func (m *Maintenance) String() string { return proto.CompactTextString(m) }
+func (*Maintenance) ProtoMessage()    {}
+func (*Maintenance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{9}
 }
 
-func (*UnimplementedProjectionistServiceServer) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+func (m *Maintenance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Maintenance.Unmarshal(m, b)
 }
-func (*UnimplementedProjectionistServiceServer) NewUser(ctx context.Context, req *UserRequest) (*UserResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method NewUser not implemented")
+func (m *Maintenance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Maintenance.Marshal(b, m, deterministic)
+}
+func (m *Maintenance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Maintenance.Merge(m, src)
+}
+func (m *Maintenance) XXX_Size() int {
+	return xxx_messageInfo_Maintenance.Size(m)
+}
+func (m *Maintenance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Maintenance.DiscardUnknown(m)
 }
 
-func RegisterProjectionistServiceServer(s *grpc.Server, srv ProjectionistServiceServer) {
-	s.RegisterService(&_ProjectionistService_serviceDesc, srv)
+var xxx_messageInfo_Maintenance proto.InternalMessageInfo
+
+func (m *Maintenance) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
 }
 
-func _ProjectionistService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *Maintenance) GetServiceId() int64 {
+	if m != nil {
+		return m.ServiceId
 	}
-	if interceptor == nil {
-		return srv.(ProjectionistServiceServer).Login(ctx, in)
+	return 0
+}
+
+func (m *Maintenance) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/projectionist.ProjectionistService/Login",
+	return ""
+}
+
+func (m *Maintenance) GetMode() MaintenanceMode {
+	if m != nil {
+		return m.Mode
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProjectionistServiceServer).Login(ctx, req.(*LoginRequest))
+	return MaintenanceMode_MaintenanceEmpty
+}
+
+func (m *Maintenance) GetStartsAt() int64 {
+	if m != nil {
+		return m.StartsAt
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _ProjectionistService_NewUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *Maintenance) GetEndsAt() int64 {
+	if m != nil {
+		return m.EndsAt
 	}
-	if interceptor == nil {
-		return srv.(ProjectionistServiceServer).NewUser(ctx, in)
+	return 0
+}
+
+func (m *Maintenance) GetCron() string {
+	if m != nil {
+		return m.Cron
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/projectionist.ProjectionistService/NewUser",
+	return ""
+}
+
+func (m *Maintenance) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProjectionistServiceServer).NewUser(ctx, req.(*UserRequest))
+	return 0
+}
+
+type MaintenanceRequest struct {
+	ServiceId            int64        `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Maintenance          *Maintenance `protobuf:"bytes,2,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *MaintenanceRequest) Reset()         { *m = MaintenanceRequest{} }
+func (m *MaintenanceRequest) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceRequest) ProtoMessage()    {}
+func (*MaintenanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{10}
+}
+
+func (m *MaintenanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceRequest.Unmarshal(m, b)
+}
+func (m *MaintenanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceRequest.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceRequest.Merge(m, src)
+}
+func (m *MaintenanceRequest) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceRequest.Size(m)
+}
+func (m *MaintenanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceRequest proto.InternalMessageInfo
+
+func (m *MaintenanceRequest) GetServiceId() int64 {
+	if m != nil {
+		return m.ServiceId
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _ProjectionistService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "projectionist.ProjectionistService",
-	HandlerType: (*ProjectionistServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Login",
-			Handler:    _ProjectionistService_Login_Handler,
-		},
+func (m *MaintenanceRequest) GetMaintenance() *Maintenance {
+	if m != nil {
+		return m.Maintenance
+	}
+	return nil
+}
+
+type MaintenanceResponse struct {
+	Meta                 *DefaultResponse `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	MaintenanceId        int64            `protobuf:"varint,2,opt,name=maintenance_id,json=maintenanceId,proto3" json:"maintenance_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *MaintenanceResponse) Reset()         { *m = MaintenanceResponse{} }
+func (m *MaintenanceResponse) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceResponse) ProtoMessage()    {}
+func (*MaintenanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{11}
+}
+
+func (m *MaintenanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceResponse.Unmarshal(m, b)
+}
+func (m *MaintenanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceResponse.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceResponse.Merge(m, src)
+}
+func (m *MaintenanceResponse) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceResponse.Size(m)
+}
+func (m *MaintenanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceResponse proto.InternalMessageInfo
+
+func (m *MaintenanceResponse) GetMeta() *DefaultResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *MaintenanceResponse) GetMaintenanceId() int64 {
+	if m != nil {
+		return m.MaintenanceId
+	}
+	return 0
+}
+
+type MaintenanceListRequest struct {
+	ServiceId            int64    `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MaintenanceListRequest) Reset()         { *m = MaintenanceListRequest{} }
+func (m *MaintenanceListRequest) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceListRequest) ProtoMessage()    {}
+func (*MaintenanceListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{12}
+}
+
+func (m *MaintenanceListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceListRequest.Unmarshal(m, b)
+}
+func (m *MaintenanceListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceListRequest.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceListRequest.Merge(m, src)
+}
+func (m *MaintenanceListRequest) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceListRequest.Size(m)
+}
+func (m *MaintenanceListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceListRequest proto.InternalMessageInfo
+
+func (m *MaintenanceListRequest) GetServiceId() int64 {
+	if m != nil {
+		return m.ServiceId
+	}
+	return 0
+}
+
+type MaintenanceListResponse struct {
+	Meta                 *DefaultResponse `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Maintenance          []*Maintenance   `protobuf:"bytes,2,rep,name=maintenance,proto3" json:"maintenance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *MaintenanceListResponse) Reset()         { *m = MaintenanceListResponse{} }
+func (m *MaintenanceListResponse) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceListResponse) ProtoMessage()    {}
+func (*MaintenanceListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{13}
+}
+
+func (m *MaintenanceListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceListResponse.Unmarshal(m, b)
+}
+func (m *MaintenanceListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceListResponse.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceListResponse.Merge(m, src)
+}
+func (m *MaintenanceListResponse) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceListResponse.Size(m)
+}
+func (m *MaintenanceListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceListResponse proto.InternalMessageInfo
+
+func (m *MaintenanceListResponse) GetMeta() *DefaultResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *MaintenanceListResponse) GetMaintenance() []*Maintenance {
+	if m != nil {
+		return m.Maintenance
+	}
+	return nil
+}
+
+type MaintenanceDeleteRequest struct {
+	ServiceId            int64    `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MaintenanceDeleteRequest) Reset()         { *m = MaintenanceDeleteRequest{} }
+func (m *MaintenanceDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceDeleteRequest) ProtoMessage()    {}
+func (*MaintenanceDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{14}
+}
+
+func (m *MaintenanceDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceDeleteRequest.Unmarshal(m, b)
+}
+func (m *MaintenanceDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceDeleteRequest.Merge(m, src)
+}
+func (m *MaintenanceDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceDeleteRequest.Size(m)
+}
+func (m *MaintenanceDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceDeleteRequest proto.InternalMessageInfo
+
+func (m *MaintenanceDeleteRequest) GetServiceId() int64 {
+	if m != nil {
+		return m.ServiceId
+	}
+	return 0
+}
+
+func (m *MaintenanceDeleteRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type ServiceIdRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceIdRequest) Reset()         { *m = ServiceIdRequest{} }
+func (m *ServiceIdRequest) String() string { return proto.CompactTextString(m) }
+func (*ServiceIdRequest) ProtoMessage()    {}
+func (*ServiceIdRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{15}
+}
+
+func (m *ServiceIdRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceIdRequest.Unmarshal(m, b)
+}
+func (m *ServiceIdRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceIdRequest.Marshal(b, m, deterministic)
+}
+func (m *ServiceIdRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceIdRequest.Merge(m, src)
+}
+func (m *ServiceIdRequest) XXX_Size() int {
+	return xxx_messageInfo_ServiceIdRequest.Size(m)
+}
+func (m *ServiceIdRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceIdRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceIdRequest proto.InternalMessageInfo
+
+func (m *ServiceIdRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type CheckResult struct {
+	ServiceId            int64       `protobuf:"varint,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Status               CheckStatus `protobuf:"varint,2,opt,name=status,proto3,enum=projectionist.CheckStatus" json:"status,omitempty"`
+	Error                string      `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Attempts             int32       `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	DurationMs           int64       `protobuf:"varint,5,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	CheckedAt            int64       `protobuf:"varint,6,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	CertExpiresAt        int64       `protobuf:"varint,7,opt,name=cert_expires_at,json=certExpiresAt,proto3" json:"cert_expires_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *CheckResult) Reset()         { *m = CheckResult{} }
+func (m *CheckResult) String() string { return proto.CompactTextString(m) }
+func (*CheckResult) ProtoMessage()    {}
+func (*CheckResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{16}
+}
+
+func (m *CheckResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckResult.Unmarshal(m, b)
+}
+func (m *CheckResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckResult.Marshal(b, m, deterministic)
+}
+func (m *CheckResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckResult.Merge(m, src)
+}
+func (m *CheckResult) XXX_Size() int {
+	return xxx_messageInfo_CheckResult.Size(m)
+}
+func (m *CheckResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckResult proto.InternalMessageInfo
+
+func (m *CheckResult) GetServiceId() int64 {
+	if m != nil {
+		return m.ServiceId
+	}
+	return 0
+}
+
+func (m *CheckResult) GetStatus() CheckStatus {
+	if m != nil {
+		return m.Status
+	}
+	return CheckStatus_CheckEmpty
+}
+
+func (m *CheckResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *CheckResult) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *CheckResult) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
+func (m *CheckResult) GetCheckedAt() int64 {
+	if m != nil {
+		return m.CheckedAt
+	}
+	return 0
+}
+
+func (m *CheckResult) GetCertExpiresAt() int64 {
+	if m != nil {
+		return m.CertExpiresAt
+	}
+	return 0
+}
+
+type CheckResponse struct {
+	Meta                 *DefaultResponse `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Result               *CheckResult     `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CheckResponse) Reset()         { *m = CheckResponse{} }
+func (m *CheckResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckResponse) ProtoMessage()    {}
+func (*CheckResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{17}
+}
+
+func (m *CheckResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckResponse.Unmarshal(m, b)
+}
+func (m *CheckResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckResponse.Merge(m, src)
+}
+func (m *CheckResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckResponse.Size(m)
+}
+func (m *CheckResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckResponse proto.InternalMessageInfo
+
+func (m *CheckResponse) GetMeta() *DefaultResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CheckResponse) GetResult() *CheckResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// Incident
+type Incident struct {
+	Id                   int64           `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceId            int64           `protobuf:"varint,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Reason               string          `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	StartedAt            int64           `protobuf:"varint,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndedAt              int64           `protobuf:"varint,5,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`
+	Duration             int64           `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	AcknowledgedAt       int64           `protobuf:"varint,7,opt,name=acknowledged_at,json=acknowledgedAt,proto3" json:"acknowledged_at,omitempty"`
+	AcknowledgedBy       int64           `protobuf:"varint,8,opt,name=acknowledged_by,json=acknowledgedBy,proto3" json:"acknowledged_by,omitempty"`
+	Notes                []*IncidentNote `protobuf:"bytes,9,rep,name=notes,proto3" json:"notes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Incident) Reset()         { *m = Incident{} }
+func (m *Incident) String() string { return proto.CompactTextString(m) }
+func (*Incident) ProtoMessage()    {}
+func (*Incident) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{18}
+}
+
+func (m *Incident) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Incident.Unmarshal(m, b)
+}
+func (m *Incident) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Incident.Marshal(b, m, deterministic)
+}
+func (m *Incident) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Incident.Merge(m, src)
+}
+func (m *Incident) XXX_Size() int {
+	return xxx_messageInfo_Incident.Size(m)
+}
+func (m *Incident) XXX_DiscardUnknown() {
+	xxx_messageInfo_Incident.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Incident proto.InternalMessageInfo
+
+func (m *Incident) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Incident) GetServiceId() int64 {
+	if m != nil {
+		return m.ServiceId
+	}
+	return 0
+}
+
+func (m *Incident) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Incident) GetStartedAt() int64 {
+	if m != nil {
+		return m.StartedAt
+	}
+	return 0
+}
+
+func (m *Incident) GetEndedAt() int64 {
+	if m != nil {
+		return m.EndedAt
+	}
+	return 0
+}
+
+func (m *Incident) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *Incident) GetAcknowledgedAt() int64 {
+	if m != nil {
+		return m.AcknowledgedAt
+	}
+	return 0
+}
+
+func (m *Incident) GetAcknowledgedBy() int64 {
+	if m != nil {
+		return m.AcknowledgedBy
+	}
+	return 0
+}
+
+func (m *Incident) GetNotes() []*IncidentNote {
+	if m != nil {
+		return m.Notes
+	}
+	return nil
+}
+
+type IncidentNote struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncidentId           int64    `protobuf:"varint,2,opt,name=incident_id,json=incidentId,proto3" json:"incident_id,omitempty"`
+	UserId               int64    `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Text                 string   `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IncidentNote) Reset()         { *m = IncidentNote{} }
+func (m *IncidentNote) String() string { return proto.CompactTextString(m) }
+func (*IncidentNote) ProtoMessage()    {}
+func (*IncidentNote) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{19}
+}
+
+func (m *IncidentNote) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncidentNote.Unmarshal(m, b)
+}
+func (m *IncidentNote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncidentNote.Marshal(b, m, deterministic)
+}
+func (m *IncidentNote) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncidentNote.Merge(m, src)
+}
+func (m *IncidentNote) XXX_Size() int {
+	return xxx_messageInfo_IncidentNote.Size(m)
+}
+func (m *IncidentNote) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncidentNote.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncidentNote proto.InternalMessageInfo
+
+func (m *IncidentNote) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *IncidentNote) GetIncidentId() int64 {
+	if m != nil {
+		return m.IncidentId
+	}
+	return 0
+}
+
+func (m *IncidentNote) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *IncidentNote) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+func (m *IncidentNote) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type IncidentListRequest struct {
+	Page                 int64    `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	ServiceId            int64    `protobuf:"varint,4,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IncidentListRequest) Reset()         { *m = IncidentListRequest{} }
+func (m *IncidentListRequest) String() string { return proto.CompactTextString(m) }
+func (*IncidentListRequest) ProtoMessage()    {}
+func (*IncidentListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{20}
+}
+
+func (m *IncidentListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncidentListRequest.Unmarshal(m, b)
+}
+func (m *IncidentListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncidentListRequest.Marshal(b, m, deterministic)
+}
+func (m *IncidentListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncidentListRequest.Merge(m, src)
+}
+func (m *IncidentListRequest) XXX_Size() int {
+	return xxx_messageInfo_IncidentListRequest.Size(m)
+}
+func (m *IncidentListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncidentListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncidentListRequest proto.InternalMessageInfo
+
+func (m *IncidentListRequest) GetPage() int64 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+func (m *IncidentListRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *IncidentListRequest) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *IncidentListRequest) GetServiceId() int64 {
+	if m != nil {
+		return m.ServiceId
+	}
+	return 0
+}
+
+type IncidentListResponse struct {
+	Meta                 *DefaultResponse `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Incidents            []*Incident      `protobuf:"bytes,2,rep,name=incidents,proto3" json:"incidents,omitempty"`
+	Total                int64            `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *IncidentListResponse) Reset()         { *m = IncidentListResponse{} }
+func (m *IncidentListResponse) String() string { return proto.CompactTextString(m) }
+func (*IncidentListResponse) ProtoMessage()    {}
+func (*IncidentListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{21}
+}
+
+func (m *IncidentListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncidentListResponse.Unmarshal(m, b)
+}
+func (m *IncidentListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncidentListResponse.Marshal(b, m, deterministic)
+}
+func (m *IncidentListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncidentListResponse.Merge(m, src)
+}
+func (m *IncidentListResponse) XXX_Size() int {
+	return xxx_messageInfo_IncidentListResponse.Size(m)
+}
+func (m *IncidentListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncidentListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncidentListResponse proto.InternalMessageInfo
+
+func (m *IncidentListResponse) GetMeta() *DefaultResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *IncidentListResponse) GetIncidents() []*Incident {
+	if m != nil {
+		return m.Incidents
+	}
+	return nil
+}
+
+func (m *IncidentListResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type IncidentRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IncidentRequest) Reset()         { *m = IncidentRequest{} }
+func (m *IncidentRequest) String() string { return proto.CompactTextString(m) }
+func (*IncidentRequest) ProtoMessage()    {}
+func (*IncidentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{22}
+}
+
+func (m *IncidentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncidentRequest.Unmarshal(m, b)
+}
+func (m *IncidentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncidentRequest.Marshal(b, m, deterministic)
+}
+func (m *IncidentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncidentRequest.Merge(m, src)
+}
+func (m *IncidentRequest) XXX_Size() int {
+	return xxx_messageInfo_IncidentRequest.Size(m)
+}
+func (m *IncidentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncidentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncidentRequest proto.InternalMessageInfo
+
+func (m *IncidentRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type IncidentNoteRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IncidentNoteRequest) Reset()         { *m = IncidentNoteRequest{} }
+func (m *IncidentNoteRequest) String() string { return proto.CompactTextString(m) }
+func (*IncidentNoteRequest) ProtoMessage()    {}
+func (*IncidentNoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{23}
+}
+
+func (m *IncidentNoteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncidentNoteRequest.Unmarshal(m, b)
+}
+func (m *IncidentNoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncidentNoteRequest.Marshal(b, m, deterministic)
+}
+func (m *IncidentNoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncidentNoteRequest.Merge(m, src)
+}
+func (m *IncidentNoteRequest) XXX_Size() int {
+	return xxx_messageInfo_IncidentNoteRequest.Size(m)
+}
+func (m *IncidentNoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncidentNoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncidentNoteRequest proto.InternalMessageInfo
+
+func (m *IncidentNoteRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *IncidentNoteRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+type IncidentResponse struct {
+	Meta                 *DefaultResponse `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Incident             *Incident        `protobuf:"bytes,2,opt,name=incident,proto3" json:"incident,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *IncidentResponse) Reset()         { *m = IncidentResponse{} }
+func (m *IncidentResponse) String() string { return proto.CompactTextString(m) }
+func (*IncidentResponse) ProtoMessage()    {}
+func (*IncidentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{24}
+}
+
+func (m *IncidentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncidentResponse.Unmarshal(m, b)
+}
+func (m *IncidentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncidentResponse.Marshal(b, m, deterministic)
+}
+func (m *IncidentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncidentResponse.Merge(m, src)
+}
+func (m *IncidentResponse) XXX_Size() int {
+	return xxx_messageInfo_IncidentResponse.Size(m)
+}
+func (m *IncidentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncidentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncidentResponse proto.InternalMessageInfo
+
+func (m *IncidentResponse) GetMeta() *DefaultResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *IncidentResponse) GetIncident() *Incident {
+	if m != nil {
+		return m.Incident
+	}
+	return nil
+}
+
+// Agent
+type AgentRegisterRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentRegisterRequest) Reset()         { *m = AgentRegisterRequest{} }
+func (m *AgentRegisterRequest) String() string { return proto.CompactTextString(m) }
+func (*AgentRegisterRequest) ProtoMessage()    {}
+func (*AgentRegisterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{25}
+}
+
+func (m *AgentRegisterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentRegisterRequest.Unmarshal(m, b)
+}
+func (m *AgentRegisterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentRegisterRequest.Marshal(b, m, deterministic)
+}
+func (m *AgentRegisterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentRegisterRequest.Merge(m, src)
+}
+func (m *AgentRegisterRequest) XXX_Size() int {
+	return xxx_messageInfo_AgentRegisterRequest.Size(m)
+}
+func (m *AgentRegisterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentRegisterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentRegisterRequest proto.InternalMessageInfo
+
+func (m *AgentRegisterRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type AgentRegisterResponse struct {
+	Meta                 *DefaultResponse `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	AgentId              int64            `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *AgentRegisterResponse) Reset()         { *m = AgentRegisterResponse{} }
+func (m *AgentRegisterResponse) String() string { return proto.CompactTextString(m) }
+func (*AgentRegisterResponse) ProtoMessage()    {}
+func (*AgentRegisterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{26}
+}
+
+func (m *AgentRegisterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentRegisterResponse.Unmarshal(m, b)
+}
+func (m *AgentRegisterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentRegisterResponse.Marshal(b, m, deterministic)
+}
+func (m *AgentRegisterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentRegisterResponse.Merge(m, src)
+}
+func (m *AgentRegisterResponse) XXX_Size() int {
+	return xxx_messageInfo_AgentRegisterResponse.Size(m)
+}
+func (m *AgentRegisterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentRegisterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentRegisterResponse proto.InternalMessageInfo
+
+func (m *AgentRegisterResponse) GetMeta() *DefaultResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *AgentRegisterResponse) GetAgentId() int64 {
+	if m != nil {
+		return m.AgentId
+	}
+	return 0
+}
+
+type AgentRequest struct {
+	AgentId              int64    `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentRequest) Reset()         { *m = AgentRequest{} }
+func (m *AgentRequest) String() string { return proto.CompactTextString(m) }
+func (*AgentRequest) ProtoMessage()    {}
+func (*AgentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{27}
+}
+
+func (m *AgentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentRequest.Unmarshal(m, b)
+}
+func (m *AgentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentRequest.Marshal(b, m, deterministic)
+}
+func (m *AgentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentRequest.Merge(m, src)
+}
+func (m *AgentRequest) XXX_Size() int {
+	return xxx_messageInfo_AgentRequest.Size(m)
+}
+func (m *AgentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentRequest proto.InternalMessageInfo
+
+func (m *AgentRequest) GetAgentId() int64 {
+	if m != nil {
+		return m.AgentId
+	}
+	return 0
+}
+
+type AgentService struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Link                 string   `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	Token                string   `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	Frequency            int64    `protobuf:"varint,5,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Timeout              int64    `protobuf:"varint,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Retries              int64    `protobuf:"varint,7,opt,name=retries,proto3" json:"retries,omitempty"`
+	RetryBackoff         int64    `protobuf:"varint,8,opt,name=retry_backoff,json=retryBackoff,proto3" json:"retry_backoff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentService) Reset()         { *m = AgentService{} }
+func (m *AgentService) String() string { return proto.CompactTextString(m) }
+func (*AgentService) ProtoMessage()    {}
+func (*AgentService) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{28}
+}
+
+func (m *AgentService) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentService.Unmarshal(m, b)
+}
+func (m *AgentService) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentService.Marshal(b, m, deterministic)
+}
+func (m *AgentService) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentService.Merge(m, src)
+}
+func (m *AgentService) XXX_Size() int {
+	return xxx_messageInfo_AgentService.Size(m)
+}
+func (m *AgentService) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentService.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentService proto.InternalMessageInfo
+
+func (m *AgentService) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AgentService) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AgentService) GetLink() string {
+	if m != nil {
+		return m.Link
+	}
+	return ""
+}
+
+func (m *AgentService) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *AgentService) GetFrequency() int64 {
+	if m != nil {
+		return m.Frequency
+	}
+	return 0
+}
+
+func (m *AgentService) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *AgentService) GetRetries() int64 {
+	if m != nil {
+		return m.Retries
+	}
+	return 0
+}
+
+func (m *AgentService) GetRetryBackoff() int64 {
+	if m != nil {
+		return m.RetryBackoff
+	}
+	return 0
+}
+
+type AgentServicesResponse struct {
+	Meta                 *DefaultResponse `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Services             []*AgentService  `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *AgentServicesResponse) Reset()         { *m = AgentServicesResponse{} }
+func (m *AgentServicesResponse) String() string { return proto.CompactTextString(m) }
+func (*AgentServicesResponse) ProtoMessage()    {}
+func (*AgentServicesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{29}
+}
+
+func (m *AgentServicesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentServicesResponse.Unmarshal(m, b)
+}
+func (m *AgentServicesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentServicesResponse.Marshal(b, m, deterministic)
+}
+func (m *AgentServicesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentServicesResponse.Merge(m, src)
+}
+func (m *AgentServicesResponse) XXX_Size() int {
+	return xxx_messageInfo_AgentServicesResponse.Size(m)
+}
+func (m *AgentServicesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentServicesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentServicesResponse proto.InternalMessageInfo
+
+func (m *AgentServicesResponse) GetMeta() *DefaultResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *AgentServicesResponse) GetServices() []*AgentService {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+type AgentReportRequest struct {
+	AgentId              int64        `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Result               *CheckResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AgentReportRequest) Reset()         { *m = AgentReportRequest{} }
+func (m *AgentReportRequest) String() string { return proto.CompactTextString(m) }
+func (*AgentReportRequest) ProtoMessage()    {}
+func (*AgentReportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{30}
+}
+
+func (m *AgentReportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentReportRequest.Unmarshal(m, b)
+}
+func (m *AgentReportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentReportRequest.Marshal(b, m, deterministic)
+}
+func (m *AgentReportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentReportRequest.Merge(m, src)
+}
+func (m *AgentReportRequest) XXX_Size() int {
+	return xxx_messageInfo_AgentReportRequest.Size(m)
+}
+func (m *AgentReportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentReportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentReportRequest proto.InternalMessageInfo
+
+func (m *AgentReportRequest) GetAgentId() int64 {
+	if m != nil {
+		return m.AgentId
+	}
+	return 0
+}
+
+func (m *AgentReportRequest) GetResult() *CheckResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// Default
+type DefaultResponse struct {
+	Status               bool     `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Code                 int32    `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DefaultResponse) Reset()         { *m = DefaultResponse{} }
+func (m *DefaultResponse) String() string { return proto.CompactTextString(m) }
+func (*DefaultResponse) ProtoMessage()    {}
+func (*DefaultResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9cc8a487c9186292, []int{31}
+}
+
+func (m *DefaultResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefaultResponse.Unmarshal(m, b)
+}
+func (m *DefaultResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefaultResponse.Marshal(b, m, deterministic)
+}
+func (m *DefaultResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefaultResponse.Merge(m, src)
+}
+func (m *DefaultResponse) XXX_Size() int {
+	return xxx_messageInfo_DefaultResponse.Size(m)
+}
+func (m *DefaultResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefaultResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefaultResponse proto.InternalMessageInfo
+
+func (m *DefaultResponse) GetStatus() bool {
+	if m != nil {
+		return m.Status
+	}
+	return false
+}
+
+func (m *DefaultResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *DefaultResponse) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("projectionist.UserRole", UserRole_name, UserRole_value)
+	proto.RegisterEnum("projectionist.Deleted", Deleted_name, Deleted_value)
+	proto.RegisterEnum("projectionist.MaintenanceMode", MaintenanceMode_name, MaintenanceMode_value)
+	proto.RegisterEnum("projectionist.CheckStatus", CheckStatus_name, CheckStatus_value)
+	proto.RegisterType((*User)(nil), "projectionist.User")
+	proto.RegisterType((*UserRequest)(nil), "projectionist.UserRequest")
+	proto.RegisterType((*UserResponse)(nil), "projectionist.UserResponse")
+	proto.RegisterType((*LoginRequest)(nil), "projectionist.LoginRequest")
+	proto.RegisterType((*LoginResponse)(nil), "projectionist.LoginResponse")
+	proto.RegisterType((*LoginTwoFactorRequest)(nil), "projectionist.LoginTwoFactorRequest")
+	proto.RegisterType((*RefreshRequest)(nil), "projectionist.RefreshRequest")
+	proto.RegisterType((*LogoutRequest)(nil), "projectionist.LogoutRequest")
+	proto.RegisterType((*ChangePasswordRequest)(nil), "projectionist.ChangePasswordRequest")
+	proto.RegisterType((*Maintenance)(nil), "projectionist.Maintenance")
+	proto.RegisterType((*MaintenanceRequest)(nil), "projectionist.MaintenanceRequest")
+	proto.RegisterType((*MaintenanceResponse)(nil), "projectionist.MaintenanceResponse")
+	proto.RegisterType((*MaintenanceListRequest)(nil), "projectionist.MaintenanceListRequest")
+	proto.RegisterType((*MaintenanceListResponse)(nil), "projectionist.MaintenanceListResponse")
+	proto.RegisterType((*MaintenanceDeleteRequest)(nil), "projectionist.MaintenanceDeleteRequest")
+	proto.RegisterType((*ServiceIdRequest)(nil), "projectionist.ServiceIdRequest")
+	proto.RegisterType((*CheckResult)(nil), "projectionist.CheckResult")
+	proto.RegisterType((*CheckResponse)(nil), "projectionist.CheckResponse")
+	proto.RegisterType((*Incident)(nil), "projectionist.Incident")
+	proto.RegisterType((*IncidentNote)(nil), "projectionist.IncidentNote")
+	proto.RegisterType((*IncidentListRequest)(nil), "projectionist.IncidentListRequest")
+	proto.RegisterType((*IncidentListResponse)(nil), "projectionist.IncidentListResponse")
+	proto.RegisterType((*IncidentRequest)(nil), "projectionist.IncidentRequest")
+	proto.RegisterType((*IncidentNoteRequest)(nil), "projectionist.IncidentNoteRequest")
+	proto.RegisterType((*IncidentResponse)(nil), "projectionist.IncidentResponse")
+	proto.RegisterType((*AgentRegisterRequest)(nil), "projectionist.AgentRegisterRequest")
+	proto.RegisterType((*AgentRegisterResponse)(nil), "projectionist.AgentRegisterResponse")
+	proto.RegisterType((*AgentRequest)(nil), "projectionist.AgentRequest")
+	proto.RegisterType((*AgentService)(nil), "projectionist.AgentService")
+	proto.RegisterType((*AgentServicesResponse)(nil), "projectionist.AgentServicesResponse")
+	proto.RegisterType((*AgentReportRequest)(nil), "projectionist.AgentReportRequest")
+	proto.RegisterType((*DefaultResponse)(nil), "projectionist.DefaultResponse")
+}
+
+func init() { proto.RegisterFile("projectionist.proto", fileDescriptor_9cc8a487c9186292) }
+
+var fileDescriptor_9cc8a487c9186292 = []byte{
+	// 1937 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x5f, 0x6f, 0x1b, 0xc7,
+	0x11, 0xd7, 0x91, 0x14, 0xff, 0x0c, 0x29, 0x92, 0x5a, 0xfd, 0xa3, 0x29, 0x3b, 0xb2, 0xd7, 0xb1,
+	0xad, 0xa8, 0xb5, 0x99, 0xca, 0x30, 0xd2, 0x16, 0x79, 0x61, 0xd2, 0x38, 0x20, 0x10, 0x1b, 0xc6,
+	0xc9, 0x4d, 0x1f, 0x12, 0x80, 0x38, 0xdf, 0xad, 0xe8, 0xab, 0xc8, 0x3b, 0x7a, 0x77, 0xcf, 0xb4,
+	0x60, 0xf8, 0x25, 0xc8, 0x5b, 0xfb, 0x16, 0x14, 0xf9, 0x12, 0xfd, 0x16, 0xed, 0x37, 0xc8, 0x57,
+	0xe8, 0x53, 0x3f, 0x42, 0x9f, 0x8a, 0xfd, 0x47, 0xee, 0x1d, 0x49, 0x51, 0x8e, 0x02, 0xe4, 0xed,
+	0x76, 0x76, 0x76, 0x7e, 0x33, 0xb3, 0x33, 0xb3, 0x33, 0x24, 0x6c, 0x8d, 0x69, 0xfc, 0x57, 0xe2,
+	0xf3, 0x30, 0x8e, 0x42, 0xc6, 0x1f, 0x8c, 0x69, 0xcc, 0x63, 0xb4, 0x91, 0x22, 0xb6, 0xaf, 0x0f,
+	0xe2, 0x78, 0x30, 0x24, 0x1d, 0x6f, 0x1c, 0x76, 0xbc, 0x28, 0x8a, 0xb9, 0x27, 0x76, 0x98, 0x62,
+	0xc6, 0xff, 0x72, 0xa0, 0xf0, 0x67, 0x46, 0x28, 0xaa, 0x43, 0x2e, 0x0c, 0x5a, 0xce, 0x4d, 0xe7,
+	0x30, 0xef, 0xe6, 0xc2, 0x00, 0xb5, 0xa1, 0x9c, 0x30, 0x42, 0x23, 0x6f, 0x44, 0x5a, 0xb9, 0x9b,
+	0xce, 0x61, 0xc5, 0x9d, 0xae, 0xc5, 0xde, 0xd8, 0x63, 0x6c, 0x12, 0xd3, 0xa0, 0x95, 0x57, 0x7b,
+	0x66, 0x8d, 0x7e, 0x03, 0x05, 0x1a, 0x0f, 0x49, 0xab, 0x70, 0xd3, 0x39, 0xac, 0x1f, 0xef, 0x3d,
+	0x48, 0x6b, 0x28, 0xa0, 0xdc, 0x78, 0x48, 0x5c, 0xc9, 0x84, 0xb6, 0x61, 0x9d, 0xc7, 0x67, 0x24,
+	0x6a, 0xad, 0x4b, 0x29, 0x6a, 0x81, 0x3e, 0x86, 0x52, 0x40, 0x86, 0x84, 0x93, 0xa0, 0x55, 0x94,
+	0x52, 0x76, 0x33, 0x52, 0xfe, 0xa4, 0x76, 0x5d, 0xc3, 0x86, 0xbf, 0x73, 0xa0, 0x2a, 0x45, 0x93,
+	0x57, 0x09, 0x61, 0xfc, 0x57, 0x31, 0x06, 0x7f, 0x03, 0x35, 0x49, 0x21, 0x6c, 0x1c, 0x47, 0x8c,
+	0xa0, 0x63, 0x28, 0x8c, 0x08, 0xf7, 0xa4, 0x1a, 0xd5, 0xe3, 0x0f, 0xe6, 0x6c, 0x38, 0xf5, 0x92,
+	0x21, 0x37, 0xdc, 0xae, 0xe4, 0x45, 0x7b, 0x50, 0x12, 0x8a, 0xf5, 0xc3, 0x40, 0xeb, 0x59, 0x14,
+	0xcb, 0x5e, 0x80, 0x1f, 0x43, 0xed, 0xab, 0x78, 0x10, 0x46, 0xc6, 0x42, 0xdb, 0x22, 0xe7, 0x02,
+	0x8b, 0x72, 0x69, 0x8b, 0xf0, 0xbf, 0x73, 0xb0, 0xa1, 0x05, 0x69, 0x35, 0xef, 0x41, 0x41, 0x9c,
+	0xd4, 0x6a, 0x6e, 0x2d, 0xb2, 0x51, 0x32, 0x4c, 0xed, 0xc9, 0xbd, 0x87, 0x3d, 0xb7, 0x61, 0x83,
+	0x92, 0x53, 0x4a, 0xd8, 0xcb, 0xbe, 0xba, 0x68, 0xe5, 0xe1, 0x9a, 0x26, 0x3e, 0x97, 0xf7, 0x7d,
+	0x03, 0x80, 0xbc, 0x19, 0x87, 0x94, 0xb0, 0xbe, 0xc7, 0xa5, 0xaf, 0xf3, 0x6e, 0x45, 0x53, 0xba,
+	0x1c, 0x3d, 0x80, 0x2d, 0x3e, 0x89, 0xfb, 0xa7, 0x9e, 0xcf, 0x63, 0xda, 0xa7, 0xe4, 0x55, 0x12,
+	0x52, 0x12, 0xc8, 0x90, 0x29, 0xbb, 0x9b, 0x7c, 0x12, 0x3f, 0x96, 0x3b, 0xae, 0xde, 0x40, 0x87,
+	0xd0, 0xb4, 0xf8, 0x15, 0x6c, 0x51, 0xc2, 0xd6, 0xa7, 0xcc, 0x0a, 0xf8, 0xf7, 0xd0, 0x32, 0x8e,
+	0xe9, 0xfb, 0x2f, 0xbd, 0x68, 0x40, 0x66, 0xe2, 0x4b, 0x52, 0xfc, 0xae, 0xd9, 0xff, 0x5c, 0x6e,
+	0x1b, 0x0c, 0xdc, 0x85, 0x1d, 0xe9, 0xc5, 0xe7, 0x36, 0xba, 0xb8, 0x97, 0x69, 0x44, 0x3b, 0x76,
+	0x44, 0x23, 0x28, 0xf8, 0x71, 0x60, 0x62, 0x4f, 0x7e, 0xe3, 0x47, 0x50, 0x77, 0x95, 0x17, 0xcc,
+	0xd9, 0x39, 0x67, 0x39, 0xf3, 0xce, 0xc2, 0x0d, 0x79, 0x7f, 0x71, 0xc2, 0xf5, 0x29, 0x4c, 0x60,
+	0x47, 0x29, 0xf7, 0x4c, 0xab, 0x6a, 0xc4, 0x7d, 0x04, 0x4d, 0x3f, 0xa1, 0x94, 0x44, 0xbc, 0x3f,
+	0x0d, 0x07, 0x25, 0xb1, 0xa1, 0xe9, 0xe6, 0x04, 0xba, 0x05, 0xb5, 0x88, 0x4c, 0xfa, 0x99, 0xa8,
+	0xa9, 0x46, 0x64, 0x62, 0x58, 0xf0, 0x7f, 0x1d, 0xa8, 0x3e, 0xf1, 0xc2, 0x88, 0x93, 0xc8, 0x8b,
+	0x7c, 0x32, 0x97, 0x62, 0x37, 0x00, 0x18, 0xa1, 0xaf, 0x43, 0x9f, 0x98, 0xe0, 0xcd, 0xbb, 0x15,
+	0x4d, 0xe9, 0x05, 0xc2, 0x03, 0x32, 0x56, 0xd5, 0xfd, 0xcb, 0x6f, 0x19, 0x50, 0xc2, 0x2b, 0x2a,
+	0xbb, 0xb2, 0x01, 0x65, 0x81, 0x3d, 0x89, 0x03, 0x11, 0x50, 0x71, 0x40, 0xd0, 0x3e, 0x54, 0x18,
+	0xf7, 0x28, 0x97, 0xa1, 0xb2, 0x2e, 0x51, 0xca, 0x8a, 0xd0, 0xe5, 0x22, 0x7b, 0x48, 0x14, 0xc8,
+	0xad, 0xa2, 0xdc, 0x2a, 0x8a, 0x65, 0x97, 0x4b, 0xff, 0xd3, 0x38, 0x6a, 0x95, 0xb4, 0xff, 0x69,
+	0x1c, 0x89, 0x2c, 0x09, 0x12, 0x2a, 0x8b, 0x61, 0xab, 0xac, 0x04, 0x99, 0x35, 0x7e, 0x05, 0xc8,
+	0x82, 0x37, 0x0e, 0x4d, 0x9b, 0xe8, 0x64, 0x4d, 0xfc, 0x14, 0xaa, 0xa3, 0xd9, 0x21, 0x9d, 0x26,
+	0xed, 0xe5, 0x56, 0xb9, 0x36, 0x3b, 0x1e, 0xc3, 0x56, 0x0a, 0xf2, 0x0a, 0x45, 0xe4, 0x0e, 0xd4,
+	0x2d, 0xc9, 0xb3, 0xeb, 0xd8, 0xb0, 0xa8, 0xbd, 0x00, 0x7f, 0x02, 0xbb, 0x16, 0xe2, 0x57, 0x21,
+	0xe3, 0x97, 0x33, 0x14, 0xff, 0xcd, 0x81, 0xbd, 0xb9, 0x93, 0x57, 0xd0, 0x77, 0xce, 0x71, 0xf9,
+	0xf7, 0x71, 0x5c, 0x0f, 0x5a, 0xd6, 0x9e, 0x7a, 0x1a, 0x2e, 0x79, 0x63, 0x2a, 0x86, 0x73, 0x26,
+	0x86, 0x31, 0x86, 0xe6, 0x89, 0xd9, 0x5c, 0xf2, 0x94, 0xe0, 0xff, 0x39, 0x50, 0xfd, 0xfc, 0x25,
+	0xf1, 0xcf, 0x5c, 0xc2, 0x92, 0xe1, 0x4a, 0x88, 0x63, 0x28, 0x32, 0xee, 0xf1, 0x84, 0x49, 0x98,
+	0xfa, 0x9c, 0x59, 0x52, 0xd4, 0x89, 0xe4, 0x70, 0x35, 0xa7, 0xa8, 0x21, 0x84, 0xd2, 0x98, 0xea,
+	0x64, 0x51, 0x0b, 0x11, 0xaf, 0x1e, 0xe7, 0x64, 0x34, 0xe6, 0x4c, 0x66, 0xcc, 0xba, 0x3b, 0x5d,
+	0xa3, 0x03, 0xa8, 0x9a, 0xd8, 0xed, 0x8f, 0x98, 0xce, 0x0b, 0x30, 0xa4, 0x27, 0x4c, 0x68, 0xe9,
+	0x0b, 0x24, 0x12, 0xcc, 0x92, 0xa3, 0xa2, 0x29, 0x5d, 0x8e, 0xee, 0x42, 0xc3, 0x27, 0x94, 0xf7,
+	0xad, 0x32, 0x5c, 0x52, 0x21, 0x23, 0xc8, 0x5f, 0x98, 0x52, 0x8c, 0x27, 0xb0, 0x61, 0x6c, 0xff,
+	0xf9, 0xd7, 0x7d, 0x0c, 0x45, 0x2a, 0x7d, 0xb7, 0x24, 0x45, 0x2c, 0xef, 0xba, 0x9a, 0x13, 0xff,
+	0x33, 0x07, 0xe5, 0x5e, 0xe4, 0x87, 0x01, 0x89, 0xf8, 0xfb, 0x96, 0x9e, 0x5d, 0x81, 0xe7, 0xb1,
+	0xd8, 0x3c, 0x3e, 0x7a, 0x25, 0x8f, 0x89, 0xca, 0xa1, 0x7c, 0xa2, 0x9f, 0x1d, 0x4d, 0xe9, 0x72,
+	0x74, 0x0d, 0xca, 0x24, 0x0a, 0xd4, 0xa6, 0x72, 0x68, 0x49, 0xae, 0xbb, 0x3c, 0x55, 0x3a, 0x8a,
+	0xe9, 0xd2, 0x81, 0xee, 0x41, 0xc3, 0xf3, 0xcf, 0xa2, 0x78, 0x32, 0x24, 0xc1, 0x80, 0x04, 0x33,
+	0x57, 0xd6, 0x6d, 0x72, 0x97, 0xcf, 0x31, 0xbe, 0x38, 0x6f, 0x95, 0xe7, 0x19, 0x3f, 0x3b, 0x47,
+	0xbf, 0x83, 0xf5, 0x28, 0xe6, 0x84, 0xb5, 0x2a, 0x32, 0x31, 0xf6, 0x33, 0xee, 0x32, 0x6e, 0x79,
+	0x1a, 0x73, 0xe2, 0x2a, 0x4e, 0x91, 0xa1, 0x35, 0x9b, 0x3e, 0xe7, 0xb2, 0x03, 0xa8, 0x86, 0x7a,
+	0x7f, 0xe6, 0x33, 0x30, 0xa4, 0x5e, 0x60, 0x37, 0x22, 0x79, 0x55, 0x4a, 0x55, 0x23, 0x22, 0x4a,
+	0x29, 0x27, 0x6f, 0x94, 0xbf, 0x2a, 0xae, 0xfc, 0x96, 0xd1, 0x45, 0x89, 0xc7, 0x6d, 0x67, 0x55,
+	0x34, 0xa5, 0xcb, 0x31, 0x87, 0x2d, 0xa3, 0x8c, 0x5d, 0x65, 0x10, 0x14, 0xc6, 0xde, 0x80, 0x68,
+	0xad, 0xe4, 0xb7, 0x08, 0x7d, 0x3f, 0x4e, 0x22, 0xae, 0x35, 0x52, 0x0b, 0x41, 0x15, 0xa9, 0x61,
+	0x5e, 0x0f, 0xb5, 0xc8, 0x5c, 0x7b, 0x21, 0x5b, 0xa5, 0x7e, 0x74, 0x60, 0x3b, 0x0d, 0x7b, 0x85,
+	0x98, 0x7d, 0x04, 0x15, 0xe3, 0x1c, 0xa6, 0x0b, 0xd4, 0xde, 0x92, 0x7b, 0x70, 0x67, 0x9c, 0xaa,
+	0x1b, 0xe0, 0xde, 0x50, 0xfb, 0x50, 0x2d, 0xf0, 0x2d, 0x68, 0x4c, 0x99, 0x97, 0x54, 0x99, 0x3f,
+	0xc0, 0x96, 0x7d, 0x7f, 0x4b, 0xd8, 0xa6, 0x97, 0x91, 0x9b, 0x5d, 0x06, 0x7e, 0x0b, 0xcd, 0x99,
+	0xf4, 0x2b, 0x98, 0xfc, 0x10, 0xca, 0xc6, 0x10, 0x9d, 0xa8, 0x4b, 0x2d, 0x9e, 0x32, 0xe2, 0x23,
+	0xd8, 0xee, 0x0e, 0x24, 0xf2, 0x20, 0x64, 0x7c, 0xd6, 0x90, 0x9b, 0xe7, 0xdf, 0x99, 0x3d, 0xff,
+	0xf8, 0x14, 0x76, 0x32, 0xbc, 0x57, 0xd0, 0xf6, 0x1a, 0x94, 0xbd, 0x41, 0x2a, 0x9a, 0x4b, 0x72,
+	0xdd, 0x0b, 0xf0, 0x47, 0x50, 0xd3, 0x38, 0x4a, 0x17, 0x9b, 0xd5, 0x49, 0xb3, 0xfe, 0xe4, 0x68,
+	0x5e, 0xfd, 0x0c, 0x2c, 0x72, 0xb8, 0x35, 0x44, 0xc8, 0x6f, 0x41, 0x1b, 0x86, 0xd1, 0x99, 0x69,
+	0x6d, 0xc4, 0xf7, 0xac, 0x0d, 0x2c, 0xd8, 0x6d, 0xe0, 0x75, 0xa8, 0x9c, 0x8a, 0x06, 0x93, 0x44,
+	0xfe, 0xb9, 0x49, 0x93, 0x29, 0x01, 0xb5, 0xa0, 0xc4, 0xc3, 0x11, 0x89, 0x13, 0x53, 0xa0, 0xcd,
+	0x52, 0xec, 0x50, 0xc2, 0x69, 0x48, 0x98, 0xae, 0x25, 0x66, 0xa9, 0x5a, 0x46, 0x4e, 0xcf, 0xfb,
+	0x2f, 0x3c, 0xff, 0x2c, 0x3e, 0x3d, 0xd5, 0x25, 0xa4, 0x26, 0x89, 0x9f, 0x29, 0x1a, 0xfe, 0xde,
+	0x81, 0x1d, 0xdb, 0x2a, 0x76, 0x25, 0x4f, 0x7f, 0x02, 0x65, 0x9d, 0x64, 0x26, 0x13, 0xb2, 0x15,
+	0xc9, 0xc6, 0x72, 0xa7, 0xcc, 0xd8, 0x07, 0xa4, 0xef, 0x61, 0x1c, 0xd3, 0x4b, 0xdc, 0xc6, 0xcf,
+	0x7a, 0x28, 0xfe, 0x02, 0x8d, 0x8c, 0xda, 0xa2, 0xfe, 0xeb, 0x27, 0xd8, 0x91, 0x3d, 0xbd, 0x5e,
+	0x09, 0xaf, 0x8e, 0x08, 0x63, 0xa2, 0x04, 0xa9, 0xeb, 0x34, 0xcb, 0x69, 0xbb, 0x9e, 0x97, 0xcf,
+	0xac, 0xfc, 0x3e, 0xfa, 0x14, 0xca, 0x66, 0xde, 0x43, 0x15, 0x58, 0xff, 0x62, 0x34, 0xe6, 0xe7,
+	0xcd, 0x35, 0xf1, 0xd9, 0x0d, 0x46, 0x61, 0xd4, 0x74, 0x50, 0x1d, 0xe0, 0x24, 0x19, 0x13, 0xaa,
+	0xd6, 0x39, 0x04, 0x50, 0xfc, 0x3a, 0x24, 0x13, 0x42, 0x9b, 0xf9, 0xa3, 0xfb, 0x50, 0xd2, 0x43,
+	0x2b, 0x5a, 0x07, 0xa7, 0xdf, 0x5c, 0x43, 0x55, 0x28, 0xf5, 0x58, 0x7f, 0x18, 0xbe, 0x26, 0xea,
+	0x68, 0x8f, 0xf5, 0xf5, 0x34, 0xdb, 0xcc, 0x1d, 0x7d, 0x0d, 0x8d, 0x4c, 0xfb, 0x8b, 0xb6, 0xa1,
+	0x69, 0x91, 0x0c, 0x7c, 0x9a, 0xfa, 0xcc, 0x4b, 0x98, 0x10, 0xb7, 0x97, 0xea, 0x25, 0x4f, 0x92,
+	0xf1, 0x98, 0x12, 0xc6, 0x9a, 0xb9, 0xa3, 0x13, 0xa8, 0x5a, 0x0d, 0x87, 0x80, 0x95, 0x4b, 0x23,
+	0xad, 0x09, 0x35, 0xb5, 0x9d, 0xf8, 0xbe, 0x38, 0xe0, 0x4c, 0x29, 0x8f, 0xbd, 0x70, 0x98, 0x50,
+	0xd2, 0xcc, 0x4d, 0x29, 0xcf, 0x55, 0x6c, 0x36, 0xf3, 0xc7, 0x3f, 0x6e, 0xc2, 0xf6, 0x33, 0xfb,
+	0x62, 0x4c, 0xf2, 0x7c, 0x0b, 0xeb, 0x72, 0x48, 0x42, 0xd9, 0x00, 0xb1, 0x27, 0xd9, 0xf6, 0xf5,
+	0xc5, 0x9b, 0xea, 0xf2, 0x70, 0xeb, 0xbb, 0x9f, 0xfe, 0xf3, 0x43, 0x0e, 0xe1, 0x8d, 0xce, 0xeb,
+	0x63, 0xf9, 0x13, 0xc6, 0x50, 0x6c, 0xff, 0xd1, 0x39, 0x42, 0x14, 0xea, 0xe9, 0x11, 0x0c, 0x7d,
+	0xb8, 0x48, 0x52, 0x76, 0x42, 0x5b, 0x81, 0x77, 0x5d, 0xe2, 0xed, 0xe2, 0xcd, 0x14, 0x5e, 0xe7,
+	0xf8, 0xd4, 0x13, 0x98, 0x2f, 0xa0, 0xa4, 0x67, 0x36, 0x74, 0x23, 0x23, 0x26, 0x3d, 0xcb, 0xad,
+	0x40, 0x69, 0x4b, 0x94, 0x6d, 0xdc, 0x30, 0x28, 0x7a, 0xc4, 0x13, 0x18, 0x1e, 0x14, 0xd5, 0x80,
+	0x87, 0x16, 0xc8, 0x98, 0xcd, 0x7d, 0xed, 0x15, 0xd9, 0x8a, 0xaf, 0x49, 0x8c, 0x2d, 0x5c, 0xb7,
+	0x2c, 0x89, 0x13, 0x2e, 0x20, 0x5e, 0x41, 0x3d, 0x3d, 0x32, 0xce, 0xb9, 0x6e, 0xe1, 0x44, 0xb9,
+	0xc2, 0xa8, 0x7d, 0x09, 0xb8, 0xd3, 0x6e, 0x1a, 0x40, 0x33, 0x4e, 0x0a, 0xc8, 0x6f, 0xa0, 0xf4,
+	0x94, 0x4c, 0xe4, 0x2f, 0x4d, 0xed, 0x45, 0x3f, 0x31, 0x68, 0x84, 0xfd, 0x85, 0x7b, 0x1a, 0x60,
+	0x4f, 0x02, 0x6c, 0xe2, 0x9a, 0x01, 0x10, 0x2d, 0x89, 0x10, 0xfe, 0x77, 0x07, 0xea, 0x4f, 0xc9,
+	0xc4, 0x1e, 0x4f, 0x6f, 0x5d, 0x30, 0x3e, 0x68, 0x2c, 0x7c, 0x11, 0x8b, 0x86, 0x7c, 0x28, 0x21,
+	0xef, 0xe3, 0x43, 0x03, 0xa9, 0xab, 0x59, 0xe7, 0xed, 0xac, 0xf5, 0x78, 0xd7, 0xb1, 0xc6, 0x11,
+	0xa1, 0xce, 0x3f, 0x1c, 0x40, 0x5f, 0x12, 0x9e, 0x19, 0x91, 0xd0, 0x9d, 0xe5, 0x78, 0x56, 0x5b,
+	0xd4, 0xbe, 0xbb, 0x8a, 0x4d, 0xab, 0xf6, 0xb1, 0x54, 0xed, 0x08, 0x5d, 0x5a, 0x35, 0xf4, 0x83,
+	0x03, 0x9b, 0xaa, 0x0a, 0xd9, 0x9e, 0xba, 0xb7, 0x1c, 0x2f, 0x35, 0x4c, 0xad, 0x0c, 0xb8, 0x47,
+	0x52, 0xa1, 0xce, 0xd1, 0xfd, 0xcb, 0x2a, 0xd4, 0x79, 0x1b, 0x06, 0xef, 0x10, 0x37, 0x45, 0x47,
+	0x31, 0xa1, 0x83, 0x0c, 0x4c, 0x76, 0x22, 0x9b, 0x8b, 0xc2, 0xd4, 0x44, 0x82, 0xef, 0x48, 0x2d,
+	0x0e, 0x70, 0x7b, 0x4e, 0x0b, 0x81, 0x2e, 0x47, 0x1e, 0x71, 0x47, 0xaf, 0xa1, 0x26, 0xab, 0xe5,
+	0xa5, 0x51, 0x57, 0x59, 0x7f, 0x31, 0xee, 0x58, 0x60, 0x09, 0xdc, 0x37, 0xb0, 0x21, 0x5e, 0xac,
+	0xd1, 0x2f, 0x07, 0x7c, 0x57, 0x02, 0xdf, 0xc4, 0xfb, 0x0b, 0x81, 0xa9, 0x04, 0x13, 0xc8, 0x1c,
+	0x1a, 0x5f, 0x12, 0x6e, 0x77, 0xc4, 0x08, 0x2f, 0x69, 0xe8, 0xec, 0x70, 0xbc, 0x7d, 0x21, 0x4f,
+	0xba, 0x4a, 0xa3, 0x69, 0xea, 0x9b, 0x86, 0x10, 0x8d, 0xa0, 0x6a, 0xa1, 0xa2, 0x0f, 0x96, 0x48,
+	0x33, 0x68, 0x07, 0x4b, 0xf7, 0x35, 0xd2, 0x0d, 0x89, 0xb4, 0x87, 0x76, 0xb2, 0x48, 0x2a, 0x98,
+	0xbe, 0x77, 0x60, 0xab, 0x3b, 0x1b, 0x9f, 0x7e, 0x39, 0xdc, 0xdf, 0x4a, 0xdc, 0xbb, 0xf8, 0xd6,
+	0x42, 0xdc, 0x8e, 0x35, 0xb2, 0x09, 0x5f, 0xbf, 0x83, 0x46, 0x37, 0x08, 0x52, 0x13, 0x18, 0xbe,
+	0x68, 0x6c, 0xbb, 0xac, 0x16, 0x73, 0x41, 0x96, 0xd6, 0x22, 0x8a, 0xb9, 0x84, 0xff, 0x16, 0x36,
+	0x52, 0x9d, 0x35, 0xba, 0xbd, 0xa8, 0x43, 0xcb, 0xf4, 0xe8, 0xed, 0x0f, 0x2f, 0x66, 0xd2, 0x2a,
+	0xac, 0xa1, 0xe7, 0x5a, 0xba, 0xe9, 0x26, 0xd1, 0xfe, 0xe2, 0x83, 0x17, 0x48, 0xcd, 0x36, 0xa2,
+	0x78, 0x0d, 0xb9, 0x50, 0xb5, 0xba, 0xc3, 0xb9, 0xfa, 0x3d, 0xdf, 0x39, 0xae, 0x4c, 0x8c, 0xb5,
+	0x17, 0x45, 0xf9, 0x1f, 0xc7, 0xc3, 0xff, 0x0f, 0x00, 0x77, 0x75, 0x22, 0x88, 0x27, 0x19, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ProjectionistServiceClient is the client API for ProjectionistService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ProjectionistServiceClient interface {
+	// auth
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	LoginTwoFactor(ctx context.Context, in *LoginTwoFactorRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*DefaultResponse, error)
+	// change password of request user, other sessions are revoked and new tokens are responded
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	//---------
+	// user
+	NewUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	//---------
+	// service maintenance
+	NewMaintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error)
+	GetMaintenanceList(ctx context.Context, in *MaintenanceListRequest, opts ...grpc.CallOption) (*MaintenanceListResponse, error)
+	DeleteMaintenance(ctx context.Context, in *MaintenanceDeleteRequest, opts ...grpc.CallOption) (*DefaultResponse, error)
+	CheckService(ctx context.Context, in *ServiceIdRequest, opts ...grpc.CallOption) (*CheckResponse, error)
+	PauseService(ctx context.Context, in *ServiceIdRequest, opts ...grpc.CallOption) (*DefaultResponse, error)
+	ResumeService(ctx context.Context, in *ServiceIdRequest, opts ...grpc.CallOption) (*DefaultResponse, error)
+	GetIncidentList(ctx context.Context, in *IncidentListRequest, opts ...grpc.CallOption) (*IncidentListResponse, error)
+	GetIncident(ctx context.Context, in *IncidentRequest, opts ...grpc.CallOption) (*IncidentResponse, error)
+	AcknowledgeIncident(ctx context.Context, in *IncidentRequest, opts ...grpc.CallOption) (*IncidentResponse, error)
+	AddIncidentNote(ctx context.Context, in *IncidentNoteRequest, opts ...grpc.CallOption) (*IncidentResponse, error)
+	// remote health check agents, authorized by agents token
+	AgentRegister(ctx context.Context, in *AgentRegisterRequest, opts ...grpc.CallOption) (*AgentRegisterResponse, error)
+	AgentServices(ctx context.Context, in *AgentRequest, opts ...grpc.CallOption) (*AgentServicesResponse, error)
+	AgentReport(ctx context.Context, in *AgentReportRequest, opts ...grpc.CallOption) (*DefaultResponse, error)
+}
+
+type projectionistServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewProjectionistServiceClient(cc *grpc.ClientConn) ProjectionistServiceClient {
+	return &projectionistServiceClient{cc}
+}
+
+func (c *projectionistServiceClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) LoginTwoFactor(ctx context.Context, in *LoginTwoFactorRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/LoginTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/Refresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*DefaultResponse, error) {
+	out := new(DefaultResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/Logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/ChangePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) NewUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/NewUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) NewMaintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error) {
+	out := new(MaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/NewMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) GetMaintenanceList(ctx context.Context, in *MaintenanceListRequest, opts ...grpc.CallOption) (*MaintenanceListResponse, error) {
+	out := new(MaintenanceListResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/GetMaintenanceList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) DeleteMaintenance(ctx context.Context, in *MaintenanceDeleteRequest, opts ...grpc.CallOption) (*DefaultResponse, error) {
+	out := new(DefaultResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/DeleteMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) CheckService(ctx context.Context, in *ServiceIdRequest, opts ...grpc.CallOption) (*CheckResponse, error) {
+	out := new(CheckResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/CheckService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) PauseService(ctx context.Context, in *ServiceIdRequest, opts ...grpc.CallOption) (*DefaultResponse, error) {
+	out := new(DefaultResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/PauseService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) ResumeService(ctx context.Context, in *ServiceIdRequest, opts ...grpc.CallOption) (*DefaultResponse, error) {
+	out := new(DefaultResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/ResumeService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) GetIncidentList(ctx context.Context, in *IncidentListRequest, opts ...grpc.CallOption) (*IncidentListResponse, error) {
+	out := new(IncidentListResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/GetIncidentList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) GetIncident(ctx context.Context, in *IncidentRequest, opts ...grpc.CallOption) (*IncidentResponse, error) {
+	out := new(IncidentResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/GetIncident", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) AcknowledgeIncident(ctx context.Context, in *IncidentRequest, opts ...grpc.CallOption) (*IncidentResponse, error) {
+	out := new(IncidentResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/AcknowledgeIncident", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) AddIncidentNote(ctx context.Context, in *IncidentNoteRequest, opts ...grpc.CallOption) (*IncidentResponse, error) {
+	out := new(IncidentResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/AddIncidentNote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) AgentRegister(ctx context.Context, in *AgentRegisterRequest, opts ...grpc.CallOption) (*AgentRegisterResponse, error) {
+	out := new(AgentRegisterResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/AgentRegister", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) AgentServices(ctx context.Context, in *AgentRequest, opts ...grpc.CallOption) (*AgentServicesResponse, error) {
+	out := new(AgentServicesResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/AgentServices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectionistServiceClient) AgentReport(ctx context.Context, in *AgentReportRequest, opts ...grpc.CallOption) (*DefaultResponse, error) {
+	out := new(DefaultResponse)
+	err := c.cc.Invoke(ctx, "/projectionist.ProjectionistService/AgentReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProjectionistServiceServer is the server API for ProjectionistService service.
+type ProjectionistServiceServer interface {
+	// auth
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	LoginTwoFactor(context.Context, *LoginTwoFactorRequest) (*LoginResponse, error)
+	Refresh(context.Context, *RefreshRequest) (*LoginResponse, error)
+	Logout(context.Context, *LogoutRequest) (*DefaultResponse, error)
+	// change password of request user, other sessions are revoked and new tokens are responded
+	ChangePassword(context.Context, *ChangePasswordRequest) (*LoginResponse, error)
+	//---------
+	// user
+	NewUser(context.Context, *UserRequest) (*UserResponse, error)
+	//---------
+	// service maintenance
+	NewMaintenance(context.Context, *MaintenanceRequest) (*MaintenanceResponse, error)
+	GetMaintenanceList(context.Context, *MaintenanceListRequest) (*MaintenanceListResponse, error)
+	DeleteMaintenance(context.Context, *MaintenanceDeleteRequest) (*DefaultResponse, error)
+	CheckService(context.Context, *ServiceIdRequest) (*CheckResponse, error)
+	PauseService(context.Context, *ServiceIdRequest) (*DefaultResponse, error)
+	ResumeService(context.Context, *ServiceIdRequest) (*DefaultResponse, error)
+	GetIncidentList(context.Context, *IncidentListRequest) (*IncidentListResponse, error)
+	GetIncident(context.Context, *IncidentRequest) (*IncidentResponse, error)
+	AcknowledgeIncident(context.Context, *IncidentRequest) (*IncidentResponse, error)
+	AddIncidentNote(context.Context, *IncidentNoteRequest) (*IncidentResponse, error)
+	// remote health check agents, authorized by agents token
+	AgentRegister(context.Context, *AgentRegisterRequest) (*AgentRegisterResponse, error)
+	AgentServices(context.Context, *AgentRequest) (*AgentServicesResponse, error)
+	AgentReport(context.Context, *AgentReportRequest) (*DefaultResponse, error)
+}
+
+// UnimplementedProjectionistServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedProjectionistServiceServer struct {
+}
+
+func (*UnimplementedProjectionistServiceServer) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) LoginTwoFactor(ctx context.Context, req *LoginTwoFactorRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginTwoFactor not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) Logout(ctx context.Context, req *LogoutRequest) (*DefaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) ChangePassword(ctx context.Context, req *ChangePasswordRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) NewUser(ctx context.Context, req *UserRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewUser not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) NewMaintenance(ctx context.Context, req *MaintenanceRequest) (*MaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewMaintenance not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) GetMaintenanceList(ctx context.Context, req *MaintenanceListRequest) (*MaintenanceListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMaintenanceList not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) DeleteMaintenance(ctx context.Context, req *MaintenanceDeleteRequest) (*DefaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMaintenance not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) CheckService(ctx context.Context, req *ServiceIdRequest) (*CheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckService not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) PauseService(ctx context.Context, req *ServiceIdRequest) (*DefaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseService not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) ResumeService(ctx context.Context, req *ServiceIdRequest) (*DefaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeService not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) GetIncidentList(ctx context.Context, req *IncidentListRequest) (*IncidentListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIncidentList not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) GetIncident(ctx context.Context, req *IncidentRequest) (*IncidentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIncident not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) AcknowledgeIncident(ctx context.Context, req *IncidentRequest) (*IncidentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcknowledgeIncident not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) AddIncidentNote(ctx context.Context, req *IncidentNoteRequest) (*IncidentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddIncidentNote not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) AgentRegister(ctx context.Context, req *AgentRegisterRequest) (*AgentRegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AgentRegister not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) AgentServices(ctx context.Context, req *AgentRequest) (*AgentServicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AgentServices not implemented")
+}
+func (*UnimplementedProjectionistServiceServer) AgentReport(ctx context.Context, req *AgentReportRequest) (*DefaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AgentReport not implemented")
+}
+
+func RegisterProjectionistServiceServer(s *grpc.Server, srv ProjectionistServiceServer) {
+	s.RegisterService(&_ProjectionistService_serviceDesc, srv)
+}
+
+func _ProjectionistService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_LoginTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).LoginTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/LoginTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).LoginTwoFactor(ctx, req.(*LoginTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).Refresh(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/ChangePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_NewUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).NewUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/NewUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).NewUser(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_NewMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).NewMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/NewMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).NewMaintenance(ctx, req.(*MaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_GetMaintenanceList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaintenanceListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).GetMaintenanceList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/GetMaintenanceList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).GetMaintenanceList(ctx, req.(*MaintenanceListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_DeleteMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaintenanceDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).DeleteMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/DeleteMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).DeleteMaintenance(ctx, req.(*MaintenanceDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_CheckService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).CheckService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/CheckService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).CheckService(ctx, req.(*ServiceIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_PauseService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).PauseService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/PauseService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).PauseService(ctx, req.(*ServiceIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_ResumeService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).ResumeService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/ResumeService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).ResumeService(ctx, req.(*ServiceIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_GetIncidentList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).GetIncidentList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/GetIncidentList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).GetIncidentList(ctx, req.(*IncidentListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_GetIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).GetIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/GetIncident",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).GetIncident(ctx, req.(*IncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_AcknowledgeIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).AcknowledgeIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/AcknowledgeIncident",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).AcknowledgeIncident(ctx, req.(*IncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_AddIncidentNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).AddIncidentNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/AddIncidentNote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).AddIncidentNote(ctx, req.(*IncidentNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_AgentRegister_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentRegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).AgentRegister(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/AgentRegister",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).AgentRegister(ctx, req.(*AgentRegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_AgentServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).AgentServices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/AgentServices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).AgentServices(ctx, req.(*AgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectionistService_AgentReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectionistServiceServer).AgentReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/projectionist.ProjectionistService/AgentReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectionistServiceServer).AgentReport(ctx, req.(*AgentReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ProjectionistService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "projectionist.ProjectionistService",
+	HandlerType: (*ProjectionistServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Login",
+			Handler:    _ProjectionistService_Login_Handler,
+		},
+		{
+			MethodName: "LoginTwoFactor",
+			Handler:    _ProjectionistService_LoginTwoFactor_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _ProjectionistService_Refresh_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _ProjectionistService_Logout_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _ProjectionistService_ChangePassword_Handler,
+		},
 		{
 			MethodName: "NewUser",
 			Handler:    _ProjectionistService_NewUser_Handler,
 		},
+		{
+			MethodName: "NewMaintenance",
+			Handler:    _ProjectionistService_NewMaintenance_Handler,
+		},
+		{
+			MethodName: "GetMaintenanceList",
+			Handler:    _ProjectionistService_GetMaintenanceList_Handler,
+		},
+		{
+			MethodName: "DeleteMaintenance",
+			Handler:    _ProjectionistService_DeleteMaintenance_Handler,
+		},
+		{
+			MethodName: "CheckService",
+			Handler:    _ProjectionistService_CheckService_Handler,
+		},
+		{
+			MethodName: "PauseService",
+			Handler:    _ProjectionistService_PauseService_Handler,
+		},
+		{
+			MethodName: "ResumeService",
+			Handler:    _ProjectionistService_ResumeService_Handler,
+		},
+		{
+			MethodName: "GetIncidentList",
+			Handler:    _ProjectionistService_GetIncidentList_Handler,
+		},
+		{
+			MethodName: "GetIncident",
+			Handler:    _ProjectionistService_GetIncident_Handler,
+		},
+		{
+			MethodName: "AcknowledgeIncident",
+			Handler:    _ProjectionistService_AcknowledgeIncident_Handler,
+		},
+		{
+			MethodName: "AddIncidentNote",
+			Handler:    _ProjectionistService_AddIncidentNote_Handler,
+		},
+		{
+			MethodName: "AgentRegister",
+			Handler:    _ProjectionistService_AgentRegister_Handler,
+		},
+		{
+			MethodName: "AgentServices",
+			Handler:    _ProjectionistService_AgentServices_Handler,
+		},
+		{
+			MethodName: "AgentReport",
+			Handler:    _ProjectionistService_AgentReport_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "projectionist.proto",
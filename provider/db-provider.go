@@ -35,6 +35,24 @@ func (p *DBProvider) Save(m models.Model) error {
 	case *models.Email:
 		e := m.(*models.Email)
 		return saveProcessErrBusy(p.db, e.Save)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return saveProcessErrBusy(p.db, w.Save)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return saveProcessErrBusy(p.db, i.Save)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return saveProcessErrBusy(p.db, ep.Save)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return saveProcessErrBusy(p.db, nt.Save)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return saveProcessErrBusy(p.db, d.Save)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return saveProcessErrBusy(p.db, rs.Save)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
@@ -53,6 +71,24 @@ func (p *DBProvider) GetByName(m models.Model, name string) (models.Model, error
 	case *models.Email:
 		e := m.(*models.Email)
 		return e, e.GetByName(p.db, name)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return w, w.GetByName(p.db, name)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return i, i.GetByName(p.db, name)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return ep, ep.GetByName(p.db, name)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return nt, nt.GetByName(p.db, name)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return d, d.GetByName(p.db, name)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return rs, rs.GetByName(p.db, name)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
@@ -71,6 +107,24 @@ func (p *DBProvider) GetByID(m models.Model, id int64) (models.Model, error) {
 	case *models.Email:
 		e := m.(*models.Email)
 		return e, e.GetByID(p.db, id)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return w, w.GetByID(p.db, id)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return i, i.GetByID(p.db, id)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return ep, ep.GetByID(p.db, id)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return nt, nt.GetByID(p.db, id)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return d, d.GetByID(p.db, id)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return rs, rs.GetByID(p.db, id)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
@@ -89,6 +143,24 @@ func (p *DBProvider) IsExistByName(m models.Model) (error, bool) {
 	case *models.Email:
 		e := m.(*models.Email)
 		return e.IsExistByName(p.db)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return w.IsExistByName(p.db)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return i.IsExistByName(p.db)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return ep.IsExistByName(p.db)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return nt.IsExistByName(p.db)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return d.IsExistByName(p.db)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return rs.IsExistByName(p.db)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
@@ -107,6 +179,24 @@ func (p *DBProvider) Count(m models.Model) (int, error) {
 	case *models.Email:
 		e := m.(*models.Email)
 		return e.Count(p.db)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return w.Count(p.db)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return i.Count(p.db)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return ep.Count(p.db)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return nt.Count(p.db)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return d.Count(p.db)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return rs.Count(p.db)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
@@ -125,6 +215,24 @@ func (p *DBProvider) Pagination(m models.Model, start, stop int) ([]models.Model
 	case *models.Email:
 		e := m.(*models.Email)
 		return e.Pagination(p.db, start, stop)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return w.Pagination(p.db, start, stop)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return i.Pagination(p.db, start, stop)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return ep.Pagination(p.db, start, stop)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return nt.Pagination(p.db, start, stop)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return d.Pagination(p.db, start, stop)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return rs.Pagination(p.db, start, stop)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
@@ -143,6 +251,24 @@ func (p *DBProvider) Update(m models.Model, id int) error {
 	case *models.Email:
 		e := m.(*models.Email)
 		return processErrBusy(p.db, id, e.Update)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return processErrBusy(p.db, id, w.Update)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return processErrBusy(p.db, id, i.Update)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return processErrBusy(p.db, id, ep.Update)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return processErrBusy(p.db, id, nt.Update)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return processErrBusy(p.db, id, d.Update)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return processErrBusy(p.db, id, rs.Update)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
@@ -161,6 +287,24 @@ func (p *DBProvider) Delete(m models.Model, id int) error {
 	case *models.Email:
 		e := m.(*models.Email)
 		return processErrBusy(p.db, id, e.Delete)
+	case *models.MaintenanceWindow:
+		w := m.(*models.MaintenanceWindow)
+		return processErrBusy(p.db, id, w.Delete)
+	case *models.Incident:
+		i := m.(*models.Incident)
+		return processErrBusy(p.db, id, i.Delete)
+	case *models.EscalationPolicy:
+		ep := m.(*models.EscalationPolicy)
+		return processErrBusy(p.db, id, ep.Delete)
+	case *models.NotificationTemplate:
+		nt := m.(*models.NotificationTemplate)
+		return processErrBusy(p.db, id, nt.Delete)
+	case *models.Delivery:
+		d := m.(*models.Delivery)
+		return processErrBusy(p.db, id, d.Delete)
+	case *models.RecipientSettings:
+		rs := m.(*models.RecipientSettings)
+		return processErrBusy(p.db, id, rs.Delete)
 	default:
 		err := errors.ErrUnknownModel
 		err.SetArgs("type", fmt.Sprintf("%T", m))
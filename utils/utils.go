@@ -29,8 +29,13 @@ func JsonRespond(w http.ResponseWriter, data map[string]interface{}) {
 
 // GetIDFromReq get id parameter from request
 func GetIDFromReq(r *http.Request) (int, error) {
+	return GetIntVarFromReq(r, "id")
+}
+
+// GetIntVarFromReq get number url parameter by name from request
+func GetIntVarFromReq(r *http.Request, name string) (int, error) {
 	var params = mux.Vars(r)
-	idStr, ok := params["id"]
+	idStr, ok := params[name]
 	if !ok {
 		return 0, fmt.Errorf(strings.ToLower(consts.IdIsEmptyResp))
 	}
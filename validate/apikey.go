@@ -0,0 +1,49 @@
+package validate
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"projectionist/models"
+)
+
+// ErrAPIKeyInvalid - api key is unknown, revoked or expired, or its owner is deleted
+var ErrAPIKeyInvalid = fmt.Errorf("api key is revoked or expired")
+
+// AuthenticateAPIKey - claims of owner of active api key limited by key scopes, last used time of key is updated
+func AuthenticateAPIKey(db *sql.DB, key string, now time.Time) (*models.Token, error) {
+	apiKey, err := models.GetAPIKeyByKey(db, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+
+	if !apiKey.IsActive(now) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	var user = &models.User{}
+	err = user.GetByID(db, int64(apiKey.UserID))
+	if err == sql.ErrNoRows || (err == nil && user.IsDeleted()) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = apiKey.MarkUsed(db, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Token{
+		UserId:         uint64(user.ID),
+		Role:           user.Role,
+		PasswordChange: user.PasswordChangeRequired,
+		APIKeyID:       apiKey.ID,
+		Scopes:         apiKey.Scopes,
+	}, nil
+}
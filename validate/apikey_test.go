@@ -0,0 +1,79 @@
+package validate
+
+import (
+	"testing"
+	"time"
+
+	"projectionist/models"
+)
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var user = &models.User{Username: "ci", Password: "password", Role: models.Admin}
+	if err := user.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	var now = time.Now()
+	var expiresAt = now.Add(time.Hour)
+	key, err := models.NewAPIKeyValue()
+	if err != nil {
+		t.Fatalf("NewAPIKeyValue() error: %v", err)
+	}
+
+	var apiKey = &models.APIKey{
+		UserID:    user.ID,
+		Name:      "ci",
+		Scopes:    []models.Scope{{Resource: models.ResourceService, Action: models.ActionWrite}},
+		CreatedAt: now,
+		ExpiresAt: &expiresAt,
+	}
+	apiKey.SetKey(key)
+	if err = apiKey.Save(sqlDB); err != nil {
+		t.Fatalf("api key Save() error: %v", err)
+	}
+
+	tokenM, err := Authenticate(sqlDB, "Bearer "+key, "secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error: %v", err)
+	}
+
+	if tokenM.UserId != uint64(user.ID) || tokenM.APIKeyID != apiKey.ID {
+		t.Errorf("Authenticate() claims = %+v", tokenM)
+	}
+
+	if !tokenM.Can(models.ResourceService, models.ActionWrite) {
+		t.Errorf("api key is not allowed scope action")
+	}
+
+	if tokenM.Can(models.ResourceService, models.ActionDelete) {
+		t.Errorf("api key is allowed action out of scopes")
+	}
+
+	used, err := models.GetAPIKey(sqlDB, apiKey.ID)
+	if err != nil {
+		t.Fatalf("GetAPIKey() error: %v", err)
+	}
+
+	if used.LastUsedAt == nil {
+		t.Errorf("last used time of api key is not updated")
+	}
+
+	if _, err = AuthenticateAPIKey(sqlDB, key, expiresAt.Add(time.Second)); err != ErrAPIKeyInvalid {
+		t.Errorf("AuthenticateAPIKey() of expired key error = %v, want %v", err, ErrAPIKeyInvalid)
+	}
+
+	if err = apiKey.Revoke(sqlDB, now); err != nil {
+		t.Fatalf("Revoke() error: %v", err)
+	}
+
+	if _, err = AuthenticateAPIKey(sqlDB, key, now); err != ErrAPIKeyInvalid {
+		t.Errorf("AuthenticateAPIKey() of revoked key error = %v, want %v", err, ErrAPIKeyInvalid)
+	}
+
+	if _, err = AuthenticateAPIKey(sqlDB, models.APIKeyPrefix+"unknown", now); err != ErrAPIKeyInvalid {
+		t.Errorf("AuthenticateAPIKey() of unknown key error = %v, want %v", err, ErrAPIKeyInvalid)
+	}
+}
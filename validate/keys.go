@@ -0,0 +1,392 @@
+package validate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+const rsaKeyBits = 2048
+
+// JWKSMaxAge - time JWK set may be cached by services verifying tokens
+const JWKSMaxAge = 5 * time.Minute
+
+// keyPublishLead - time next key is in JWK set before it signs, cached JWK sets know it before its tokens
+const keyPublishLead = 2 * JWKSMaxAge
+
+// KeySet - keys of access tokens, the current key signs, the next key is published before it signs
+// and retired keys verify tokens until they expire,
+// in HS256 mode tokens are signed and verified by shared secret
+type KeySet struct {
+	mu        sync.RWMutex
+	db        *sql.DB
+	method    jwt.SigningMethod
+	secret    []byte        // shared secret of HS256
+	rotation  time.Duration // age of signing key before rotation, 0 - no rotation
+	retention time.Duration // time retired key verifies tokens
+	keys      []*signingKey // newest first, at most one pending key is newer than current key
+}
+
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	retiredAt *time.Time
+	model     *models.SigningKey
+}
+
+// JSONWebKey - public key of JWK set
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+var (
+	keySetMu  sync.RWMutex
+	keySetCur *KeySet
+)
+
+// SetKeySet - keys signing and verifying tokens of the process, tokens are signed by token secret key if it is not set
+func SetKeySet(keys *KeySet) {
+	keySetMu.Lock()
+	keySetCur = keys
+	keySetMu.Unlock()
+}
+
+// tokenKeys - key set of the process or shared secret key set
+func tokenKeys(tokenSecretKey string) *KeySet {
+	keySetMu.RLock()
+	defer keySetMu.RUnlock()
+
+	if keySetCur != nil {
+		return keySetCur
+	}
+
+	return NewSecretKeySet(tokenSecretKey)
+}
+
+// TokenKeyfunc - key verifying access token by its algorithm and key id
+func TokenKeyfunc(tokenSecretKey string) jwt.Keyfunc {
+	return tokenKeys(tokenSecretKey).Keyfunc
+}
+
+// NewSecretKeySet - key set signing by shared secret with HS256
+func NewSecretKeySet(secret string) *KeySet {
+	return &KeySet{method: jwt.SigningMethodHS256, secret: []byte(secret)}
+}
+
+// NewKeySet - key set of config algorithm, keys are loaded from database and the first key is generated
+// if there are no keys of algorithm yet
+func NewKeySet(db *sql.DB, cfg *config.Config, now time.Time) (*KeySet, error) {
+	switch cfg.Auth.Signing.Algorithm {
+	case jwt.SigningMethodHS256.Name:
+		return NewSecretKeySet(cfg.TokenSecretKey), nil
+	case jwt.SigningMethodRS256.Name, jwt.SigningMethodES256.Name:
+	default:
+		return nil, fmt.Errorf("unsupported token signing algorithm %q", cfg.Auth.Signing.Algorithm)
+	}
+
+	var keys = &KeySet{
+		db:     db,
+		method: jwt.GetSigningMethod(cfg.Auth.Signing.Algorithm),
+		// retired key verifies the longest living token signed by it
+		retention: time.Duration(cfg.Auth.AccessTokenTTL) * time.Second,
+	}
+	if keys.retention < twoFactorTokenTTL {
+		keys.retention = twoFactorTokenTTL
+	}
+	if cfg.Auth.Signing.RotationInterval > 0 {
+		keys.rotation = time.Duration(cfg.Auth.Signing.RotationInterval) * time.Second
+	}
+
+	saved, err := models.GetSigningKeys(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, model := range saved {
+		key, err := parseSigningKey(model)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s error: %v", model.Kid, err)
+		}
+		keys.keys = append(keys.keys, key)
+	}
+
+	return keys, keys.RotateIfDue(now)
+}
+
+// RotateIfDue - publish next signing key before current key is older than rotation interval
+// or has other algorithm, next key starts signing when it is published longer than JWK set is cached
+// even if rotation is disabled.
+// Retired keys of expired tokens are removed
+func (k *KeySet) RotateIfDue(now time.Time) error {
+	if k.secret != nil {
+		return nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var current, pending = k.current(), k.pending()
+
+	// next key of previous algorithm never signed
+	if pending != nil && pending.method != k.method {
+		if err := k.retire(pending, now); err != nil {
+			return err
+		}
+		pending = nil
+	}
+
+	// the first key has no tokens to verify yet, it signs immediately
+	if current == nil {
+		if _, err := k.generate(now); err != nil {
+			return err
+		}
+		current = k.current()
+	}
+
+	var age = now.Sub(current.model.CreatedAt)
+	var due = current.method != k.method || (k.rotation > 0 && age >= k.rotation)
+	if pending == nil && (due || (k.rotation > 0 && age >= k.rotation-keyPublishLead)) {
+		var err error
+		if pending, err = k.generate(now); err != nil {
+			return err
+		}
+	}
+
+	// published key signs whatever rotation interval is, e.g. after algorithm change without rotation
+	if pending != nil && now.Sub(pending.model.CreatedAt) >= keyPublishLead {
+		if err := k.retire(current, now); err != nil {
+			return err
+		}
+	}
+
+	var retiredBefore = now.Add(-k.retention)
+	err := models.DeleteSigningKeysRetiredBefore(k.db, retiredBefore)
+	if err != nil {
+		return err
+	}
+
+	var keys = k.keys[:0]
+	for _, key := range k.keys {
+		if key.retiredAt == nil || !key.retiredAt.Before(retiredBefore) {
+			keys = append(keys, key)
+		}
+	}
+	k.keys = keys
+
+	return nil
+}
+
+// generate - new key of key set algorithm, it is published at once and signs after older active key is retired
+func (k *KeySet) generate(now time.Time) (*signingKey, error) {
+	private, err := generateKey(k.method)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	var kidBuf = make([]byte, 8)
+	if _, err = rand.Read(kidBuf); err != nil {
+		return nil, err
+	}
+
+	var model = &models.SigningKey{
+		Kid:        hex.EncodeToString(kidBuf),
+		Algorithm:  k.method.Alg(),
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		CreatedAt:  now,
+	}
+	if err = model.Save(k.db); err != nil {
+		return nil, err
+	}
+
+	var key = &signingKey{kid: model.Kid, method: k.method, private: private, model: model}
+	k.keys = append([]*signingKey{key}, k.keys...)
+
+	return key, nil
+}
+
+// retire - stop signing by key, it verifies tokens until retention is over
+func (k *KeySet) retire(key *signingKey, now time.Time) error {
+	if err := key.model.Retire(k.db, now); err != nil {
+		return err
+	}
+	key.retiredAt = key.model.RetiredAt
+
+	return nil
+}
+
+// current - the oldest not retired key, it signs new tokens, nil if there are no active keys
+func (k *KeySet) current() *signingKey {
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if k.keys[i].retiredAt == nil {
+			return k.keys[i]
+		}
+	}
+
+	return nil
+}
+
+// pending - published key signing after current key, nil if there is no next key
+func (k *KeySet) pending() *signingKey {
+	if len(k.keys) == 0 || k.keys[0].retiredAt != nil || k.keys[0] == k.current() {
+		return nil
+	}
+
+	return k.keys[0]
+}
+
+// Sign - token of claims signed by current key, key id is set in token header
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if k.secret != nil {
+		return jwt.NewWithClaims(k.method, claims).SignedString(k.secret)
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	var current = k.current()
+	if current == nil {
+		return "", fmt.Errorf("no active signing key")
+	}
+
+	var token = jwt.NewWithClaims(current.method, claims)
+	token.Header["kid"] = current.kid
+	return token.SignedString(current.private)
+}
+
+// Keyfunc - key verifying token, token algorithm must match algorithm of key
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if k.secret != nil {
+		if token.Method != k.method {
+			return nil, fmt.Errorf("unexpected signing algorithm %s", token.Method.Alg())
+		}
+		return k.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.kid != kid {
+			continue
+		}
+
+		if token.Method != key.method {
+			return nil, fmt.Errorf("unexpected signing algorithm %s", token.Method.Alg())
+		}
+
+		return key.private.Public(), nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// JWKS - public keys verifying tokens, empty for shared secret
+func (k *KeySet) JWKS() []JSONWebKey {
+	var keys = []JSONWebKey{}
+	if k.secret != nil {
+		return keys
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		var jwk = JSONWebKey{Kid: key.kid, Use: "sig", Alg: key.method.Alg()}
+		switch public := key.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			var size = (public.Curve.Params().BitSize + 7) / 8
+			jwk.Kty = "EC"
+			jwk.Crv = public.Curve.Params().Name
+			jwk.X = base64.RawURLEncoding.EncodeToString(padBytes(public.X.Bytes(), size))
+			jwk.Y = base64.RawURLEncoding.EncodeToString(padBytes(public.Y.Bytes(), size))
+		default:
+			continue
+		}
+
+		keys = append(keys, jwk)
+	}
+
+	return keys
+}
+
+func generateKey(method jwt.SigningMethod) (crypto.Signer, error) {
+	switch method {
+	case jwt.SigningMethodRS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case jwt.SigningMethodES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %s", method.Alg())
+	}
+}
+
+func parseSigningKey(model *models.SigningKey) (*signingKey, error) {
+	var method = jwt.GetSigningMethod(model.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported signing algorithm %s", model.Algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(model.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("invalid pem")
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key")
+	}
+
+	return &signingKey{kid: model.Kid, method: method, private: signer, retiredAt: model.RetiredAt, model: model}, nil
+}
+
+// padBytes - big-endian number of fixed size
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	var padded = make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
@@ -0,0 +1,267 @@
+package validate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+func TestKeySet(t *testing.T) {
+	for _, alg := range []string{"RS256", "ES256"} {
+		t.Run(alg, func(t *testing.T) {
+			sqlDB, cleanup := newSessionDB(t)
+			defer cleanup()
+
+			var cfg = &config.Config{TokenSecretKey: "secret", Auth: config.AuthCfg{
+				AccessTokenTTL: 600, RefreshTokenTTL: 3600,
+				LoginFailures: 5, IPLoginFailures: 20, LockoutDuration: 60,
+				Signing: config.SigningCfg{Algorithm: alg, RotationInterval: 3600},
+			}}
+
+			var now = time.Now()
+			keys, err := NewKeySet(sqlDB, cfg, now)
+			if err != nil {
+				t.Fatalf("NewKeySet() error: %v", err)
+			}
+			SetKeySet(keys)
+			defer SetKeySet(nil)
+
+			var user = &models.User{Username: "alice", Password: "password", Role: models.Admin}
+			if err = user.Save(sqlDB); err != nil {
+				t.Fatalf("user Save() error: %v", err)
+			}
+
+			tokens, err := NewSession(sqlDB, cfg, user, now)
+			if err != nil {
+				t.Fatalf("NewSession() error: %v", err)
+			}
+
+			if _, err = Authenticate(sqlDB, "Bearer "+tokens.AccessToken, cfg.TokenSecretKey); err != nil {
+				t.Fatalf("Authenticate() error: %v", err)
+			}
+
+			// asymmetric tokens do not depend on token secret key
+			if _, err = ParseToken("Bearer "+tokens.AccessToken, "other"); err != nil {
+				t.Errorf("ParseToken() depends on token secret key: %v", err)
+			}
+
+			hmacToken, _ := NewSecretKeySet(cfg.TokenSecretKey).Sign(&models.Token{UserId: uint64(user.ID)})
+			if _, err = ParseToken("Bearer "+hmacToken, cfg.TokenSecretKey); err == nil {
+				t.Errorf("ParseToken() accepts HS256 token of shared secret")
+			}
+
+			unknown := jwt.NewWithClaims(jwt.SigningMethodHS256, &models.Token{UserId: uint64(user.ID)})
+			unknown.Header["kid"] = keys.JWKS()[0].Kid
+			forged, _ := unknown.SignedString([]byte(cfg.TokenSecretKey))
+			if _, err = ParseToken("Bearer "+forged, cfg.TokenSecretKey); err == nil {
+				t.Errorf("ParseToken() accepts token of other algorithm with known key id")
+			}
+
+			// not due yet
+			if err = keys.RotateIfDue(now.Add(time.Minute)); err != nil {
+				t.Fatalf("RotateIfDue() error: %v", err)
+			}
+			if got := len(keys.JWKS()); got != 1 {
+				t.Fatalf("JWKS() has %d keys before rotation, want 1", got)
+			}
+
+			// next key is published before rotation, current key still signs
+			var firstKid = keys.JWKS()[0].Kid
+			var rotation = time.Duration(cfg.Auth.Signing.RotationInterval) * time.Second
+			if err = keys.RotateIfDue(now.Add(rotation - keyPublishLead)); err != nil {
+				t.Fatalf("RotateIfDue() error: %v", err)
+			}
+
+			var published = keys.JWKS()
+			if len(published) != 2 {
+				t.Fatalf("JWKS() has %d keys before rotation, want 2", len(published))
+			}
+			if kid := signingKid(t, keys); kid != firstKid {
+				t.Errorf("token is signed by published key %s before rotation, want %s", kid, firstKid)
+			}
+
+			// next key signs only after cached JWK sets know it
+			if err = keys.RotateIfDue(now.Add(rotation - time.Second)); err != nil {
+				t.Fatalf("RotateIfDue() error: %v", err)
+			}
+			if kid := signingKid(t, keys); kid != firstKid {
+				t.Errorf("token is signed by key %s before rotation interval, want %s", kid, firstKid)
+			}
+
+			// rotated key verifies issued tokens
+			var rotatedAt = now.Add(rotation)
+			if err = keys.RotateIfDue(rotatedAt); err != nil {
+				t.Fatalf("RotateIfDue() error: %v", err)
+			}
+
+			var jwks = keys.JWKS()
+			if len(jwks) != 2 {
+				t.Fatalf("JWKS() has %d keys after rotation, want 2", len(jwks))
+			}
+
+			var signingAfter = signingKid(t, keys)
+			if signingAfter == firstKid {
+				t.Errorf("token is signed by retired key %s", firstKid)
+			}
+
+			var wasPublished bool
+			for _, jwk := range published {
+				wasPublished = wasPublished || jwk.Kid == signingAfter
+			}
+			if !wasPublished {
+				t.Errorf("JWKS() before rotation has no key %s signing after it", signingAfter)
+			}
+			for _, jwk := range jwks {
+				if jwk.Alg != alg || jwk.Use != "sig" || jwk.Kid == "" {
+					t.Errorf("JWKS() key = %+v", jwk)
+				}
+				if alg == "RS256" && (jwk.Kty != "RSA" || jwk.N == "" || jwk.E != "AQAB") {
+					t.Errorf("JWKS() RSA key = %+v", jwk)
+				}
+				if alg == "ES256" && (jwk.Kty != "EC" || jwk.Crv != "P-256" || len(jwk.X) != 43 || len(jwk.Y) != 43) {
+					t.Errorf("JWKS() EC key = %+v", jwk)
+				}
+			}
+
+			if _, err = ParseToken("Bearer "+tokens.AccessToken, cfg.TokenSecretKey); err != nil {
+				t.Errorf("ParseToken() of token signed by rotated key error: %v", err)
+			}
+
+			signed, err := keys.Sign(&models.Token{UserId: uint64(user.ID)})
+			if err != nil {
+				t.Fatalf("Sign() error: %v", err)
+			}
+			parsed, _ := jwt.Parse(signed, keys.Keyfunc)
+			if parsed == nil || parsed.Header["kid"] != jwks[0].Kid {
+				t.Errorf("token is not signed by the newest key %s", jwks[0].Kid)
+			}
+
+			// keys are loaded from database by other instance
+			reloaded, err := NewKeySet(sqlDB, cfg, rotatedAt)
+			if err != nil {
+				t.Fatalf("NewKeySet() reload error: %v", err)
+			}
+			if got := len(reloaded.JWKS()); got != 2 {
+				t.Errorf("reloaded JWKS() has %d keys, want 2", got)
+			}
+			if _, err = jwt.Parse(signed, reloaded.Keyfunc); err != nil {
+				t.Errorf("reloaded key set does not verify token: %v", err)
+			}
+
+			// retired key is removed after tokens signed by it expire
+			if err = keys.RotateIfDue(rotatedAt.Add(11 * time.Minute)); err != nil {
+				t.Fatalf("RotateIfDue() error: %v", err)
+			}
+			if got := len(keys.JWKS()); got != 1 {
+				t.Errorf("JWKS() has %d keys after retention, want 1", got)
+			}
+			if _, err = ParseToken("Bearer "+tokens.AccessToken, cfg.TokenSecretKey); err == nil {
+				t.Errorf("ParseToken() accepts token of removed key")
+			}
+		})
+	}
+}
+
+// signingKid - key id of token signed by key set
+func signingKid(t *testing.T, keys *KeySet) string {
+	signed, err := keys.Sign(&models.Token{})
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(signed, &models.Token{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error: %v", err)
+	}
+
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeySet_NoRotation(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{TokenSecretKey: "secret", Auth: config.AuthCfg{
+		AccessTokenTTL: 600,
+		Signing:        config.SigningCfg{Algorithm: "RS256", RotationInterval: 3600},
+	}}
+
+	var now = time.Now()
+	keys, err := NewKeySet(sqlDB, cfg, now)
+	if err != nil {
+		t.Fatalf("NewKeySet() error: %v", err)
+	}
+
+	// next key is published, then rotation is disabled
+	var publishedAt = now.Add(time.Hour - keyPublishLead)
+	if err = keys.RotateIfDue(publishedAt); err != nil {
+		t.Fatalf("RotateIfDue() error: %v", err)
+	}
+	if got := len(keys.JWKS()); got != 2 {
+		t.Fatalf("JWKS() has %d keys, want 2", got)
+	}
+	var firstKid, pendingKid = signingKid(t, keys), keys.JWKS()[0].Kid
+
+	cfg.Auth.Signing.RotationInterval = 0
+	keys, err = NewKeySet(sqlDB, cfg, publishedAt)
+	if err != nil {
+		t.Fatalf("NewKeySet() without rotation error: %v", err)
+	}
+	if kid := signingKid(t, keys); kid != firstKid {
+		t.Errorf("token is signed by key %s before it is published long enough, want %s", kid, firstKid)
+	}
+
+	if err = keys.RotateIfDue(publishedAt.Add(keyPublishLead)); err != nil {
+		t.Fatalf("RotateIfDue() error: %v", err)
+	}
+	if kid := signingKid(t, keys); kid != pendingKid {
+		t.Errorf("token is signed by key %s without rotation, want published key %s", kid, pendingKid)
+	}
+
+	// algorithm change publishes key of new algorithm, it signs after publish lead
+	var changedAt = publishedAt.Add(2 * keyPublishLead)
+	cfg.Auth.Signing.Algorithm = "ES256"
+	keys, err = NewKeySet(sqlDB, cfg, changedAt)
+	if err != nil {
+		t.Fatalf("NewKeySet() of other algorithm error: %v", err)
+	}
+	if kid := signingKid(t, keys); kid != pendingKid {
+		t.Errorf("token is signed by key %s before key of new algorithm is published long enough, want %s", kid, pendingKid)
+	}
+
+	if err = keys.RotateIfDue(changedAt.Add(keyPublishLead)); err != nil {
+		t.Fatalf("RotateIfDue() error: %v", err)
+	}
+	signed, err := keys.Sign(&models.Token{})
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	parsed, err := jwt.Parse(signed, keys.Keyfunc)
+	if err != nil || parsed.Method != jwt.SigningMethodES256 {
+		t.Errorf("token is not signed by key of new algorithm: %v", err)
+	}
+}
+
+func TestNewKeySetAlgorithm(t *testing.T) {
+	var cfg = &config.Config{TokenSecretKey: "secret", Auth: config.AuthCfg{
+		Signing: config.SigningCfg{Algorithm: "HS256"},
+	}}
+
+	keys, err := NewKeySet(nil, cfg, time.Now())
+	if err != nil {
+		t.Fatalf("NewKeySet() of HS256 error: %v", err)
+	}
+	if got := len(keys.JWKS()); got != 0 {
+		t.Errorf("JWKS() of shared secret has %d keys", got)
+	}
+
+	cfg.Auth.Signing.Algorithm = "none"
+	if _, err = NewKeySet(nil, cfg, time.Now()); err == nil {
+		t.Errorf("NewKeySet() accepts unsupported algorithm")
+	}
+}
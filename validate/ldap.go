@@ -0,0 +1,141 @@
+package validate
+
+import (
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+var (
+	// errLDAPNoUser - directory has no entry of username
+	errLDAPNoUser = fmt.Errorf("ldap user not found")
+	// errLDAPNoRole - none of directory groups of user is mapped to role and default role is not set
+	errLDAPNoRole = fmt.Errorf("ldap user has no role")
+)
+
+// ldapEntry - directory entry of user with accepted password
+type ldapEntry struct {
+	DN     string
+	Groups []string
+}
+
+// ldapAuthenticate - entry of username found by service account and bound by user password,
+// ErrNotAuthorized is returned for wrong password and errLDAPNoUser for unknown username
+func ldapAuthenticate(cfg *config.LDAPCfg, username, password string) (*ldapEntry, error) {
+	// empty password is unauthenticated bind which succeeds for any entry
+	if password == "" {
+		return nil, ErrNotAuthorized
+	}
+
+	var timeout = time.Duration(cfg.Timeout) * time.Second
+	serverURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	var tlsConfig = &tls.Config{ServerName: serverURL.Hostname(), InsecureSkipVerify: cfg.TLSSkipVerify}
+
+	conn, err := ldap.DialURL(
+		cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetTimeout(timeout)
+
+	if cfg.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.BindDN != "" {
+		if err = conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("service account bind error: %v", err)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		cfg.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2, // more than one entry is ambiguous
+		int(cfg.Timeout),
+		false,
+		fmt.Sprintf(cfg.UserFilter, ldap.EscapeFilter(username)),
+		[]string{cfg.GroupAttribute},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("user search error: %v", err)
+	}
+
+	switch len(result.Entries) {
+	case 0:
+		return nil, errLDAPNoUser
+	case 1:
+	default:
+		return nil, fmt.Errorf("ldap filter matched %d entries of %s", len(result.Entries), username)
+	}
+
+	var entry = result.Entries[0]
+	err = conn.Bind(entry.DN, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil, ErrNotAuthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ldapEntry{DN: entry.DN, Groups: entry.GetAttributeValues(cfg.GroupAttribute)}, nil
+}
+
+// loginLDAP - user of directory entry with accepted password, user is created on first login
+// and its role follows directory groups on every login
+func loginLDAP(db *sql.DB, cfg *config.LDAPCfg, user *models.User, username, password, ip string, now time.Time) (*models.User, error) {
+	entry, err := ldapAuthenticate(cfg, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	var role = groupsRole(cfg.RoleGroups, cfg.DefaultRole, entry.Groups)
+	if role == 0 {
+		return nil, errLDAPNoRole
+	}
+
+	if user == nil {
+		user, err = provisionUser(db, username, role, ip, now, "ldap entry "+entry.DN)
+		if err != nil {
+			return nil, err
+		}
+
+		return user, user.SetLDAPDN(db, entry.DN)
+	}
+
+	return user, syncRole(db, user, role)
+}
+
+// isLDAPUser - true if password of user is checked by directory, false for local users
+func isLDAPUser(db *sql.DB, cfg *config.LDAPCfg, user *models.User) (bool, error) {
+	if cfg.URL == "" {
+		return false, nil
+	}
+
+	// unknown users are looked up in directory
+	if user == nil {
+		return true, nil
+	}
+
+	dn, err := user.LDAPDN(db)
+	return dn != "", err
+}
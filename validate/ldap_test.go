@@ -0,0 +1,216 @@
+package validate
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+const (
+	ldapBindRequest     = 0
+	ldapBindResponse    = 1
+	ldapUnbindRequest   = 2
+	ldapSearchRequest   = 3
+	ldapSearchEntry     = 4
+	ldapSearchDone      = 5
+	ldapServiceDN       = "cn=service,dc=example,dc=org"
+	ldapServicePassword = "service"
+)
+
+// stubLDAPEntry - user entry of in-process directory
+type stubLDAPEntry struct {
+	uid      string
+	password string
+	groups   []string
+}
+
+// stubLDAPServer - in-process LDAP server supporting simple bind and equality search by uid
+type stubLDAPServer struct {
+	listener net.Listener
+	entries  map[string]stubLDAPEntry // by dn
+}
+
+func newStubLDAPServer(t *testing.T, entries map[string]stubLDAPEntry) *stubLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+
+	var s = &stubLDAPServer{listener: listener, entries: entries}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *stubLDAPServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *stubLDAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	var boundDN string
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		var messageID = packet.Children[0].Value.(int64)
+		var op = packet.Children[1]
+		switch op.Tag {
+		case ldapBindRequest:
+			var dn = op.Children[1].Value.(string)
+			var password = op.Children[2].Data.String()
+			var code = ldap.LDAPResultInvalidCredentials
+			if entry, ok := s.entries[dn]; (ok && entry.password == password) ||
+				(dn == ldapServiceDN && password == ldapServicePassword) {
+				code = ldap.LDAPResultSuccess
+				boundDN = dn
+			}
+			conn.Write(ldapResponse(messageID, ldapResult(ldapBindResponse, code)).Bytes())
+		case ldapSearchRequest:
+			filter, _ := ldap.DecompileFilter(op.Children[6])
+			if boundDN == ldapServiceDN {
+				for dn, entry := range s.entries {
+					if filter == fmt.Sprintf("(uid=%s)", ldap.EscapeFilter(entry.uid)) {
+						conn.Write(ldapResponse(messageID, ldapSearchEntryPacket(dn, entry)).Bytes())
+					}
+				}
+			}
+			conn.Write(ldapResponse(messageID, ldapResult(ldapSearchDone, ldap.LDAPResultSuccess)).Bytes())
+		case ldapUnbindRequest:
+			return
+		}
+	}
+}
+
+func ldapResponse(messageID int64, op *ber.Packet) *ber.Packet {
+	var packet = ber.NewSequence("response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "message id"))
+	packet.AppendChild(op)
+	return packet
+}
+
+func ldapResult(tag ber.Tag, code int) *ber.Packet {
+	var op = ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matched dn"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "message"))
+	return op
+}
+
+func ldapSearchEntryPacket(dn string, entry stubLDAPEntry) *ber.Packet {
+	var groups = ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "values")
+	for _, group := range entry.groups {
+		groups.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, group, "value"))
+	}
+
+	var attribute = ber.NewSequence("attribute")
+	attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "memberOf", "type"))
+	attribute.AppendChild(groups)
+
+	var attributes = ber.NewSequence("attributes")
+	attributes.AppendChild(attribute)
+
+	var op = ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapSearchEntry, nil, "entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "dn"))
+	op.AppendChild(attributes)
+	return op
+}
+
+func TestLoginLDAP(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var server = newStubLDAPServer(t, map[string]stubLDAPEntry{
+		"uid=alice,ou=people,dc=example,dc=org": {
+			uid: "alice", password: "alice-password", groups: []string{"cn=Ops,ou=groups,dc=example,dc=org"},
+		},
+		"uid=bob,ou=people,dc=example,dc=org": {
+			uid: "bob", password: "bob-password", groups: []string{"cn=guests,ou=groups,dc=example,dc=org"},
+		},
+	})
+	defer server.listener.Close()
+
+	var cfg = &config.Config{Auth: config.AuthCfg{
+		LoginFailures: 10, IPLoginFailures: 10, LockoutDuration: 60,
+		LDAP: config.LDAPCfg{
+			URL:            server.url(),
+			Timeout:        5,
+			BindDN:         ldapServiceDN,
+			BindPassword:   ldapServicePassword,
+			BaseDN:         "dc=example,dc=org",
+			UserFilter:     "(uid=%s)",
+			GroupAttribute: "memberOf",
+			RoleGroups:     map[string]string{"cn=ops,ou=groups,dc=example,dc=org": "admin"},
+		},
+	}}
+
+	// local break-glass admin
+	var root = &models.User{Username: "root", Password: "root-password", Role: models.SuperAdmin}
+	if err := root.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	var now = time.Now()
+	alice, err := Login(sqlDB, cfg, "alice", "alice-password", "", now)
+	if err != nil {
+		t.Fatalf("Login() of directory user error: %v", err)
+	}
+
+	if alice.Role != models.Admin {
+		t.Errorf("directory user role = %v, want %v", alice.Role, models.Admin)
+	}
+
+	if dn, _ := alice.LDAPDN(sqlDB); dn != "uid=alice,ou=people,dc=example,dc=org" {
+		t.Errorf("directory user is linked to %q", dn)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  error
+	}{
+		{name: "directory user again", username: "alice", password: "alice-password"},
+		{name: "wrong password", username: "alice", password: "other", wantErr: ErrNotAuthorized},
+		{name: "empty password", username: "alice", password: "", wantErr: ErrNotAuthorized},
+		{name: "no mapped group", username: "bob", password: "bob-password", wantErr: ErrNotAuthorized},
+		{name: "unknown user", username: "nobody", password: "password", wantErr: sql.ErrNoRows},
+		{name: "local user", username: "root", password: "root-password"},
+	}
+	for _, tt := range tests {
+		if _, err = Login(sqlDB, cfg, tt.username, tt.password, "", now); err != tt.wantErr {
+			t.Errorf("%s: Login() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// directory is down
+	server.listener.Close()
+	cfg.Auth.LDAP.Timeout = 1
+
+	if _, err = Login(sqlDB, cfg, "root", "root-password", "", now); err != nil {
+		t.Errorf("Login() of local user while directory is down error: %v", err)
+	}
+
+	if _, err = Login(sqlDB, cfg, "alice", "alice-password", "", now); err == nil || err == ErrNotAuthorized {
+		t.Errorf("Login() of directory user while directory is down error = %v, want connection error", err)
+	}
+}
@@ -0,0 +1,209 @@
+package validate
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+// ErrNotAuthorized - password does not match
+var ErrNotAuthorized = fmt.Errorf("not authorized")
+
+// ThrottledError - login attempt is rejected because of previous failures of username or client address
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many failed logins, retry after %v", e.RetryAfter)
+}
+
+type throttleKey struct {
+	key   string
+	limit int // failures before lockout
+}
+
+// loginGuard - throttles of login attempt by username and client address
+type loginGuard struct {
+	db       *sql.DB
+	cfg      *config.AuthCfg
+	keys     []throttleKey
+	username string
+	ip       string
+	now      time.Time
+}
+
+// newLoginGuard - guard of login attempt, ThrottledError is returned if attempt is not allowed yet
+func newLoginGuard(db *sql.DB, cfg *config.Config, username, ip string, now time.Time) (*loginGuard, error) {
+	var guard = &loginGuard{db: db, cfg: &cfg.Auth, username: username, ip: ip, now: now}
+	guard.keys = []throttleKey{{key: models.UserThrottleKey(username), limit: cfg.Auth.LoginFailures}}
+	if ip != "" {
+		guard.keys = append(guard.keys, throttleKey{key: models.IPThrottleKey(ip), limit: cfg.Auth.IPLoginFailures})
+	}
+
+	var retry time.Time
+	for _, key := range guard.keys {
+		throttle, err := models.GetLoginThrottle(db, key.key)
+		if err != nil {
+			return nil, err
+		}
+
+		if at := retryAt(guard.cfg, throttle); at.After(retry) {
+			retry = at
+		}
+	}
+
+	if now.Before(retry) {
+		return nil, &ThrottledError{RetryAfter: retry.Sub(now)}
+	}
+
+	return guard, nil
+}
+
+// Login - user with matching password, failed attempts are throttled per username and client address
+// with exponential backoff and temporary lockout, sql.ErrNoRows is returned for unknown username.
+// If LDAP is configured, password of unknown and directory users is checked by directory,
+// local users keep local passwords so break-glass admins can log in while directory is down
+func Login(db *sql.DB, cfg *config.Config, username, password, ip string, now time.Time) (*models.User, error) {
+	guard, err := newLoginGuard(db, cfg, username, ip, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var user = &models.User{}
+	err = user.GetByName(db, username)
+	if err == sql.ErrNoRows {
+		user = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	directory, dirErr := isLDAPUser(db, &cfg.Auth.LDAP, user)
+	if dirErr != nil {
+		return nil, dirErr
+	}
+
+	var reason = "unknown username"
+	if directory {
+		user, err = loginLDAP(db, &cfg.Auth.LDAP, user, username, password, ip, now)
+		switch err {
+		case nil:
+			return user, guard.succeeded()
+		case errLDAPNoUser:
+			err = sql.ErrNoRows
+		case ErrNotAuthorized:
+			reason = "wrong ldap password"
+		case errLDAPNoRole:
+			reason = errLDAPNoRole.Error()
+			err = ErrNotAuthorized
+		default:
+			// unavailable directory is not failure of user
+			return nil, err
+		}
+	} else if err == nil {
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil {
+			return user, guard.succeeded()
+		}
+
+		reason = "wrong password"
+		err = ErrNotAuthorized
+	}
+
+	if failErr := guard.failed(reason); failErr != nil {
+		return nil, failErr
+	}
+
+	return nil, err
+}
+
+// UnlockUser - forget failed logins of user, unlock is made by admin adminID
+func UnlockUser(db *sql.DB, user *models.User, adminID int, now time.Time) error {
+	err := models.ResetLoginThrottle(db, models.UserThrottleKey(user.Username))
+	if err != nil {
+		return err
+	}
+
+	var entry = &models.AuditEntry{
+		Event:     models.AuditLoginUnlocked,
+		Username:  user.Username,
+		UserID:    adminID,
+		CreatedAt: now,
+	}
+
+	return entry.Save(db)
+}
+
+// succeeded - forget failures of username, failures of client address are kept
+func (g *loginGuard) succeeded() error {
+	return models.ResetLoginThrottle(g.db, g.keys[0].key)
+}
+
+// failed - count failure of username and client address, lock them if failures reach limit
+func (g *loginGuard) failed(reason string) error {
+	var entry = &models.AuditEntry{
+		Event:     models.AuditLoginFailed,
+		Username:  g.username,
+		IP:        g.ip,
+		Details:   reason,
+		CreatedAt: g.now,
+	}
+
+	err := entry.Save(g.db)
+	if err != nil {
+		return err
+	}
+
+	var lockedUntil = g.now.Add(time.Duration(g.cfg.LockoutDuration) * time.Second)
+	for _, key := range g.keys {
+		// counter is incremented by database, concurrent failures are all counted
+		throttle, locked, err := models.AddLoginFailure(g.db, key.key, key.limit, g.now, lockedUntil)
+		if err != nil {
+			return err
+		}
+
+		if !locked {
+			continue
+		}
+
+		var entry = &models.AuditEntry{
+			Event:     models.AuditLoginLocked,
+			Username:  g.username,
+			IP:        g.ip,
+			Details:   fmt.Sprintf("%s locked until %s", throttle.Key, lockedUntil.UTC().Format(time.RFC3339)),
+			CreatedAt: g.now,
+		}
+		if err = entry.Save(g.db); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retryAt - time of next allowed login attempt, delay doubles on every failure and is limited by lockout duration
+func retryAt(cfg *config.AuthCfg, throttle *models.LoginThrottle) time.Time {
+	if throttle.LockedUntil != nil {
+		return *throttle.LockedUntil
+	}
+
+	if throttle.Failures == 0 {
+		return time.Time{}
+	}
+
+	var lockout = time.Duration(cfg.LockoutDuration) * time.Second
+	var delay = time.Duration(cfg.LoginBackoff) * time.Second
+	for i := 1; i < throttle.Failures && delay < lockout; i++ {
+		delay *= 2
+	}
+
+	if delay > lockout {
+		delay = lockout
+	}
+
+	return throttle.LastFailureAt.Add(delay)
+}
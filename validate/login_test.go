@@ -0,0 +1,181 @@
+package validate
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+func TestLogin_Throttle(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{Auth: config.AuthCfg{
+		LoginFailures:   3,
+		IPLoginFailures: 10,
+		LoginBackoff:    1,
+		LockoutDuration: 60,
+	}}
+	var user = &models.User{Username: "admin", Password: "password", Role: models.Admin}
+	if err := user.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	var now = time.Now()
+	if _, err := Login(sqlDB, cfg, "admin", "wrong", "10.0.0.1", now); err != ErrNotAuthorized {
+		t.Fatalf("Login() with wrong password error = %v, want %v", err, ErrNotAuthorized)
+	}
+
+	// second attempt is allowed only after backoff
+	_, err := Login(sqlDB, cfg, "admin", "password", "10.0.0.1", now)
+	if throttled, ok := err.(*ThrottledError); !ok || throttled.RetryAfter != time.Second {
+		t.Fatalf("Login() during backoff error = %v, want retry after 1s", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err = Login(sqlDB, cfg, "admin", "wrong", "10.0.0.2", now); err != ErrNotAuthorized {
+		t.Fatalf("Login() error = %v, want %v", err, ErrNotAuthorized)
+	}
+
+	now = now.Add(2 * time.Second)
+	if _, err = Login(sqlDB, cfg, "admin", "wrong", "10.0.0.3", now); err != ErrNotAuthorized {
+		t.Fatalf("Login() error = %v, want %v", err, ErrNotAuthorized)
+	}
+
+	// third failure locks username
+	_, err = Login(sqlDB, cfg, "admin", "password", "10.0.0.4", now.Add(10*time.Second))
+	if throttled, ok := err.(*ThrottledError); !ok || throttled.RetryAfter != 50*time.Second {
+		t.Fatalf("Login() of locked user error = %v, want retry after 50s", err)
+	}
+
+	if err = UnlockUser(sqlDB, user, 1, now); err != nil {
+		t.Fatalf("UnlockUser() error: %v", err)
+	}
+
+	logged, err := Login(sqlDB, cfg, "admin", "password", "10.0.0.4", now)
+	if err != nil {
+		t.Fatalf("Login() after unlock error: %v", err)
+	}
+
+	if logged.ID != user.ID {
+		t.Errorf("Login() user = %d, want %d", logged.ID, user.ID)
+	}
+
+	if _, err = Login(sqlDB, cfg, "unknown", "password", "10.0.0.4", now); err != sql.ErrNoRows {
+		t.Errorf("Login() of unknown user error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	entries, err := models.GetAuditEntries(sqlDB, 0, 100)
+	if err != nil {
+		t.Fatalf("GetAuditEntries() error: %v", err)
+	}
+
+	var events = make(map[models.AuditEvent]int)
+	for _, entry := range entries {
+		events[entry.Event]++
+	}
+
+	if events[models.AuditLoginFailed] != 4 || events[models.AuditLoginLocked] != 1 || events[models.AuditLoginUnlocked] != 1 {
+		t.Errorf("audit events = %v", events)
+	}
+}
+
+func TestLoginGuard_ConcurrentFailures(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{Auth: config.AuthCfg{
+		LoginFailures:   5,
+		IPLoginFailures: 100,
+		LockoutDuration: 60,
+	}}
+
+	const attempts = 20
+	var now = time.Now()
+
+	// all attempts pass the guard before any failure is counted
+	var guards []*loginGuard
+	for i := 0; i < attempts; i++ {
+		guard, err := newLoginGuard(sqlDB, cfg, "admin", "10.0.0.1", now)
+		if err != nil {
+			t.Fatalf("newLoginGuard() error: %v", err)
+		}
+		guards = append(guards, guard)
+	}
+
+	var wg sync.WaitGroup
+	var errs = make(chan error, attempts)
+	for _, guard := range guards {
+		wg.Add(1)
+		go func(guard *loginGuard) {
+			defer wg.Done()
+			errs <- guard.failed("wrong password")
+		}(guard)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("failed() error: %v", err)
+		}
+	}
+
+	for _, key := range []string{models.UserThrottleKey("admin"), models.IPThrottleKey("10.0.0.1")} {
+		throttle, err := models.GetLoginThrottle(sqlDB, key)
+		if err != nil {
+			t.Fatalf("GetLoginThrottle() error: %v", err)
+		}
+
+		if throttle.Failures != attempts {
+			t.Errorf("%s failures = %d, want %d", key, throttle.Failures, attempts)
+		}
+	}
+
+	if _, err := newLoginGuard(sqlDB, cfg, "admin", "10.0.0.2", now); err == nil {
+		t.Errorf("newLoginGuard() of locked user error = nil, want ThrottledError")
+	}
+
+	entries, err := models.GetAuditEntries(sqlDB, 0, 100)
+	if err != nil {
+		t.Fatalf("GetAuditEntries() error: %v", err)
+	}
+
+	var locked int
+	for _, entry := range entries {
+		if entry.Event == models.AuditLoginLocked {
+			locked++
+		}
+	}
+
+	if locked != 1 {
+		t.Errorf("lockout audit entries = %d, want 1", locked)
+	}
+}
+
+func TestRetryAt(t *testing.T) {
+	var cfg = &config.AuthCfg{LoginBackoff: 1, LockoutDuration: 10}
+	var last = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Time
+	}{
+		{name: "no failures", failures: 0, want: time.Time{}},
+		{name: "first failure", failures: 1, want: last.Add(time.Second)},
+		{name: "third failure", failures: 3, want: last.Add(4 * time.Second)},
+		{name: "limited by lockout", failures: 10, want: last.Add(10 * time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := retryAt(cfg, &models.LoginThrottle{Failures: tt.failures, LastFailureAt: last})
+			if !got.Equal(tt.want) {
+				t.Errorf("retryAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
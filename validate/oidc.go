@@ -0,0 +1,147 @@
+package validate
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+var (
+	// ErrSSONoRole - none of provider groups of user is mapped to role and default role is not set
+	ErrSSONoRole = fmt.Errorf("single sign-on user has no role")
+	// ErrSSOUsernameTaken - username of provider user belongs to local user or user of other subject
+	ErrSSOUsernameTaken = fmt.Errorf("username is taken by other user")
+)
+
+// roleRank - privilege of role, the highest one of user groups is used
+var roleRank = map[models.Role]int{
+	models.Viewer:     1,
+	models.Admin:      2,
+	models.SuperAdmin: 3,
+}
+
+// OIDCRole - role of provider groups by config mapping, 0 if no role is mapped
+func OIDCRole(cfg *config.OIDCCfg, groups []string) models.Role {
+	return groupsRole(cfg.RoleGroups, cfg.DefaultRole, groups)
+}
+
+// groupsRole - the highest role mapped to any of groups, group names are case-insensitive,
+// default role is used if no group is mapped
+func groupsRole(roleGroups map[string]string, defaultRole string, groups []string) models.Role {
+	var role models.Role
+	for group, name := range roleGroups {
+		mapped := models.ParseRole(name)
+		if roleRank[mapped] <= roleRank[role] {
+			continue
+		}
+
+		for _, userGroup := range groups {
+			if strings.EqualFold(userGroup, group) {
+				role = mapped
+				break
+			}
+		}
+	}
+
+	if role == 0 {
+		role = models.ParseRole(defaultRole)
+	}
+
+	return role
+}
+
+// LoginOIDC - user of provider subject, user is created on first login and its role follows
+// provider groups on every login, two-factor authentication of such users is provided by provider
+func LoginOIDC(db *sql.DB, cfg *config.Config, subject, username string, groups []string, ip string, now time.Time) (*models.User, error) {
+	var role = OIDCRole(&cfg.Auth.OIDC, groups)
+	if role == 0 {
+		return nil, ssoDenied(db, username, ip, now, ErrSSONoRole)
+	}
+
+	user, err := models.GetUserByOIDCSubject(db, subject)
+	if err == sql.ErrNoRows {
+		err = (&models.User{}).GetByName(db, username)
+		if err == nil {
+			return nil, ssoDenied(db, username, ip, now, ErrSSOUsernameTaken)
+		}
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		user, err = provisionUser(db, username, role, ip, now, "single sign-on subject "+subject)
+		if err != nil {
+			return nil, err
+		}
+
+		return user, user.SetOIDCSubject(db, subject)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, syncRole(db, user, role)
+}
+
+// syncRole - update role of external user to role of its current groups
+func syncRole(db *sql.DB, user *models.User, role models.Role) error {
+	if user.Role == role {
+		return nil
+	}
+
+	err := (&models.User{Role: role}).Update(db, user.ID)
+	if err != nil {
+		return err
+	}
+	user.Role = role
+
+	return nil
+}
+
+// provisionUser - new user of external identity, its password is random so it can not log in locally
+func provisionUser(db *sql.DB, username string, role models.Role, ip string, now time.Time, source string) (*models.User, error) {
+	var buf = make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	var user = &models.User{Username: username, Password: hex.EncodeToString(buf), Role: role}
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := user.Save(db); err != nil {
+		return nil, err
+	}
+
+	var entry = &models.AuditEntry{
+		Event:     models.AuditUserProvisioned,
+		Username:  username,
+		IP:        ip,
+		Details:   fmt.Sprintf("%s, role %s", source, role),
+		CreatedAt: now,
+	}
+
+	return user, entry.Save(db)
+}
+
+// ssoDenied - audit denied single sign-on login and return its reason
+func ssoDenied(db *sql.DB, username, ip string, now time.Time, reason error) error {
+	var entry = &models.AuditEntry{
+		Event:     models.AuditLoginFailed,
+		Username:  username,
+		IP:        ip,
+		Details:   "single sign-on: " + reason.Error(),
+		CreatedAt: now,
+	}
+	if err := entry.Save(db); err != nil {
+		return err
+	}
+
+	return reason
+}
@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"testing"
+	"time"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+func TestOIDCRole(t *testing.T) {
+	var cfg = &config.OIDCCfg{RoleGroups: map[string]string{
+		"ops":    "admin",
+		"root":   "superadmin",
+		"staff":  "viewer",
+		"broken": "unknown",
+	}}
+
+	tests := []struct {
+		groups      []string
+		defaultRole string
+		want        models.Role
+	}{
+		{groups: []string{"staff", "ops"}, want: models.Admin},
+		{groups: []string{"root", "staff"}, want: models.SuperAdmin},
+		{groups: []string{"broken"}, want: 0},
+		{groups: nil, defaultRole: "viewer", want: models.Viewer},
+		{groups: []string{"other"}, want: 0},
+	}
+	for _, tt := range tests {
+		cfg.DefaultRole = tt.defaultRole
+		if got := OIDCRole(cfg, tt.groups); got != tt.want {
+			t.Errorf("OIDCRole(%v) = %v, want %v", tt.groups, got, tt.want)
+		}
+	}
+}
+
+func TestLoginOIDC(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{Auth: config.AuthCfg{OIDC: config.OIDCCfg{
+		RoleGroups: map[string]string{"ops": "admin", "staff": "viewer"},
+	}}}
+	var local = &models.User{Username: "root", Password: "password", Role: models.SuperAdmin}
+	if err := local.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	var now = time.Now()
+	user, err := LoginOIDC(sqlDB, cfg, "sub1", "alice", []string{"ops"}, "", now)
+	if err != nil {
+		t.Fatalf("LoginOIDC() error: %v", err)
+	}
+
+	if user.Role != models.Admin {
+		t.Errorf("provisioned user role = %v, want %v", user.Role, models.Admin)
+	}
+
+	if _, err = Login(sqlDB, &config.Config{}, "alice", "", "", now); err == nil {
+		t.Errorf("provisioned user logs in with empty local password")
+	}
+
+	// role follows provider groups
+	again, err := LoginOIDC(sqlDB, cfg, "sub1", "alice", []string{"staff"}, "", now)
+	if err != nil {
+		t.Fatalf("second LoginOIDC() error: %v", err)
+	}
+
+	if again.ID != user.ID || again.Role != models.Viewer {
+		t.Errorf("second LoginOIDC() user = %d role %v, want %d role %v", again.ID, again.Role, user.ID, models.Viewer)
+	}
+
+	if _, err = LoginOIDC(sqlDB, cfg, "sub2", "root", []string{"ops"}, "", now); err != ErrSSOUsernameTaken {
+		t.Errorf("LoginOIDC() as local user error = %v, want %v", err, ErrSSOUsernameTaken)
+	}
+
+	if _, err = LoginOIDC(sqlDB, cfg, "sub3", "bob", []string{"other"}, "", now); err != ErrSSONoRole {
+		t.Errorf("LoginOIDC() without role error = %v, want %v", err, ErrSSONoRole)
+	}
+}
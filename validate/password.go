@@ -0,0 +1,260 @@
+package validate
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+// maxPasswordBytes - bcrypt ignores bytes after this length
+const maxPasswordBytes = 72
+
+// ErrPasswordExternal - password of user is checked by single sign-on provider or directory
+var ErrPasswordExternal = fmt.Errorf("password is managed by identity provider")
+
+// PasswordPolicyError - new password violates password policy
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not match policy: " + strings.Join(e.Violations, ", ")
+}
+
+// CheckPasswordPolicy - PasswordPolicyError with all violations of password, nil if password is accepted
+func CheckPasswordPolicy(cfg *config.PasswordCfg, username, password string) error {
+	var violations []string
+	if len([]rune(password)) < cfg.MinLength {
+		violations = append(violations, fmt.Sprintf("at least %d characters", cfg.MinLength))
+	}
+
+	if len(password) > maxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("at most %d bytes", maxPasswordBytes))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	for _, rule := range []struct {
+		required, ok bool
+		violation    string
+	}{
+		{cfg.RequireUpper, upper, "upper case letter"},
+		{cfg.RequireLower, lower, "lower case letter"},
+		{cfg.RequireDigit, digit, "digit"},
+		{cfg.RequireSymbol, symbol, "symbol"},
+	} {
+		if rule.required && !rule.ok {
+			violations = append(violations, rule.violation)
+		}
+	}
+
+	if username != "" && strings.EqualFold(username, password) {
+		violations = append(violations, "differs from username")
+	}
+
+	if cfg.BreachedFile != "" {
+		found, err := breached.contains(cfg.BreachedFile, password)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			violations = append(violations, "not in breached password list")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// ChangePassword - replace password of user by new one after current password is checked,
+// other sessions of user are revoked and failed checks are throttled like logins
+func ChangePassword(db *sql.DB, cfg *config.Config, userID int, current, password, ip string, now time.Time) (*models.User, error) {
+	var user = &models.User{}
+	err := user.GetByID(db, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	external, err := user.IsExternal(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if external {
+		return nil, ErrPasswordExternal
+	}
+
+	guard, err := newLoginGuard(db, cfg, user.Username, ip, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(current)) != nil {
+		if err = guard.failed("wrong current password"); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotAuthorized
+	}
+
+	if err = guard.succeeded(); err != nil {
+		return nil, err
+	}
+
+	if current == password {
+		return nil, &PasswordPolicyError{Violations: []string{"differs from current password"}}
+	}
+
+	err = setPassword(db, cfg, user, password, false, user.ID, models.AuditPasswordChanged, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ResetPassword - set password of user by admin adminID, user must change it on next login
+func ResetPassword(db *sql.DB, cfg *config.Config, user *models.User, password string, adminID int, now time.Time) error {
+	external, err := user.IsExternal(db)
+	if err != nil {
+		return err
+	}
+
+	if external {
+		return ErrPasswordExternal
+	}
+
+	return setPassword(db, cfg, user, password, true, adminID, models.AuditPasswordReset, now)
+}
+
+// setPassword - save password accepted by policy, sessions of user are revoked
+func setPassword(
+	db *sql.DB,
+	cfg *config.Config,
+	user *models.User,
+	password string,
+	changeRequired bool,
+	actorID int,
+	event models.AuditEvent,
+	now time.Time,
+) error {
+	err := CheckPasswordPolicy(&cfg.Auth.Password, user.Username, password)
+	if err != nil {
+		return err
+	}
+
+	err = user.SetPassword(db, password, changeRequired)
+	if err != nil {
+		return err
+	}
+
+	err = models.RevokeUserSessions(db, user.ID, now)
+	if err != nil {
+		return err
+	}
+
+	var entry = &models.AuditEntry{
+		Event:     event,
+		Username:  user.Username,
+		UserID:    actorID,
+		CreatedAt: now,
+	}
+
+	return entry.Save(db)
+}
+
+// breachedList - SHA-1 hashes of breached passwords file, file is reloaded when it is modified
+type breachedList struct {
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	hashes  map[string]struct{}
+}
+
+var breached = &breachedList{}
+
+func (b *breachedList) contains(path, password string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	if b.hashes == nil || b.path != path || !b.modTime.Equal(info.ModTime()) {
+		hashes, err := loadBreachedFile(path)
+		if err != nil {
+			return false, err
+		}
+
+		b.path, b.modTime, b.hashes = path, info.ModTime(), hashes
+	}
+
+	_, ok := b.hashes[sha1Hex(password)]
+	return ok, nil
+}
+
+// loadBreachedFile - hashes of file lines, line of 40 hex characters is SHA-1 hash, other lines are passwords
+func loadBreachedFile(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var hashes = make(map[string]struct{})
+	var scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line = strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		var hash = line
+		if i := strings.IndexByte(hash, ':'); i >= 0 {
+			hash = hash[:i]
+		}
+
+		if _, err := hex.DecodeString(hash); err == nil && len(hash) == 2*sha1.Size {
+			hashes[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+
+		hashes[sha1Hex(line)] = struct{}{}
+	}
+
+	return hashes, scanner.Err()
+}
+
+func sha1Hex(s string) string {
+	var sum = sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,141 @@
+package validate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "projectionist-password")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// "Password1!" as plain text and "Qwerty123!" as SHA-1 hash with count
+	var breachedFile = filepath.Join(dir, "breached.txt")
+	err = ioutil.WriteFile(breachedFile, []byte("Password1!\nd4f55dec8c7bc9675182779e564fae1327d30f9b:12\n"), 0600)
+	if err != nil {
+		t.Fatalf("ioutil.WriteFile() error: %v", err)
+	}
+
+	var cfg = &config.PasswordCfg{
+		MinLength:     10,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		BreachedFile:  breachedFile,
+	}
+
+	tests := []struct {
+		username   string
+		password   string
+		violations int
+	}{
+		{password: "Correct-horse-7", violations: 0},
+		{password: "short1!A", violations: 1},
+		{password: "alllowercase", violations: 3},
+		{password: "Password1!", violations: 1},
+		{password: "Qwerty123!", violations: 1},
+		{username: "alice-12345", password: "Alice-12345", violations: 1},
+	}
+	for _, tt := range tests {
+		err := CheckPasswordPolicy(cfg, tt.username, tt.password)
+		var got int
+		if policyErr, ok := err.(*PasswordPolicyError); ok {
+			got = len(policyErr.Violations)
+		} else if err != nil {
+			t.Fatalf("CheckPasswordPolicy(%q) error: %v", tt.password, err)
+		}
+
+		if got != tt.violations {
+			t.Errorf("CheckPasswordPolicy(%q) violations = %d (%v), want %d", tt.password, got, err, tt.violations)
+		}
+	}
+
+	// list is reloaded when file is modified
+	err = ioutil.WriteFile(breachedFile, []byte("Correct-horse-7\n"), 0600)
+	if err != nil {
+		t.Fatalf("ioutil.WriteFile() error: %v", err)
+	}
+	os.Chtimes(breachedFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+
+	if err = CheckPasswordPolicy(cfg, "bob", "Correct-horse-7"); err == nil {
+		t.Errorf("CheckPasswordPolicy() accepts password of modified breached list")
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{TokenSecretKey: "secret", Auth: config.AuthCfg{
+		AccessTokenTTL: 60, RefreshTokenTTL: 3600,
+		LoginFailures: 5, IPLoginFailures: 20, LoginBackoff: 0, LockoutDuration: 60,
+		Password: config.PasswordCfg{MinLength: 8},
+	}}
+	var user = &models.User{Username: "alice", Password: "old-password", Role: models.Admin}
+	if err := user.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	var now = time.Now()
+	if err := ResetPassword(sqlDB, cfg, user, "short", 1, now); err == nil {
+		t.Errorf("ResetPassword() accepts password violating policy")
+	}
+
+	if err := ResetPassword(sqlDB, cfg, user, "temporary", 1, now); err != nil {
+		t.Fatalf("ResetPassword() error: %v", err)
+	}
+
+	logged, err := Login(sqlDB, cfg, "alice", "temporary", "", now)
+	if err != nil {
+		t.Fatalf("Login() with temporary password error: %v", err)
+	}
+
+	tokens, err := NewSession(sqlDB, cfg, logged, now)
+	if err != nil {
+		t.Fatalf("NewSession() error: %v", err)
+	}
+
+	token, err := Authenticate(sqlDB, "Bearer "+tokens.AccessToken, cfg.TokenSecretKey)
+	if err != nil {
+		t.Fatalf("Authenticate() error: %v", err)
+	}
+
+	if !token.PasswordChange {
+		t.Errorf("token of user with reset password has no password change claim")
+	}
+
+	if _, err = ChangePassword(sqlDB, cfg, user.ID, "wrong", "new-password", "", now); err != ErrNotAuthorized {
+		t.Errorf("ChangePassword() with wrong current password error = %v, want %v", err, ErrNotAuthorized)
+	}
+
+	if _, err = ChangePassword(sqlDB, cfg, user.ID, "temporary", "temporary", "", now); err == nil {
+		t.Errorf("ChangePassword() accepts the same password")
+	}
+
+	changed, err := ChangePassword(sqlDB, cfg, user.ID, "temporary", "new-password", "", now)
+	if err != nil {
+		t.Fatalf("ChangePassword() error: %v", err)
+	}
+
+	if changed.PasswordChangeRequired {
+		t.Errorf("password change is required after change")
+	}
+
+	if _, err = Authenticate(sqlDB, "Bearer "+tokens.AccessToken, cfg.TokenSecretKey); err != ErrSessionInvalid {
+		t.Errorf("Authenticate() by session before change error = %v, want %v", err, ErrSessionInvalid)
+	}
+
+	if _, err = Login(sqlDB, cfg, "alice", "new-password", "", now); err != nil {
+		t.Errorf("Login() with new password error: %v", err)
+	}
+}
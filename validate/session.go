@@ -0,0 +1,183 @@
+package validate
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+// ErrSessionInvalid - refresh token or session of access token is unknown, revoked or expired
+var ErrSessionInvalid = fmt.Errorf("session is revoked or expired")
+
+// Tokens - tokens of user session
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time // expiry of access token
+}
+
+// NewSession - start session of authenticated user
+func NewSession(db *sql.DB, cfg *config.Config, user *models.User, now time.Time) (*Tokens, error) {
+	refreshToken, err := models.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var session = &models.Session{
+		UserID:    user.ID,
+		TokenHash: models.HashToken(refreshToken),
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(cfg.Auth.RefreshTokenTTL) * time.Second),
+	}
+
+	err = session.Save(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return issueTokens(cfg, session, user, refreshToken, now)
+}
+
+// RefreshSession - rotate refresh token of session and issue access token with current role of user
+func RefreshSession(db *sql.DB, cfg *config.Config, refreshToken string, now time.Time) (*Tokens, *models.User, error) {
+	session, err := models.GetSessionByToken(db, refreshToken)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, ErrSessionInvalid
+		}
+		return nil, nil, err
+	}
+
+	if !session.IsActive(now) {
+		return nil, nil, ErrSessionInvalid
+	}
+
+	var user = &models.User{}
+	err = user.GetByID(db, int64(session.UserID))
+	if err == sql.ErrNoRows || (err == nil && user.IsDeleted()) {
+		if err = session.Revoke(db, now); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrSessionInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newToken, err := models.NewRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = session.Rotate(db, models.HashToken(newToken), now.Add(time.Duration(cfg.Auth.RefreshTokenTTL)*time.Second))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, ErrSessionInvalid
+		}
+		return nil, nil, err
+	}
+
+	tokens, err := issueTokens(cfg, session, user, newToken, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, user, nil
+}
+
+// Logout - revoke session of access token
+func Logout(db *sql.DB, token *models.Token, now time.Time) error {
+	session, err := tokenSession(db, token)
+	if err != nil {
+		return err
+	}
+
+	return session.Revoke(db, now)
+}
+
+// CheckSession - session of access token is active
+func CheckSession(db *sql.DB, token *models.Token, now time.Time) error {
+	session, err := tokenSession(db, token)
+	if err != nil {
+		return err
+	}
+
+	if !session.IsActive(now) {
+		return ErrSessionInvalid
+	}
+
+	return nil
+}
+
+// Authenticate - claims of "Bearer <token>" authorization token of active session or "Bearer <api key>"
+func Authenticate(db *sql.DB, token string, tokenSecretKey string) (*models.Token, error) {
+	var splitted = strings.Split(token, " ")
+	if len(splitted) == 2 && models.IsAPIKey(splitted[1]) {
+		return AuthenticateAPIKey(db, splitted[1], time.Now())
+	}
+
+	tokenM, err := ParseToken(token, tokenSecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = CheckSession(db, tokenM, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenM, nil
+}
+
+func tokenSession(db *sql.DB, token *models.Token) (*models.Session, error) {
+	if token.TwoFactor {
+		return nil, ErrSessionInvalid
+	}
+
+	id, err := strconv.Atoi(token.Id)
+	if err != nil {
+		return nil, ErrSessionInvalid
+	}
+
+	session, err := models.GetSession(db, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionInvalid
+		}
+		return nil, err
+	}
+
+	if session.UserID != int(token.UserId) {
+		return nil, ErrSessionInvalid
+	}
+
+	return session, nil
+}
+
+func issueTokens(cfg *config.Config, session *models.Session, user *models.User, refreshToken string, now time.Time) (*Tokens, error) {
+	var expiresAt = now.Add(time.Duration(cfg.Auth.AccessTokenTTL) * time.Second)
+	var tokenM = &models.Token{
+		UserId:         uint64(user.ID),
+		Role:           user.Role,
+		PasswordChange: user.PasswordChangeRequired,
+		StandardClaims: jwt.StandardClaims{
+			Id:        strconv.Itoa(session.ID),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+
+	accessToken, err := tokenKeys(cfg.TokenSecretKey).Sign(tokenM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresAt: expiresAt}, nil
+}
@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"projectionist/config"
+	"projectionist/db"
+	"projectionist/models"
+)
+
+func newSessionDB(t *testing.T) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "session")
+	if err != nil {
+		t.Fatalf("temp dir error: %v", err)
+	}
+
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(dir, "test.sqlite"))
+	if err != nil {
+		t.Fatalf("open db error: %v", err)
+	}
+
+	err = db.InitTables(sqlDB)
+	if err != nil {
+		t.Fatalf("init tables error: %v", err)
+	}
+
+	return sqlDB, func() {
+		sqlDB.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSession(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{TokenSecretKey: "secret", Auth: config.AuthCfg{AccessTokenTTL: 60, RefreshTokenTTL: 3600}}
+	var user = &models.User{Username: "admin", Password: "password", Role: models.Admin}
+	if err := user.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	var now = time.Now()
+	tokens, err := NewSession(sqlDB, cfg, user, now)
+	if err != nil {
+		t.Fatalf("NewSession() error: %v", err)
+	}
+
+	if tokens.ExpiresAt.Unix() != now.Add(time.Minute).Unix() {
+		t.Errorf("access token expires at %v, want %v", tokens.ExpiresAt, now.Add(time.Minute))
+	}
+
+	tokenM, err := Authenticate(sqlDB, "Bearer "+tokens.AccessToken, cfg.TokenSecretKey)
+	if err != nil {
+		t.Fatalf("Authenticate() error: %v", err)
+	}
+
+	if tokenM.UserId != uint64(user.ID) || tokenM.Role != models.Admin || tokenM.ExpiresAt == 0 {
+		t.Errorf("Authenticate() claims = %+v", tokenM)
+	}
+
+	refreshed, refreshedUser, err := RefreshSession(sqlDB, cfg, tokens.RefreshToken, now)
+	if err != nil {
+		t.Fatalf("RefreshSession() error: %v", err)
+	}
+
+	if refreshedUser.ID != user.ID || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Errorf("RefreshSession() user = %d, refresh token rotated = %v", refreshedUser.ID, refreshed.RefreshToken != tokens.RefreshToken)
+	}
+
+	if _, _, err = RefreshSession(sqlDB, cfg, tokens.RefreshToken, now); err != ErrSessionInvalid {
+		t.Errorf("RefreshSession() with rotated token error = %v, want %v", err, ErrSessionInvalid)
+	}
+
+	if _, _, err = RefreshSession(sqlDB, cfg, refreshed.RefreshToken, now.Add(2*time.Hour)); err != ErrSessionInvalid {
+		t.Errorf("RefreshSession() of expired session error = %v, want %v", err, ErrSessionInvalid)
+	}
+
+	err = Logout(sqlDB, tokenM, now)
+	if err != nil {
+		t.Fatalf("Logout() error: %v", err)
+	}
+
+	if _, err = Authenticate(sqlDB, "Bearer "+refreshed.AccessToken, cfg.TokenSecretKey); err != ErrSessionInvalid {
+		t.Errorf("Authenticate() after logout error = %v, want %v", err, ErrSessionInvalid)
+	}
+
+	if _, _, err = RefreshSession(sqlDB, cfg, refreshed.RefreshToken, now); err != ErrSessionInvalid {
+		t.Errorf("RefreshSession() after logout error = %v, want %v", err, ErrSessionInvalid)
+	}
+}
+
+func TestCheckSession_WithoutSession(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	// token issued before sessions has no id
+	if err := CheckSession(sqlDB, &models.Token{UserId: 1, Role: models.SuperAdmin}, time.Now()); err != ErrSessionInvalid {
+		t.Errorf("CheckSession() error = %v, want %v", err, ErrSessionInvalid)
+	}
+}
+
+func TestSession_RoleChange(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{TokenSecretKey: "secret", Auth: config.AuthCfg{AccessTokenTTL: 60, RefreshTokenTTL: 3600}}
+	var user = &models.User{Username: "admin", Password: "password", Role: models.Admin}
+	if err := user.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	tokens, err := NewSession(sqlDB, cfg, user, time.Now())
+	if err != nil {
+		t.Fatalf("NewSession() error: %v", err)
+	}
+
+	// the same role keeps session
+	if err = (&models.User{Username: "root", Role: models.Admin}).Update(sqlDB, user.ID); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if _, err = Authenticate(sqlDB, "Bearer "+tokens.AccessToken, cfg.TokenSecretKey); err != nil {
+		t.Errorf("Authenticate() after update without role change error: %v", err)
+	}
+
+	// demoted user must log in again to get token of new role
+	if err = (&models.User{Role: models.Viewer}).Update(sqlDB, user.ID); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if _, err = Authenticate(sqlDB, "Bearer "+tokens.AccessToken, cfg.TokenSecretKey); err != ErrSessionInvalid {
+		t.Errorf("Authenticate() after role change error = %v, want %v", err, ErrSessionInvalid)
+	}
+	if _, _, err = RefreshSession(sqlDB, cfg, tokens.RefreshToken, time.Now()); err != ErrSessionInvalid {
+		t.Errorf("RefreshSession() after role change error = %v, want %v", err, ErrSessionInvalid)
+	}
+}
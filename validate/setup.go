@@ -0,0 +1,90 @@
+package validate
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"projectionist/models"
+)
+
+var (
+	// ErrSetupDone - users already exist, setup token is not accepted anymore
+	ErrSetupDone = fmt.Errorf("setup is already done")
+	// ErrSetupToken - setup token does not match
+	ErrSetupToken = fmt.Errorf("invalid setup token")
+)
+
+// SetupToken - one-time token allowing to create the first SuperAdmin via api while database has no users
+type SetupToken struct {
+	mu    sync.Mutex
+	token string // empty when setup is done
+}
+
+// NewSetupToken - random setup token if database has no users, otherwise setup token is disabled
+func NewSetupToken(db *sql.DB) (*SetupToken, error) {
+	count, err := (&models.User{}).Count(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return &SetupToken{}, nil
+	}
+
+	token, err := models.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetupToken{token: token}, nil
+}
+
+// Token - setup token, empty if setup is done
+func (s *SetupToken) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token
+}
+
+// CreateSuperAdmin - create the first user as SuperAdmin by setup token, token is disabled after it
+func (s *SetupToken) CreateSuperAdmin(db *sql.DB, token string, user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == "" {
+		return ErrSetupDone
+	}
+
+	// user is created by command line after start
+	count, err := user.Count(db)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		s.token = ""
+		return ErrSetupDone
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return ErrSetupToken
+	}
+
+	user.Role = models.SuperAdmin
+	err = user.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = user.Save(db)
+	if err != nil {
+		return err
+	}
+
+	s.token = ""
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package validate
+
+import (
+	"testing"
+
+	"projectionist/models"
+)
+
+func TestSetupToken_CreateSuperAdmin(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	setup, err := NewSetupToken(sqlDB)
+	if err != nil {
+		t.Fatalf("NewSetupToken() error: %v", err)
+	}
+
+	var token = setup.Token()
+	if token == "" {
+		t.Fatalf("setup token of database without users is empty")
+	}
+
+	var user = &models.User{Username: "admin", Password: "password", Role: models.Viewer}
+	if err = setup.CreateSuperAdmin(sqlDB, "wrong", user); err != ErrSetupToken {
+		t.Errorf("CreateSuperAdmin() with wrong token error = %v, want %v", err, ErrSetupToken)
+	}
+
+	if err = setup.CreateSuperAdmin(sqlDB, token, user); err != nil {
+		t.Fatalf("CreateSuperAdmin() error: %v", err)
+	}
+
+	var saved = &models.User{}
+	if err = saved.GetByID(sqlDB, int64(user.ID)); err != nil {
+		t.Fatalf("GetByID() error: %v", err)
+	}
+
+	if saved.Role != models.SuperAdmin {
+		t.Errorf("role of first user = %v, want %v", saved.Role, models.SuperAdmin)
+	}
+
+	var second = &models.User{Username: "second", Password: "password"}
+	if err = setup.CreateSuperAdmin(sqlDB, token, second); err != ErrSetupDone {
+		t.Errorf("CreateSuperAdmin() with used token error = %v, want %v", err, ErrSetupDone)
+	}
+
+	setup, err = NewSetupToken(sqlDB)
+	if err != nil {
+		t.Fatalf("NewSetupToken() error: %v", err)
+	}
+
+	if setup.Token() != "" {
+		t.Errorf("setup token of database with users is not empty")
+	}
+}
@@ -0,0 +1,225 @@
+package validate
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+const (
+	// TwoFactorIssuer - issuer shown by authenticator apps
+	TwoFactorIssuer = "Projectionist"
+	// twoFactorTokenTTL - time to enter code after password is accepted
+	twoFactorTokenTTL  = 5 * time.Minute
+	recoveryCodesCount = 10
+)
+
+var (
+	// ErrTwoFactorEnabled - user already has confirmed authenticator, it must be reset before new enrollment
+	ErrTwoFactorEnabled = fmt.Errorf("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnrolled - user has no authenticator waiting for confirmation
+	ErrTwoFactorNotEnrolled = fmt.Errorf("two-factor authentication is not enrolled")
+	// ErrTwoFactorCode - code is neither valid TOTP code nor unused recovery code
+	ErrTwoFactorCode = fmt.Errorf("invalid two-factor code")
+)
+
+// TwoFactorEnabled - true if login of user needs second factor
+func TwoFactorEnabled(db *sql.DB, userID int) (bool, error) {
+	tf, err := models.GetTwoFactor(db, userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return tf.IsEnabled(), nil
+}
+
+// EnrollTwoFactor - new authenticator secret of user and its provisioning uri,
+// it is enabled after confirmation by code
+func EnrollTwoFactor(db *sql.DB, user *models.User, now time.Time) (string, error) {
+	enabled, err := TwoFactorEnabled(db, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if enabled {
+		return "", ErrTwoFactorEnabled
+	}
+
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+
+	var tf = &models.TwoFactor{UserID: user.ID, Secret: secret, CreatedAt: now}
+	err = tf.Save(db)
+	if err != nil {
+		return "", err
+	}
+
+	return TOTPURI(TwoFactorIssuer, user.Username, secret), nil
+}
+
+// ConfirmTwoFactor - enable enrolled authenticator by its code, recovery codes are returned only here
+func ConfirmTwoFactor(db *sql.DB, userID int, code string, now time.Time) ([]string, error) {
+	tf, err := models.GetTwoFactor(db, userID)
+	if err == sql.ErrNoRows {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tf.IsEnabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	counter, ok := verifyTOTP(tf.Secret, code, tf.LastCounter, now)
+	if !ok {
+		return nil, ErrTwoFactorCode
+	}
+
+	codes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = models.SaveRecoveryCodes(db, userID, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tf.Confirm(db, counter, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// ResetTwoFactor - remove authenticator of user, e.g. when device is lost, reset is made by admin adminID
+func ResetTwoFactor(db *sql.DB, user *models.User, adminID int, now time.Time) error {
+	err := models.DeleteTwoFactor(db, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var entry = &models.AuditEntry{
+		Event:     models.AuditTwoFactorReset,
+		Username:  user.Username,
+		UserID:    adminID,
+		CreatedAt: now,
+	}
+
+	return entry.Save(db)
+}
+
+// NewTwoFactorToken - short-lived token of user with accepted password, it is exchanged for session by code
+func NewTwoFactorToken(cfg *config.Config, user *models.User, now time.Time) (string, error) {
+	var tokenM = &models.Token{
+		UserId:    uint64(user.ID),
+		Role:      user.Role,
+		TwoFactor: true,
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(twoFactorTokenTTL).Unix(),
+		},
+	}
+
+	return tokenKeys(cfg.TokenSecretKey).Sign(tokenM)
+}
+
+// LoginTwoFactor - user of two-factor token with valid TOTP code or unused recovery code,
+// failures are throttled together with password failures
+func LoginTwoFactor(db *sql.DB, cfg *config.Config, twoFactorToken, code, ip string, now time.Time) (*models.User, error) {
+	var tokenM = &models.Token{}
+	tokenJWT, err := jwt.ParseWithClaims(twoFactorToken, tokenM, TokenKeyfunc(cfg.TokenSecretKey))
+	if err != nil || !tokenJWT.Valid || !tokenM.TwoFactor {
+		return nil, ErrNotAuthorized
+	}
+
+	var user = &models.User{}
+	err = user.GetByID(db, int64(tokenM.UserId))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotAuthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	guard, err := newLoginGuard(db, cfg, user.Username, ip, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tf, err := models.GetTwoFactor(db, user.ID)
+	if err == sql.ErrNoRows || (err == nil && !tf.IsEnabled()) {
+		// reset by admin after password is accepted
+		return nil, ErrNotAuthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var reason = "wrong two-factor code"
+	if counter, ok := verifyTOTP(tf.Secret, code, tf.LastCounter, now); ok {
+		err = tf.UseCounter(db, counter)
+		if err == nil {
+			return user, guard.succeeded()
+		}
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+		reason = "replayed two-factor code"
+	} else {
+		used, err := models.UseRecoveryCode(db, user.ID, normalizeRecoveryCode(code), now)
+		if err != nil {
+			return nil, err
+		}
+		if used {
+			return user, guard.succeeded()
+		}
+	}
+
+	if err = guard.failed(reason); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrTwoFactorCode
+}
+
+// newRecoveryCodes - random one-time codes in "xxxxx-xxxxx" form
+func newRecoveryCodes() ([]string, error) {
+	var codes = make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		var buf = make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+
+		code := hex.EncodeToString(buf)
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+
+	return codes, nil
+}
+
+// normalizeRecoveryCode - recovery code in stored form, users may type it without dash or in upper case
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+	if len(code) != 10 {
+		return code
+	}
+
+	return code[:5] + "-" + code[5:]
+}
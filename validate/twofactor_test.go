@@ -0,0 +1,119 @@
+package validate
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"projectionist/config"
+	"projectionist/models"
+)
+
+func TestTotpCode(t *testing.T) {
+	// RFC 6238 test secret "12345678901234567890"
+	var secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, tt := range tests {
+		got, err := totpCode(secret, tt.unix/totpPeriod)
+		if err != nil {
+			t.Fatalf("totpCode() error: %v", err)
+		}
+
+		if got != tt.want {
+			t.Errorf("totpCode() at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestTwoFactor(t *testing.T) {
+	sqlDB, cleanup := newSessionDB(t)
+	defer cleanup()
+
+	var cfg = &config.Config{TokenSecretKey: "secret", Auth: config.AuthCfg{
+		LoginFailures: 5, IPLoginFailures: 20, LoginBackoff: 1, LockoutDuration: 60,
+	}}
+	var user = &models.User{Username: "root", Password: "password", Role: models.SuperAdmin}
+	if err := user.Save(sqlDB); err != nil {
+		t.Fatalf("user Save() error: %v", err)
+	}
+
+	var now = time.Now()
+	uri, err := EnrollTwoFactor(sqlDB, user, now)
+	if err != nil {
+		t.Fatalf("EnrollTwoFactor() error: %v", err)
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "otpauth" {
+		t.Fatalf("provisioning uri %q error: %v", uri, err)
+	}
+	var secret = parsed.Query().Get("secret")
+
+	if enabled, _ := TwoFactorEnabled(sqlDB, user.ID); enabled {
+		t.Errorf("two-factor is enabled before confirmation")
+	}
+
+	code, _ := totpCode(secret, now.Unix()/totpPeriod)
+	recoveryCodes, err := ConfirmTwoFactor(sqlDB, user.ID, code, now)
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactor() error: %v", err)
+	}
+
+	if len(recoveryCodes) != recoveryCodesCount {
+		t.Errorf("count of recovery codes = %d, want %d", len(recoveryCodes), recoveryCodesCount)
+	}
+
+	if _, err = EnrollTwoFactor(sqlDB, user, now); err != ErrTwoFactorEnabled {
+		t.Errorf("EnrollTwoFactor() of enabled user error = %v, want %v", err, ErrTwoFactorEnabled)
+	}
+
+	token, err := NewTwoFactorToken(cfg, user, now)
+	if err != nil {
+		t.Fatalf("NewTwoFactorToken() error: %v", err)
+	}
+
+	if _, err = Authenticate(sqlDB, "Bearer "+token, cfg.TokenSecretKey); err == nil {
+		t.Errorf("two-factor token authorizes requests")
+	}
+
+	// code used at confirmation is not accepted again
+	if _, err = LoginTwoFactor(sqlDB, cfg, token, code, "", now); err != ErrTwoFactorCode {
+		t.Errorf("LoginTwoFactor() with replayed code error = %v, want %v", err, ErrTwoFactorCode)
+	}
+
+	now = now.Add(totpPeriod * time.Second)
+	code, _ = totpCode(secret, now.Unix()/totpPeriod)
+	logged, err := LoginTwoFactor(sqlDB, cfg, token, code, "", now)
+	if err != nil {
+		t.Fatalf("LoginTwoFactor() error: %v", err)
+	}
+
+	if logged.ID != user.ID {
+		t.Errorf("LoginTwoFactor() user = %d, want %d", logged.ID, user.ID)
+	}
+
+	var recovery = strings.ToUpper(strings.Replace(recoveryCodes[0], "-", "", 1))
+	if _, err = LoginTwoFactor(sqlDB, cfg, token, recovery, "", now); err != nil {
+		t.Errorf("LoginTwoFactor() with recovery code error: %v", err)
+	}
+
+	if _, err = LoginTwoFactor(sqlDB, cfg, token, recoveryCodes[0], "", now.Add(time.Minute)); err != ErrTwoFactorCode {
+		t.Errorf("LoginTwoFactor() with used recovery code error = %v, want %v", err, ErrTwoFactorCode)
+	}
+
+	if err = ResetTwoFactor(sqlDB, user, 1, now); err != nil {
+		t.Fatalf("ResetTwoFactor() error: %v", err)
+	}
+
+	if enabled, _ := TwoFactorEnabled(sqlDB, user.ID); enabled {
+		t.Errorf("two-factor is enabled after reset")
+	}
+}
@@ -8,24 +8,28 @@ import (
 )
 
 func ValidateToken(token string, tokenSecretKey string) error {
+	_, err := ParseToken(token, tokenSecretKey)
+	return err
+}
+
+// ParseToken - claims of valid "Bearer <token>" authorization token
+func ParseToken(token string, tokenSecretKey string) (*models.Token, error) {
 	var splitted = strings.Split(token, " ")
 	if len(splitted) != 2 {
-		return fmt.Errorf("invalid/Malformed authorization token")
+		return nil, fmt.Errorf("invalid/Malformed authorization token")
 	}
 
 	var tokenPart = splitted[1]
 	var tokenM = &models.Token{}
 
-	tokenJWT, err := jwt.ParseWithClaims(tokenPart, tokenM, func(token *jwt.Token) (i interface{}, e error) {
-		return []byte(tokenSecretKey), nil
-	})
+	tokenJWT, err := jwt.ParseWithClaims(tokenPart, tokenM, TokenKeyfunc(tokenSecretKey))
 	if err != nil {
-		return fmt.Errorf("malformed authentication token")
+		return nil, fmt.Errorf("malformed authentication token")
 	}
 
 	if !tokenJWT.Valid {
-		return fmt.Errorf("authentication token is not valid")
+		return nil, fmt.Errorf("authentication token is not valid")
 	}
 
-	return nil
+	return tokenM, nil
 }